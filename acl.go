@@ -0,0 +1,132 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Access is the access level of keys, e.g. of keys served to clients
+// of varying trust. Levels are ordered: AccessPublic < AccessInternal <
+// AccessSecret.
+type Access int
+
+const (
+	AccessPublic Access = iota
+	AccessInternal
+	AccessSecret
+)
+
+var access_names = [...]string{
+	AccessPublic:   "public",
+	AccessInternal: "internal",
+	AccessSecret:   "secret",
+}
+
+func (a Access) String() string {
+	if a < 0 || int(a) >= len(access_names) {
+		return fmt.Sprintf("Access(%d)", int(a))
+	}
+	return access_names[a]
+}
+
+// Returns the Access named s (public, internal, or secret).
+func ParseAccess(s string) (Access, error) {
+	for a, name := range access_names {
+		if s == name {
+			return Access(a), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid access level <%s> - allowed values are %s", s, strings.Join(access_names[:], ", "))
+}
+
+// ACL assigns access levels to keys, by key or by key prefix, so that a
+// single store can safely back both privileged (e.g. admin console) and
+// unauthenticated (e.g. health endpoint) views. See ACL#Filter.
+//
+// ACLs are not safe for concurrent modification.
+type ACL struct {
+	def   Access
+	rules map[string]Access // by key, or by prefix (with trailing '*')
+}
+
+// Instantiates a new ACL, with default level def for keys matching
+// no rule.
+func NewACL(def Access) *ACL {
+	return &ACL{def: def, rules: make(map[string]Access)}
+}
+
+// Instantiates a new ACL from the rules of m, mapping patterns to access
+// level names, e.g. the map value of key "acl[:]":
+//
+//	acl[:] = db.*:secret, log.level:public
+//
+// See ACL#Set.
+func ParseACL(def Access, m map[string]string) (*ACL, error) {
+	acl := NewACL(def)
+	for _, pattern := range sortedKeys(m) {
+		a, e := ParseAccess(m[pattern])
+		if e != nil {
+			return nil, &KeyError{pattern, e}
+		}
+		acl.Set(pattern, a)
+	}
+	return acl, nil
+}
+
+// Returns an ACL with level AccessSecret for the Secret keys of s,
+// and level def for all other keys.
+func SchemaACL(s *Schema, def Access) *ACL {
+	acl := NewACL(def)
+	for _, spec := range s.Keys {
+		if spec.Secret {
+			acl.Set(spec.Key, AccessSecret)
+		}
+	}
+	return acl
+}
+
+// Sets the access level of the keys matching pattern, which is either
+// a key, or a key prefix followed by '*' (e.g. "db.*"). The level of a
+// key is per its exact rule, if any, or else per its longest matching
+// prefix rule. Returns the receiver.
+func (acl *ACL) Set(pattern string, a Access) *ACL {
+	acl.rules[pattern] = a
+	return acl
+}
+
+// Returns true if the access level of key is AccessSecret. See RedactedDiff.
+func (acl *ACL) Sensitive(key string) bool {
+	return acl.Level(key) >= AccessSecret
+}
+
+// Returns the access level of key.
+func (acl *ACL) Level(key string) Access {
+	if a, ok := acl.rules[key]; ok {
+		return a
+	}
+	a, matched := acl.def, -1
+	for pattern, pa := range acl.rules {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if ok && len(prefix) > matched && strings.HasPrefix(key, prefix) {
+			a, matched = pa, len(prefix)
+		}
+	}
+	return a
+}
+
+// Returns a view of p of the keys accessible with clearance, i.e. of level
+// at most clearance. The returned object shares the values of p.
+func (acl *ACL) Filter(p Properties, clearance Access) Properties {
+	view := make(Properties)
+	for k, v := range p {
+		if acl.Level(plainKey(k)) <= clearance {
+			view[k] = v
+		}
+	}
+	view.adopt(p, func(k string) (string, bool) { return k, acl.Level(plainKey(k)) <= clearance }, false)
+	return view
+}
@@ -0,0 +1,59 @@
+package gestalt
+
+import (
+	"testing"
+)
+
+func TestACL(t *testing.T) {
+	acl := NewACL(AccessInternal).
+		Set("health.*", AccessPublic).
+		Set("db.*", AccessSecret).
+		Set("db.host", AccessInternal).
+		Set("db.pool.*", AccessInternal)
+
+	for key, expected := range map[string]Access{
+		"health.status": AccessPublic,
+		"db.password":   AccessSecret,
+		"db.host":       AccessInternal,
+		"db.pool.size":  AccessInternal,
+		"app.name":      AccessInternal,
+	} {
+		if a := acl.Level(key); a != expected {
+			t.Errorf("TestACL - Level(%s) - expected: %s, got: %s", key, expected, a)
+		}
+	}
+
+	p := Properties{"health.status": "ok", "db.password": "s3cr3t", "db.host": "localhost", "app.name": "gestalt"}
+	for clearance, expected := range map[Access]int{AccessPublic: 1, AccessInternal: 3, AccessSecret: 4} {
+		if view := acl.Filter(p, clearance); len(view) != expected {
+			t.Errorf("TestACL - Filter(%s) - expected: %d keys, got: %v", clearance, expected, view)
+		}
+	}
+	if view := acl.Filter(p, AccessPublic); view.GetString("health.status") != "ok" {
+		t.Errorf("TestACL - Filter(public) - expected: health.status, got: %v", view)
+	}
+}
+
+func TestParseACL(t *testing.T) {
+	acl, e := ParseACL(AccessPublic, map[string]string{"db.*": "secret", "log.level": "internal"})
+	if e != nil {
+		t.Fatalf("TestParseACL - %s", e)
+	}
+	if a := acl.Level("db.password"); a != AccessSecret {
+		t.Errorf("TestParseACL - Level(db.password) - expected: secret, got: %s", a)
+	}
+	if a := acl.Level("app.name"); a != AccessPublic {
+		t.Errorf("TestParseACL - Level(app.name) - expected: public, got: %s", a)
+	}
+	if _, e := ParseACL(AccessPublic, map[string]string{"db.*": "private"}); e == nil {
+		t.Errorf("TestParseACL - invalid level - error expected")
+	}
+}
+
+func TestSchemaACL(t *testing.T) {
+	schema := &Schema{Keys: []KeySpec{{Key: "db.password", Secret: true}, {Key: "db.host"}}}
+	acl := SchemaACL(schema, AccessPublic)
+	if view := acl.Filter(Properties{"db.password": "s3cr3t", "db.host": "localhost"}, AccessInternal); len(view) != 1 || view.GetString("db.host") != "localhost" {
+		t.Errorf("TestSchemaACL - Filter(internal) - expected: db.host only, got: %v", view)
+	}
+}
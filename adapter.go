@@ -0,0 +1,133 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ----------------------------------------------------------------------
+// Adapters of other configuration libraries (e.g. viper, koanf)
+//
+// these libraries exchange configurations as nested maps, with keys
+// split on a delimiter (conventionally "."), e.g.
+//
+//	db.host = localhost    <=>   {"db": {"host": "localhost"}}
+//
+// the adapters are dependency free. see ToMap, FromMap, and KoanfProvider.
+// ----------------------------------------------------------------------
+
+const map_delim = "."
+
+// Returns the nested map of p, e.g. for viper.MergeConfigMap. Keys are
+// split on ".". Array values are []string, and map values are maps of
+// their (string) values, nested under the key (sans type suffix).
+//
+// Returns error if keys conflict, e.g. "db" and "db.host".
+func (p Properties) ToMap() (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	for _, k := range sortedKeys(p) {
+		var v interface{}
+		switch rv := p.get(k).(type) {
+		case nil:
+			continue
+		case map[string]string:
+			mv := make(map[string]interface{}, len(rv))
+			for mk, s := range rv {
+				mv[mk] = s
+			}
+			k, v = strings.TrimSuffix(k, cmap), mv
+		case []string:
+			k, v = strings.TrimSuffix(k, array), append([]string(nil), rv...)
+		default:
+			v = rv
+		}
+		if e := putNested(m, strings.Split(k, map_delim), v); e != nil {
+			return nil, &KeyError{k, e}
+		}
+	}
+	return m, nil
+}
+
+func putNested(m map[string]interface{}, path []string, v interface{}) error {
+	for i, name := range path[:len(path)-1] {
+		switch nv := m[name].(type) {
+		case nil:
+			nm := make(map[string]interface{})
+			m[name], m = nm, nm
+		case map[string]interface{}:
+			m = nv
+		default:
+			return fmt.Errorf("conflicts with key <%s>", strings.Join(path[:i+1], map_delim))
+		}
+	}
+	name := path[len(path)-1]
+	if _, ok := m[name]; ok {
+		return fmt.Errorf("conflicts with a key of group <%s>", strings.Join(path, map_delim))
+	}
+	m[name] = v
+	return nil
+}
+
+// Instantiates a new Properties object from m, e.g. of viper.AllSettings
+// or koanf.All. Nested maps are flattened, with keys joined with ".".
+// Slices are array values (keys with suffix "[]"), and all other values
+// are strings, per fmt.Sprint. nil values are ignored.
+//
+// Returns error if keys are duplicated, e.g. "db.host" and nested "host"
+// of "db".
+func FromMap(m map[string]interface{}) (Properties, error) {
+	p := make(Properties)
+	if e := p.putFlat("", m); e != nil {
+		return nil, e
+	}
+	return p, nil
+}
+
+func (p Properties) putFlat(prefix string, m map[string]interface{}) error {
+	for _, k := range sortedKeys(m) {
+		key := prefix + k
+		if _, dup := p[key]; dup {
+			return &KeyError{key, fmt.Errorf("duplicate key")}
+		}
+		switch v := m[k].(type) {
+		case nil:
+		case map[string]interface{}:
+			if e := p.putFlat(key+map_delim, v); e != nil {
+				return e
+			}
+		case []string:
+			p[key+array] = append([]string(nil), v...)
+		case []interface{}:
+			arrv := make([]string, len(v))
+			for i, av := range v {
+				arrv[i] = fmt.Sprint(av)
+			}
+			p[key+array] = arrv
+		default:
+			p[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// KoanfProvider is a koanf Provider (koanf.Provider) of Properties, e.g.
+//
+//	k.Load(gestalt.KoanfProvider{p}, nil)
+type KoanfProvider struct {
+	Properties Properties
+}
+
+// Returns errors.ErrUnsupported. Properties are provided as a map. See Read.
+func (kp KoanfProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// Returns the nested map of the Properties. See Properties#ToMap.
+func (kp KoanfProvider) Read() (map[string]interface{}, error) {
+	return kp.Properties.ToMap()
+}
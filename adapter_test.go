@@ -0,0 +1,70 @@
+package gestalt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestToMap(t *testing.T) {
+	p := Properties{
+		"db.host":     "localhost",
+		"db.port":     "5432",
+		"hosts[]":     []string{"a", "b"},
+		"limits[:]":   map[string]string{"cpu": "2"},
+		"app":         "gestalt",
+		"db.password": unsetValue{},
+	}
+	m, e := p.ToMap()
+	if e != nil {
+		t.Fatalf("TestToMap - %s", e)
+	}
+	expected := map[string]interface{}{
+		"app":    "gestalt",
+		"db":     map[string]interface{}{"host": "localhost", "port": "5432"},
+		"hosts":  []string{"a", "b"},
+		"limits": map[string]interface{}{"cpu": "2"},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("TestToMap - expected: %v, got: %v", expected, m)
+	}
+
+	if _, e := (Properties{"db": "x", "db.host": "localhost"}).ToMap(); e == nil {
+		t.Errorf("TestToMap - conflicting keys - error expected")
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	p, e := FromMap(map[string]interface{}{
+		"app": "gestalt",
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": 5432,
+			"ssl":  true,
+		},
+		"hosts":   []interface{}{"a", "b"},
+		"ignored": nil,
+	})
+	if e != nil {
+		t.Fatalf("TestFromMap - %s", e)
+	}
+	expected := Properties{"app": "gestalt", "db.host": "localhost", "db.port": "5432", "db.ssl": "true", "hosts[]": []string{"a", "b"}}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("TestFromMap - expected: %v, got: %v", expected, p)
+	}
+
+	if _, e := FromMap(map[string]interface{}{"db.host": "a", "db": map[string]interface{}{"host": "b"}}); e == nil {
+		t.Errorf("TestFromMap - duplicate keys - error expected")
+	}
+}
+
+func TestKoanfProvider(t *testing.T) {
+	kp := KoanfProvider{Properties{"db.host": "localhost"}}
+	if _, e := kp.ReadBytes(); !errors.Is(e, errors.ErrUnsupported) {
+		t.Errorf("TestKoanfProvider - ReadBytes - expected: ErrUnsupported, got: %v", e)
+	}
+	m, e := kp.Read()
+	if e != nil || m["db"].(map[string]interface{})["host"] != "localhost" {
+		t.Errorf("TestKoanfProvider - Read - expected nested db.host, got: %v, %v", m, e)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// Host and port value property, e.g. `db.addr = db.example.com:5432` -
+// returns error if no such key, or value is not of the form <host>:<port>
+// (IPv6 hosts in brackets, e.g. [::1]:5432), or the host is missing, or the
+// port is not a number in the range 1..65535.
+func (p Properties) GetHostPort(key string) (host string, port int, e error) {
+	s, e := p.stringValue(key)
+	if e != nil {
+		return "", 0, e
+	}
+	hp, e := parseHostPort(s, false)
+	if e != nil {
+		return "", 0, &KeyError{key, e}
+	}
+	return hp.host, hp.port, nil
+}
+
+// Listen address value property, e.g. `server.addr = :8080` - returns the
+// address, suitable for net.Listen. The host is optional (i.e. all interfaces),
+// and the port may be 0 (i.e. any port). Returns error if no such key, or the
+// value is malformed (see GetHostPort), or the host does not resolve.
+func (p Properties) GetListenAddr(key string) (string, error) {
+	s, e := p.stringValue(key)
+	if e != nil {
+		return "", e
+	}
+	hp, e := parseHostPort(s, true)
+	if e != nil {
+		return "", &KeyError{key, e}
+	}
+	addr := net.JoinHostPort(hp.host, strconv.Itoa(hp.port))
+	if _, e := net.ResolveTCPAddr("tcp", addr); e != nil {
+		return "", &KeyError{key, e}
+	}
+	return addr, nil
+}
+
+type hostPort struct {
+	host string
+	port int
+}
+
+// parses s of form <host>:<port>. The host is optional, and the port may
+// be 0, if listen.
+func parseHostPort(s string, listen bool) (hostPort, error) {
+	host, ps, e := net.SplitHostPort(s)
+	if e != nil {
+		return hostPort{}, fmt.Errorf("malformed address <%s> - expected <host>:<port>", s)
+	}
+	port, e := strconv.Atoi(ps)
+	switch {
+	case e != nil && host != empty && isPort(host):
+		return hostPort{}, fmt.Errorf("malformed address <%s> - reversed <port>:<host>?", s)
+	case e != nil:
+		return hostPort{}, fmt.Errorf("malformed address <%s> - port <%s> is not a number", s, ps)
+	case port < 0 || port > 65535 || (port == 0 && !listen):
+		return hostPort{}, fmt.Errorf("malformed address <%s> - port %d out of range", s, port)
+	case host == empty && !listen:
+		return hostPort{}, fmt.Errorf("malformed address <%s> - missing host", s)
+	}
+	return hostPort{host, port}, nil
+}
+
+// returns true if s is a (decimal) port number
+func isPort(s string) bool {
+	n, e := strconv.Atoi(s)
+	return e == nil && n > 0 && n <= 65535
+}
@@ -0,0 +1,54 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetHostPort(t *testing.T) {
+	p := Properties{
+		"db":       "db.example.com:5432",
+		"v6":       "[::1]:6379",
+		"reversed": "5432:db.example.com",
+		"noport":   "db.example.com",
+		"noname":   ":5432",
+		"range":    "db:70000",
+		"zero":     "db:0",
+	}
+	for key, expected := range map[string]hostPort{"db": {"db.example.com", 5432}, "v6": {"::1", 6379}} {
+		if host, port, e := p.GetHostPort(key); e != nil || host != expected.host || port != expected.port {
+			t.Errorf("TestGetHostPort - GetHostPort(%s) - expected: %v, got: %s, %d, %v", key, expected, host, port, e)
+		}
+	}
+	for _, key := range []string{"reversed", "noport", "noname", "range", "zero", "nope"} {
+		if _, _, e := p.GetHostPort(key); e == nil {
+			t.Errorf("TestGetHostPort - GetHostPort(%s) - error expected", key)
+		}
+	}
+	if _, _, e := p.GetHostPort("reversed"); e == nil || !strings.Contains(e.Error(), "reversed") {
+		t.Errorf("TestGetHostPort - GetHostPort(reversed) - expected reversed error, got: %v", e)
+	}
+
+	s := &Schema{Keys: []KeySpec{{Key: "db", Type: TypeHostPort}, {Key: "noport", Type: TypeHostPort}}}
+	if errs := s.Validate(p); len(errs) != 1 || errs[0].(*KeyError).Key != "noport" {
+		t.Errorf("TestGetHostPort - Validate - expected error of key noport, got: %v", errs)
+	}
+}
+
+func TestGetListenAddr(t *testing.T) {
+	p := Properties{"any": ":8080", "local": "127.0.0.1:0", "v6": "[::1]:443", "noport": "127.0.0.1", "bad": "8080"}
+	for key, expected := range map[string]string{"any": ":8080", "local": "127.0.0.1:0", "v6": "[::1]:443"} {
+		if addr, e := p.GetListenAddr(key); e != nil || addr != expected {
+			t.Errorf("TestGetListenAddr - GetListenAddr(%s) - expected: %s, got: %s, %v", key, expected, addr, e)
+		}
+	}
+	for _, key := range []string{"noport", "bad", "nope"} {
+		if _, e := p.GetListenAddr(key); e == nil {
+			t.Errorf("TestGetListenAddr - GetListenAddr(%s) - error expected", key)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ----------------------------------------------------------------------
+// Binary encoding
+//
+// format:  magic | version | count | count * entry
+// entry:   key | kind | value
+//
+// strings are uvarint length prefixed, and counts are uvarints.
+// arrays are count * string and maps count * (string, string) with
+// map entries sorted by key, so that the encoding of a given
+// Properties is stable (e.g. for use as a cache key).
+// ----------------------------------------------------------------------
+
+const (
+	bin_magic   = "GSTL"
+	bin_version = 1
+)
+
+// value kind tags
+const (
+	bin_string byte = iota
+	bin_array
+	bin_map
+	bin_unset
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (p Properties) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(bin_magic)
+	buf.WriteByte(bin_version)
+	var keys []string
+	for _, k := range sortedKeys(p) {
+		if p[k] != nil {
+			keys = append(keys, k)
+		}
+	}
+	putUvarint(&buf, uint64(len(keys)))
+
+	for _, k := range keys {
+		putString(&buf, k)
+		switch v := p[k].(type) {
+		case string:
+			buf.WriteByte(bin_string)
+			putString(&buf, v)
+		case []string:
+			buf.WriteByte(bin_array)
+			putUvarint(&buf, uint64(len(v)))
+			for _, av := range v {
+				putString(&buf, av)
+			}
+		case map[string]string:
+			buf.WriteByte(bin_map)
+			putUvarint(&buf, uint64(len(v)))
+			for _, mk := range sortedKeys(v) {
+				putString(&buf, mk)
+				putString(&buf, v[mk])
+			}
+		case unsetValue:
+			buf.WriteByte(bin_unset)
+		default:
+			return nil, fmt.Errorf("can not encode value of key <%s> - unsupported type %T", k, v)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// Entries are added to the receiver, which is allocated if nil. The
+// windows of windowed keys are per the system clock.
+func (p *Properties) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+
+	magic := make([]byte, len(bin_magic))
+	if _, e := r.Read(magic); e != nil || string(magic) != bin_magic {
+		return errors.New("not a gestalt binary encoding")
+	}
+	version, e := r.ReadByte()
+	if e != nil {
+		return e
+	}
+	if version > bin_version {
+		return fmt.Errorf("unsupported gestalt binary encoding version %d", version)
+	}
+
+	n, e := binary.ReadUvarint(r)
+	if e != nil {
+		return e
+	}
+	if *p == nil {
+		*p = make(Properties)
+	}
+	for i := uint64(0); i < n; i++ {
+		k, e := getString(r)
+		if e != nil {
+			return e
+		}
+		kind, e := r.ReadByte()
+		if e != nil {
+			return e
+		}
+		var v interface{}
+		switch kind {
+		case bin_string:
+			v, e = getString(r)
+		case bin_array:
+			v, e = getArray(r)
+		case bin_map:
+			v, e = getMap(r)
+		case bin_unset:
+			v = unsetValue{}
+		default:
+			e = fmt.Errorf("unknown value kind %d", kind)
+		}
+		if e != nil {
+			return fmt.Errorf("can not decode value of key <%s> - %s", k, e)
+		}
+		(*p)[k] = v
+		if plain, w, ok, e := splitWindow(k); e != nil {
+			return e
+		} else if ok {
+			p.addWindow(plain, w, SystemClock)
+		}
+	}
+	return nil
+}
+
+func putUvarint(buf *bytes.Buffer, n uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], n)])
+}
+
+func putString(buf *bytes.Buffer, s string) {
+	putUvarint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+func getString(r *bytes.Reader) (string, error) {
+	n, e := binary.ReadUvarint(r)
+	if e != nil {
+		return "", e
+	}
+	if n > uint64(r.Len()) {
+		return "", errors.New("truncated string")
+	}
+	b := make([]byte, n)
+	r.Read(b)
+	return string(b), nil
+}
+
+func getArray(r *bytes.Reader) ([]string, error) {
+	n, e := binary.ReadUvarint(r)
+	if e != nil {
+		return nil, e
+	}
+	if n > uint64(r.Len()) {
+		return nil, errors.New("truncated array")
+	}
+	arrv := make([]string, n)
+	for i := range arrv {
+		if arrv[i], e = getString(r); e != nil {
+			return nil, e
+		}
+	}
+	return arrv, nil
+}
+
+func getMap(r *bytes.Reader) (map[string]string, error) {
+	n, e := binary.ReadUvarint(r)
+	if e != nil {
+		return nil, e
+	}
+	if n > uint64(r.Len()) {
+		return nil, errors.New("truncated map")
+	}
+	mapv := make(map[string]string, n)
+	for i := uint64(0); i < n; i++ {
+		mk, e := getString(r)
+		if e != nil {
+			return nil, e
+		}
+		if mapv[mk], e = getString(r); e != nil {
+			return nil, e
+		}
+	}
+	return mapv, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,44 @@
+package gestalt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	prop, e := Load("test/test.conf")
+	if e != nil {
+		t.Errorf("TestBinaryRoundTrip - Load - %s", e)
+	}
+	prop["masked"] = unsetValue{}
+
+	b, e := prop.MarshalBinary()
+	if e != nil {
+		t.Errorf("TestBinaryRoundTrip - MarshalBinary - %s", e)
+	}
+	b2, _ := prop.MarshalBinary()
+	if string(b) != string(b2) {
+		t.Errorf("TestBinaryRoundTrip - MarshalBinary - encoding is not stable")
+	}
+
+	var got Properties
+	if e := got.UnmarshalBinary(b); e != nil {
+		t.Errorf("TestBinaryRoundTrip - UnmarshalBinary - %s", e)
+	}
+	if !reflect.DeepEqual(prop, got) {
+		t.Errorf("TestBinaryRoundTrip - expected: %s, got: %s", prop, got)
+	}
+}
+
+func TestBinaryVersion(t *testing.T) {
+	b, _ := Properties{"a": "b"}.MarshalBinary()
+	b[len(bin_magic)] = bin_version + 1
+
+	var got Properties
+	if e := got.UnmarshalBinary(b); e == nil {
+		t.Errorf("TestBinaryVersion - UnmarshalBinary - error expected for unsupported version")
+	}
+	if e := got.UnmarshalBinary([]byte("junk")); e == nil {
+		t.Errorf("TestBinaryVersion - UnmarshalBinary - error expected for junk")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrNotModified is returned by a BlobGetter if the object is unchanged.
+var ErrNotModified = errors.New("not modified")
+
+// BlobGetter fetches objects from an object store (e.g. S3, GCS). Object
+// store SDKs are adapted to it, and registered per url scheme with
+// RegisterBlobGetter, which keeps heavy SDK dependencies out of gestalt.
+type BlobGetter interface {
+	// returns the content and etag of object key of bucket. If etag is not
+	// "" and matches the object's current etag, returns ErrNotModified.
+	GetBlob(ctx context.Context, bucket, key, etag string) (content []byte, newEtag string, e error)
+}
+
+// LimitedBlobGetter is a BlobGetter that reads at most limit bytes of the
+// content of objects (e.g. with io.LimitReader), so that the content of
+// objects exceeding MaxContentSize is not read into memory. Sources of
+// BlobGetters that are LimitedBlobGetters call GetBlobLimit with limit
+// one byte over the maximum content size.
+type LimitedBlobGetter interface {
+	BlobGetter
+	// as GetBlob, but returns at most limit bytes of content
+	GetBlobLimit(ctx context.Context, bucket, key, etag string, limit int64) (content []byte, newEtag string, e error)
+}
+
+var blobGetters = struct {
+	sync.RWMutex
+	m map[string]BlobGetter
+}{m: make(map[string]BlobGetter)}
+
+// Registers the BlobGetter of url scheme, e.g. "s3" or "gs".
+func RegisterBlobGetter(scheme string, g BlobGetter) {
+	blobGetters.Lock()
+	defer blobGetters.Unlock()
+	blobGetters.m[scheme] = g
+}
+
+// Instantiates a new Properties object from the object at the specified
+// url, e.g. s3://bucket/path/to/app.conf. See RegisterBlobGetter.
+func LoadBlob(ctx context.Context, rawurl string, opts ...LoadOption) (Properties, error) {
+	src, e := BlobSource(rawurl, opts...)
+	if e != nil {
+		return nil, e
+	}
+	return src.Load(ctx)
+}
+
+// Returns a Source loading the object at the specified url. Reloads are
+// conditional on the object's etag, and unmodified objects are not re-parsed.
+func BlobSource(rawurl string, opts ...LoadOption) (Source, error) {
+	u, e := url.Parse(rawurl)
+	if e != nil {
+		return nil, e
+	}
+	blobGetters.RLock()
+	g := blobGetters.m[u.Scheme]
+	blobGetters.RUnlock()
+	if g == nil {
+		return nil, fmt.Errorf("no BlobGetter registered for url scheme <%s>", u.Scheme)
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" {
+		return nil, fmt.Errorf("url <%s> must be of form <scheme>://<bucket>/<key>", rawurl)
+	}
+	return &blobSource{getter: g, bucket: u.Host, key: key, opts: opts}, nil
+}
+
+type blobSource struct {
+	getter      BlobGetter
+	bucket, key string
+	opts        []LoadOption
+
+	mu     sync.Mutex
+	etag   string
+	cached Properties
+}
+
+func (s *blobSource) Load(ctx context.Context) (Properties, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var content []byte
+	var etag string
+	var e error
+	if lg, ok := s.getter.(LimitedBlobGetter); ok && s.maxSize() > 0 {
+		limit := int64(s.maxSize()) + 1
+		content, etag, e = lg.GetBlobLimit(ctx, s.bucket, s.key, s.etag, limit)
+	} else {
+		content, etag, e = s.getter.GetBlob(ctx, s.bucket, s.key, s.etag)
+	}
+	if e == ErrNotModified && s.cached != nil {
+		return s.cached, nil
+	}
+	if e != nil {
+		return nil, fmt.Errorf("Error reading gestalt object <%s/%s> : %s", s.bucket, s.key, e)
+	}
+	p, e := LoadStr(string(content), s.opts...)
+	if e != nil {
+		return nil, e
+	}
+	s.etag, s.cached = etag, p
+	return p, nil
+}
+
+// returns the maximum content size of the source's options
+func (s *blobSource) maxSize() int { return newLoadOptions(s.opts).maxSize }
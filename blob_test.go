@@ -0,0 +1,60 @@
+package gestalt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// an in-memory BlobGetter
+type fakeBlobs struct {
+	mu      sync.Mutex
+	objects map[string]string
+	gets    int
+}
+
+func (b *fakeBlobs) GetBlob(ctx context.Context, bucket, key, etag string) ([]byte, string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.gets++
+	content, ok := b.objects[bucket+"/"+key]
+	if !ok {
+		return nil, "", fmt.Errorf("no such object")
+	}
+	newEtag := fmt.Sprintf("%x", len(content))
+	if etag == newEtag {
+		return nil, "", ErrNotModified
+	}
+	return []byte(content), newEtag, nil
+}
+
+func TestBlobSource(t *testing.T) {
+	blobs := &fakeBlobs{objects: map[string]string{"configs/app.conf": "foo = bar\n"}}
+	RegisterBlobGetter("s3", blobs)
+
+	src, e := BlobSource("s3://configs/app.conf")
+	if e != nil {
+		t.Fatalf("TestBlobSource - BlobSource - %s", e)
+	}
+	p1, e := src.Load(context.Background())
+	if e != nil || p1.GetString("foo") != "bar" {
+		t.Fatalf("TestBlobSource - Load - expected: foo=bar, got: %s (%v)", p1, e)
+	}
+	p2, e := src.Load(context.Background())
+	if e != nil || fmt.Sprintf("%p", p1) != fmt.Sprintf("%p", p2) {
+		t.Errorf("TestBlobSource - Load - expected cached Properties for unmodified object")
+	}
+
+	blobs.objects["configs/app.conf"] = "foo = changed\n"
+	if p, e := src.Load(context.Background()); e != nil || p.GetString("foo") != "changed" {
+		t.Errorf("TestBlobSource - Load - expected: foo=changed, got: %s (%v)", p, e)
+	}
+
+	if _, e := LoadBlob(context.Background(), "gs://configs/app.conf"); e == nil {
+		t.Errorf("TestBlobSource - LoadBlob - error expected for unregistered scheme")
+	}
+	if _, e := BlobSource("s3://configs"); e == nil {
+		t.Errorf("TestBlobSource - BlobSource - error expected for missing key")
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reuse blocks and repetition
+//
+// a block of keys is defined once by @define, and instantiated under
+// any number of key prefixes by @use, e.g.
+//
+//	@define pool
+//	  timeout = 5s
+//	  size = 10
+//	@end
+//
+//	@use pool as db.primary        # => db.primary.timeout, db.primary.size
+//	@use pool as db.replica
+//	db.replica.size = 20           # keys of a use may be redefined
+//
+// Blocks must be defined before use, and may use blocks defined before
+// them.
+//
+// @foreach repeats a template of keys per element of a list, with the
+// references {{<name>}} replaced by the element, e.g.
+//
+//	@foreach shard in a, b, c
+//	  shard.{{shard}}.host = {{shard}}.db.example.com
+//	  @use pool as shard.{{shard}}.pool
+//	@end
+//
+// defines shard.a.host, shard.b.host, etc. Repetitions may be nested.
+// ----------------------------------------------------------------------
+
+const (
+	define_directive  = "@define"
+	use_directive     = "@use"
+	foreach_directive = "@foreach"
+	end_directive     = "@end"
+	use_as            = " as "
+	foreach_in        = " in "
+	var_open          = "{{"
+	var_close         = "}}"
+)
+
+// a directive (@define or @foreach) pending its @end
+type directiveFrame struct {
+	directive string
+	name      string   // of the block, or the variable
+	elements  []string // of the foreach list
+	specs     []string
+}
+
+// returns specs with the blocks of define directives instantiated per
+// the use directives, and foreach directives expanded.
+func expandBlocks(specs []string) ([]string, error) {
+	if !hasDirectives(specs) {
+		return specs, nil
+	}
+	var out []string
+	var stack []*directiveFrame
+	blocks := make(map[string][]string)
+	emit := func(spec string) {
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			top.specs = append(top.specs, spec)
+		} else {
+			out = append(out, spec)
+		}
+	}
+	for _, spec := range specs {
+		tspec := strings.Trim(spec, trimset)
+		if !strings.HasPrefix(tspec, "@") {
+			emit(spec)
+			continue
+		}
+		directive, arg, _ := strings.Cut(tspec, " ")
+		arg = strings.Trim(arg, ws)
+		switch directive {
+		case define_directive:
+			if len(stack) > 0 {
+				return nil, fmt.Errorf("block <%s> - nested %s", arg, define_directive)
+			}
+			if arg == empty || strings.ContainsAny(arg, ws) {
+				return nil, fmt.Errorf("malformed directive '%s'", tspec)
+			}
+			if _, dup := blocks[arg]; dup {
+				return nil, fmt.Errorf("duplicate block <%s>", arg)
+			}
+			stack = append(stack, &directiveFrame{directive: directive, name: arg})
+		case foreach_directive:
+			name, list, ok := strings.Cut(arg, foreach_in)
+			name = strings.Trim(name, ws)
+			if !ok || name == empty || strings.ContainsAny(name, ws) {
+				return nil, fmt.Errorf("malformed directive '%s' - expected %s <name> in <list>", tspec, foreach_directive)
+			}
+			elements := strings.Split(list, val_delim)
+			for i, e := range elements {
+				if elements[i] = strings.Trim(e, ws); elements[i] == empty {
+					return nil, fmt.Errorf("malformed directive '%s' - empty element %d", tspec, i)
+				}
+			}
+			stack = append(stack, &directiveFrame{directive: directive, name: name, elements: elements})
+		case end_directive:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%s without %s or %s", end_directive, define_directive, foreach_directive)
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if top.directive == define_directive {
+				blocks[top.name] = top.specs
+				continue
+			}
+			ref := var_open + top.name + var_close
+			for _, e := range top.elements {
+				for _, tspec := range top.specs {
+					emit(strings.ReplaceAll(tspec, ref, e))
+				}
+			}
+		case use_directive:
+			bname, prefix, ok := strings.Cut(arg, use_as)
+			bname, prefix = strings.Trim(bname, ws), strings.Trim(prefix, ws)
+			if !ok || bname == empty || prefix == empty {
+				return nil, fmt.Errorf("malformed directive '%s' - expected %s <block> as <prefix>", tspec, use_directive)
+			}
+			b, ok := blocks[bname]
+			if !ok {
+				return nil, fmt.Errorf("undefined block <%s>", bname)
+			}
+			for _, bspec := range b {
+				if bspec = strings.Trim(bspec, trimset); bspec != empty {
+					emit(prefix + "." + bspec)
+				}
+			}
+		default:
+			emit(spec)
+		}
+	}
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return nil, fmt.Errorf("%s <%s> - missing %s", top.directive, top.name, end_directive)
+	}
+	return out, nil
+}
+
+// returns true if any of specs is a directive
+func hasDirectives(specs []string) bool {
+	for _, spec := range specs {
+		if strings.HasPrefix(strings.TrimLeft(spec, trimset), "@") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBlocks(t *testing.T) {
+	p, e := LoadStr(`
+@define pool                   # shared pool settings
+  timeout = 5s
+  tags[] = a, b
+@end
+
+@define db
+  @use pool as pool
+  driver = postgres
+@end
+
+@use pool as db.primary
+@use db as db.replica
+db.replica.pool.timeout = 10s
+`)
+	if e != nil {
+		t.Fatalf("TestBlocks - LoadStr - unexpected error: %s", e)
+	}
+	expected := Properties{
+		"db.primary.timeout":      "5s",
+		"db.primary.tags[]":       []string{"a", "b"},
+		"db.replica.pool.timeout": "10s",
+		"db.replica.pool.tags[]":  []string{"a", "b"},
+		"db.replica.driver":       "postgres",
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("TestBlocks - expected: %v, got: %v", expected, p)
+	}
+
+	for _, spec := range []string{
+		"@use pool as x\n",
+		"@define pool\na = b\n",
+		"@define pool\n@define other\n@end\n@end\n",
+		"@end\n",
+		"@define pool\na = b\n@end\n@define pool\n@end\n",
+		"@define pool\na = b\n@end\n@use pool\n",
+	} {
+		if _, e := LoadStr(spec); e == nil {
+			t.Errorf("TestBlocks - LoadStr(%q) - error expected", spec)
+		}
+	}
+}
+
+func TestForeach(t *testing.T) {
+	p, e := LoadStr(`
+@define pool
+  size = 10
+@end
+
+@foreach shard in a, b
+  shard.{{shard}}.host = {{shard}}.db.example.com
+  @use pool as shard.{{shard}}.pool
+  @foreach replica in 1,2
+    shard.{{shard}}.replica.{{replica}} = {{shard}}{{replica}}.db.example.com
+  @end
+@end
+shard.b.pool.size = 20
+`)
+	if e != nil {
+		t.Fatalf("TestForeach - LoadStr - unexpected error: %s", e)
+	}
+	expected := Properties{
+		"shard.a.host":      "a.db.example.com",
+		"shard.b.host":      "b.db.example.com",
+		"shard.a.pool.size": "10",
+		"shard.b.pool.size": "20",
+		"shard.a.replica.1": "a1.db.example.com",
+		"shard.a.replica.2": "a2.db.example.com",
+		"shard.b.replica.1": "b1.db.example.com",
+		"shard.b.replica.2": "b2.db.example.com",
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("TestForeach - expected: %v, got: %v", expected, p)
+	}
+
+	for _, spec := range []string{
+		"@foreach shard in a, b\nx.{{shard}} = 1\n",
+		"@foreach shard a, b\nx = 1\n@end\n",
+		"@foreach shard in a,,b\nx = 1\n@end\n",
+		"@foreach shard in a\n@define pool\n@end\n@end\n",
+	} {
+		if _, e := LoadStr(spec); e == nil {
+			t.Errorf("TestForeach - LoadStr(%q) - error expected", spec)
+		}
+	}
+}
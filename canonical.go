@@ -0,0 +1,58 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// kind of @unset keys of canonical documents. See MarshalCanonical.
+const KindUnset = "unset"
+
+// canonical document (see MarshalCanonical)
+type canonicalDoc struct {
+	Provenance map[string]string         `json:"provenance,omitempty"`
+	Keys       map[string]canonicalValue `json:"keys"`
+}
+
+type canonicalValue struct {
+	Kind  string      `json:"kind"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Returns the canonical JSON document of the receiver, with provenance
+// metadata prov (e.g. source file, environment, version), if not nil,
+// for audit storage and comparison (e.g. across environments). For
+// example:
+//
+//	{"provenance":{"source":"app.conf"},"keys":{
+//	 "db.host":{"kind":"string","value":"localhost"},
+//	 "db.replicas[]":{"kind":"array","value":["a","b"]},
+//	 "db.password":{"kind":"unset"}}}
+//
+// (shown wrapped). The document is compact, with object members sorted
+// by name, so that equal Properties (and metadata) have identical
+// documents. Unlike MarshalJSON, @unset keys are included.
+func (p Properties) MarshalCanonical(prov map[string]string) ([]byte, error) {
+	doc := canonicalDoc{Provenance: prov, Keys: make(map[string]canonicalValue, len(p))}
+	for k, v := range p {
+		switch {
+		case v == nil:
+		case isUnset(v):
+			doc.Keys[k] = canonicalValue{Kind: KindUnset}
+		default:
+			doc.Keys[k] = canonicalValue{Kind: keyKind(k), Value: v}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if e := enc.Encode(doc); e != nil {
+		return nil, e
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,35 @@
+package gestalt
+
+import (
+	"testing"
+)
+
+func TestMarshalCanonical(t *testing.T) {
+	p := Properties{
+		"db.host":       "localhost",
+		"db.replicas[]": []string{"a", "b"},
+		"limits[:]":     map[string]string{"mem": "1G", "cpu": "2"},
+		"db.password":   unsetValue{},
+		"q":             "<a&b>",
+	}
+	expected := `{"provenance":{"env":"prod","source":"app.conf"},"keys":{` +
+		`"db.host":{"kind":"string","value":"localhost"},` +
+		`"db.password":{"kind":"unset"},` +
+		`"db.replicas[]":{"kind":"array","value":["a","b"]},` +
+		`"limits[:]":{"kind":"map","value":{"cpu":"2","mem":"1G"}},` +
+		`"q":{"kind":"string","value":"<a&b>"}}}`
+	for i := 0; i < 3; i++ {
+		b, e := p.MarshalCanonical(map[string]string{"source": "app.conf", "env": "prod"})
+		if e != nil {
+			t.Fatalf("TestMarshalCanonical - %s", e)
+		}
+		if string(b) != expected {
+			t.Fatalf("TestMarshalCanonical - expected:\n%s\ngot:\n%s", expected, b)
+		}
+	}
+
+	b, e := Properties{"a": ""}.MarshalCanonical(nil)
+	if expected := `{"keys":{"a":{"kind":"string","value":""}}}`; e != nil || string(b) != expected {
+		t.Errorf("TestMarshalCanonical - nil provenance - expected: %s, got: %s, %v", expected, b, e)
+	}
+}
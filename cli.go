@@ -0,0 +1,123 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"flag"
+)
+
+// FlagBinder binds command line flags (and environment variables) to keys,
+// and loads the configuration file given by a -config flag, with precedence
+// flag > environment > file. For example:
+//
+//	fs := flag.NewFlagSet("app", flag.ExitOnError)
+//	b := gestalt.BindFlags(fs, "app.conf", "APP_").
+//		Bind("db.host", "db-host", "database host").
+//		Bind("db.port", "db-port", "database port")
+//	fs.Parse(os.Args[1:])
+//	p, e := b.Load()
+//
+// With spf13/cobra, the flags are added to the persistent flags of the
+// command, and the configuration is loaded before the command runs:
+//
+//	cmd.PersistentFlags().AddGoFlagSet(fs)
+//	cmd.PersistentPreRunE = func(*cobra.Command, []string) (e error) {
+//		p, e = b.Load()
+//		return
+//	}
+type FlagBinder struct {
+	fs        *flag.FlagSet
+	config    *flagValue
+	envPrefix string
+	bindings  []flagBinding
+}
+
+type flagBinding struct {
+	key  string
+	flag *flagValue
+}
+
+// flagValue is a flag.Value recording whether it is set, also if set
+// by other flag packages (e.g. pflag) adapting it.
+type flagValue struct {
+	v   string
+	set bool
+}
+
+func (fv *flagValue) String() string {
+	if fv == nil {
+		return ""
+	}
+	return fv.v
+}
+
+func (fv *flagValue) Set(s string) error {
+	fv.v, fv.set = s, true
+	return nil
+}
+
+// Instantiates a new FlagBinder of fs, defining flag -config (of the
+// configuration file) with default defaultConfig. An empty configuration
+// file name loads no file. The environment variables of keys are named
+// per EnvName, prefixed with envPrefix (e.g. APP_DB_HOST of "db.host").
+func BindFlags(fs *flag.FlagSet, defaultConfig string, envPrefix string) *FlagBinder {
+	b := &FlagBinder{fs: fs, config: &flagValue{v: defaultConfig}, envPrefix: envPrefix}
+	fs.Var(b.config, "config", "configuration file")
+	return b
+}
+
+// Defines flag name (with usage) of key. The flag value is given
+// per file syntax, e.g. "a, b" for array keys. Returns the receiver.
+func (b *FlagBinder) Bind(key, name, usage string) *FlagBinder {
+	fv := &flagValue{}
+	b.fs.Var(fv, name, usage)
+	b.bindings = append(b.bindings, flagBinding{key, fv})
+	return b
+}
+
+// Loads the configuration file (if any), overridden by the environment
+// variables of its keys and of bound keys, and by set flags, in that
+// order. Must be called after the flags are parsed.
+//
+// Returns error if the file can not be loaded, or an overriding value is
+// malformed.
+func (b *FlagBinder) Load(opts ...LoadOption) (p Properties, e error) {
+	p = make(Properties)
+	if b.config.v != "" {
+		if p, e = Load(b.config.v, opts...); e != nil {
+			return nil, e
+		}
+	}
+
+	keys := sortedKeys(p)
+	for _, fb := range b.bindings {
+		keys = append(keys, fb.key)
+	}
+	for _, k := range keys {
+		if vrep, ok := lookupEnv(b.envPrefix + EnvName(k)); ok {
+			if e := p.override(k, vrep); e != nil {
+				return nil, e
+			}
+		}
+	}
+	for _, fb := range b.bindings {
+		if fb.flag.set {
+			if e := p.override(fb.key, fb.flag.v); e != nil {
+				return nil, e
+			}
+		}
+	}
+	return p, nil
+}
+
+// sets key to the value of vrep (per file syntax)
+func (p Properties) override(key, vrep string) error {
+	v, e := parseValue(key, vrep)
+	if e != nil {
+		return e
+	}
+	p[key] = v
+	return nil
+}
@@ -0,0 +1,53 @@
+package gestalt
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestFlagBinder(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := BindFlags(fs, "test/test.conf", "GESTALT_TEST_").
+		Bind("db.host", "db-host", "database host").
+		Bind("db.port", "db-port", "database port").
+		Bind("db.hosts[]", "db-hosts", "database hosts")
+
+	t.Setenv("GESTALT_TEST_DB_HOST", "env.host")
+	t.Setenv("GESTALT_TEST_DB_PORT", "5432")
+	t.Setenv("GESTALT_TEST_PROP_ONE", "env value")
+	if e := fs.Parse([]string{"-db-host", "flag.host", "-db-hosts", "a, b"}); e != nil {
+		t.Fatalf("TestFlagBinder - Parse - %s", e)
+	}
+	p, e := b.Load()
+	if e != nil {
+		t.Fatalf("TestFlagBinder - Load - %s", e)
+	}
+	for key, expected := range map[string]string{
+		"db.host":          "flag.host",
+		"db.port":          "5432",
+		"prop one":         "env value",
+		"another property": "value",
+	} {
+		if v := p.GetString(key); v != expected {
+			t.Errorf("TestFlagBinder - GetString(%s) - expected: %s, got: %s", key, expected, v)
+		}
+	}
+	if v := p.GetArray("db.hosts[]"); len(v) != 2 || v[1] != "b" {
+		t.Errorf("TestFlagBinder - GetArray(db.hosts[]) - expected: [a b], got: %v", v)
+	}
+}
+
+func TestFlagBinderConfig(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := BindFlags(fs, "", "")
+	if p, e := b.Load(); e != nil || len(p) != 0 {
+		t.Errorf("TestFlagBinderConfig - no config - expected empty Properties, got: %v, %v", p, e)
+	}
+	fs.Parse([]string{"-config", "test/no-such-file.conf"})
+	if _, e := b.Load(); e == nil {
+		t.Errorf("TestFlagBinderConfig - missing config - error expected")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time, and tickers, e.g. of time-windowed
+// values and Watcher polling. See SystemClock and ManualClock.
+type Clock interface {
+	Now() time.Time
+	// Returns a channel delivering ticks every d (see time.Ticker), and
+	// a function stopping the ticks.
+	NewTicker(d time.Duration) (<-chan time.Time, func())
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+// SystemClock is the Clock of the system (time.Now).
+var SystemClock Clock = systemClock{}
+
+// WithClock sets the Clock of time-windowed values, e.g. for tests.
+// nil c is the SystemClock.
+func WithClock(c Clock) LoadOption {
+	return func(o *loadOptions) {
+		o.clock = c
+	}
+}
+
+// ManualClock is a Clock that is advanced explicitly, for deterministic
+// tests of time dependent behavior, without sleeps.
+//
+// ManualClock is safe for concurrent use.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers map[*manualTicker]struct{}
+}
+
+type manualTicker struct {
+	c    chan time.Time
+	d    time.Duration
+	next time.Time
+}
+
+// Instantiates a new ManualClock set to now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now, tickers: make(map[*manualTicker]struct{})}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTicker{c: make(chan time.Time, 1), d: d, next: c.now.Add(d)}
+	c.tickers[t] = struct{}{}
+	return t.c, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.tickers, t)
+	}
+}
+
+// Advances the clock by d, delivering the ticks due. As with time.Ticker,
+// ticks are dropped for slow receivers.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for t := range c.tickers {
+		for !t.next.After(c.now) {
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.d)
+		}
+	}
+}
+
+// Sets the clock to now, per Advance.
+func (c *ManualClock) Set(now time.Time) {
+	c.Advance(now.Sub(c.Now()))
+}
@@ -0,0 +1,51 @@
+package gestalt
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(start)
+	tick, stop := c.NewTicker(time.Minute)
+	defer stop()
+
+	c.Advance(30 * time.Second)
+	select {
+	case <-tick:
+		t.Fatalf("TestManualClock - Advance(30s) - unexpected tick")
+	default:
+	}
+	c.Advance(30 * time.Second)
+	if ts := <-tick; !ts.Equal(start.Add(time.Minute)) {
+		t.Errorf("TestManualClock - Advance(1m) - expected tick at: %s, got: %s", start.Add(time.Minute), ts)
+	}
+	if now := c.Now(); !now.Equal(start.Add(time.Minute)) {
+		t.Errorf("TestManualClock - Now() - expected: %s, got: %s", start.Add(time.Minute), now)
+	}
+}
+
+func TestWatchManualClock(t *testing.T) {
+	c := NewManualClock(time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC))
+	var n int32
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		return Properties{"n": string(rune('0' + atomic.AddInt32(&n, 1)))}, nil
+	})
+	changed := make(chan Properties, 1)
+	w, e := Watch(context.Background(), src, time.Minute, func(p Properties, e error) { changed <- p }, WatchClock(c))
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer w.Stop()
+
+	c.Advance(time.Minute)
+	if p := <-changed; p.GetString("n") != "2" {
+		t.Errorf("TestWatchManualClock - poll - expected: n = 2, got: %v", p)
+	}
+	if v := w.Version(); v.N != 2 || !v.Time.Equal(c.Now()) {
+		t.Errorf("TestWatchManualClock - Version() - expected: 2 at %s, got: %v", c.Now(), v)
+	}
+}
@@ -0,0 +1,414 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command gestalt provides tooling for gestalt property files.
+//
+// Usage:
+//
+//	gestalt <command> [arguments]
+//
+// Commands:
+//
+//	validate [-schema file] files...
+//		validates files, and writes a JSON report to stdout.
+//		exit status is 1 if any file is invalid.
+//
+//	convert [-from format] -to format [file]
+//		converts file (or stdin) to the specified format, written to stdout.
+//		input formats are gestalt, properties, json, and dotenv, and output
+//		formats are these, yaml, and toml (yaml and toml are output only).
+//
+//	init -schema file [-i]
+//		writes a commented starter configuration file for schema to stdout.
+//		with -i, values are prompted for on the terminal.
+//
+//	keys [-schema file] [--complete [-prefix p]] files...
+//		lists the keys of files (and schema) with their kind, type, and doc.
+//		with --complete, writes `key=` completion words, one per line, e.g.
+//
+//			COMPREPLY=($(gestalt keys --complete -prefix "$cur" app.conf))
+//
+//	gen [-schema file] [-pkg name] [files...]
+//		generates a Go package (source written to stdout) of typed access
+//		to the keys of schema, or of the keys of the sample files.
+//
+//	consts [-pkg name] files...
+//		writes a Go file of key name constants of the keys of files to stdout.
+//
+//	refs [-schema file] [-src dir] files...
+//		cross-checks the keys referenced in the Go source of dir (default .)
+//		against the keys of files (and schema), and reports keys read but
+//		never defined, and keys defined but never read. exit status is 1
+//		if any are reported.
+//
+//	migrate -rename old=new... [-w] files...
+//		renames keys of files (e.g. "hosts=hosts[]" also changes the type
+//		of key hosts), retaining comments, and writes the migrated files to
+//		stdout, or with -w, in place.
+//
+//	dump [-resolved] [-schema file] files...
+//		writes the merged keys of files (later files take precedence) with
+//		their values, and the file defining them, as JSON lines sorted by
+//		key, e.g. for diffs of environments. with -resolved, references
+//		(including ${env:NAME}) are expanded, and values of ${env:NAME}
+//		references are redacted, unless described by schema. values of keys
+//		described by schema are typed, and secret values are redacted.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alphazero/gestalt"
+	"github.com/alphazero/gestalt/keyrefs"
+)
+
+// commands by name. a command returns the process exit status.
+var commands = map[string]func(args []string) int{
+	"validate": validate,
+	"convert":  convert,
+	"init":     initConf,
+	"keys":     keys,
+	"gen":      gen,
+	"consts":   consts,
+	"refs":     refs,
+	"migrate":  migrate,
+	"dump":     dump,
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		usage()
+		os.Exit(2)
+	}
+	os.Exit(commands[os.Args[1]](os.Args[2:]))
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gestalt <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  validate [-schema file] files...")
+	fmt.Fprintln(os.Stderr, "  convert [-from format] -to format [file]")
+	fmt.Fprintln(os.Stderr, "  init -schema file [-i]")
+	fmt.Fprintln(os.Stderr, "  keys [-schema file] [--complete [-prefix p]] files...")
+	fmt.Fprintln(os.Stderr, "  gen [-schema file] [-pkg name] [files...]")
+	fmt.Fprintln(os.Stderr, "  consts [-pkg name] files...")
+	fmt.Fprintln(os.Stderr, "  refs [-schema file] [-src dir] files...")
+	fmt.Fprintln(os.Stderr, "  migrate -rename old=new... [-w] files...")
+	fmt.Fprintln(os.Stderr, "  dump [-resolved] [-schema file] files...")
+}
+
+// prints error to stderr and returns exit status 1
+func fail(e error) int {
+	fmt.Fprintf(os.Stderr, "gestalt: %s\n", e)
+	return 1
+}
+
+func validate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	schemaFile := flags.String("schema", "", "schema file")
+	flags.Parse(args)
+
+	var schema *gestalt.Schema
+	if *schemaFile != "" {
+		var e error
+		if schema, e = gestalt.LoadSchema(*schemaFile); e != nil {
+			return fail(e)
+		}
+	}
+
+	report := gestalt.ValidateFiles(flags.Args(), schema)
+	b, e := report.JSON()
+	if e != nil {
+		return fail(e)
+	}
+	fmt.Println(string(b))
+	if !report.OK {
+		return 1
+	}
+	return 0
+}
+
+func convert(args []string) int {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	from := flags.String("from", string(gestalt.FormatGestalt), "input format (gestalt, properties, json, or dotenv)")
+	to := flags.String("to", "", "output format")
+	flags.Parse(args)
+
+	switch gestalt.Format(*from) {
+	case gestalt.FormatYAML, gestalt.FormatTOML:
+		return fail(fmt.Errorf("-from %s is not supported - yaml and toml are output only", *from))
+	}
+
+	var r io.Reader = os.Stdin
+	if flags.NArg() > 0 {
+		f, e := os.Open(flags.Arg(0))
+		if e != nil {
+			return fail(e)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if e := gestalt.Convert(r, gestalt.Format(*from), gestalt.Format(*to), os.Stdout); e != nil {
+		return fail(e)
+	}
+	return 0
+}
+
+func initConf(args []string) int {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	schemaFile := flags.String("schema", "", "schema file")
+	interactive := flags.Bool("i", false, "prompt for values")
+	flags.Parse(args)
+
+	if *schemaFile == "" {
+		return fail(fmt.Errorf("init requires -schema"))
+	}
+	schema, e := gestalt.LoadSchema(*schemaFile)
+	if e != nil {
+		return fail(e)
+	}
+
+	var pr gestalt.Prompter
+	if *interactive {
+		if pr = gestalt.TTYPrompter(); pr == nil {
+			return fail(fmt.Errorf("-i requires a terminal"))
+		}
+	}
+	if e := schema.WriteStarter(os.Stdout, pr); e != nil {
+		return fail(e)
+	}
+	return 0
+}
+
+func keys(args []string) int {
+	flags := flag.NewFlagSet("keys", flag.ExitOnError)
+	schemaFile := flags.String("schema", "", "schema file")
+	complete := flags.Bool("complete", false, "write completion words")
+	prefix := flags.String("prefix", "", "prefix of completed keys")
+	flags.Parse(args)
+
+	var schema *gestalt.Schema
+	if *schemaFile != "" {
+		var e error
+		if schema, e = gestalt.LoadSchema(*schemaFile); e != nil {
+			return fail(e)
+		}
+	}
+	p, e := merged(flags.Args())
+	if e != nil {
+		return fail(e)
+	}
+
+	if *complete {
+		for _, word := range gestalt.Completions(p, schema, *prefix) {
+			fmt.Println(word)
+		}
+		return 0
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, info := range gestalt.KeyInfos(p, schema) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.Key, info.Kind, info.Type, info.Doc)
+	}
+	if e := w.Flush(); e != nil {
+		return fail(e)
+	}
+	return 0
+}
+
+func gen(args []string) int {
+	flags := flag.NewFlagSet("gen", flag.ExitOnError)
+	schemaFile := flags.String("schema", "", "schema file")
+	pkg := flags.String("pkg", "config", "name of the generated package")
+	flags.Parse(args)
+
+	schema := &gestalt.Schema{}
+	if *schemaFile != "" {
+		var e error
+		if schema, e = gestalt.LoadSchema(*schemaFile); e != nil {
+			return fail(e)
+		}
+	} else {
+		p, e := merged(flags.Args())
+		if e != nil {
+			return fail(e)
+		}
+		for _, key := range p.Keys() {
+			schema.Keys = append(schema.Keys, gestalt.KeySpec{Key: key, Type: gestalt.TypeString})
+		}
+	}
+	if len(schema.Keys) == 0 {
+		return fail(fmt.Errorf("gen requires -schema or sample files"))
+	}
+	if e := schema.GenerateGo(os.Stdout, *pkg); e != nil {
+		return fail(e)
+	}
+	return 0
+}
+
+func consts(args []string) int {
+	flags := flag.NewFlagSet("consts", flag.ExitOnError)
+	pkg := flags.String("pkg", "config", "name of the generated package")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return fail(fmt.Errorf("consts requires files"))
+	}
+	p, e := merged(flags.Args())
+	if e != nil {
+		return fail(e)
+	}
+	if e := gestalt.WriteKeyConsts(os.Stdout, *pkg, p); e != nil {
+		return fail(e)
+	}
+	return 0
+}
+
+func refs(args []string) int {
+	flags := flag.NewFlagSet("refs", flag.ExitOnError)
+	schemaFile := flags.String("schema", "", "schema file")
+	src := flags.String("src", ".", "Go source directory")
+	flags.Parse(args)
+
+	p, e := merged(flags.Args())
+	if e != nil {
+		return fail(e)
+	}
+	defined := p.Keys()
+	if *schemaFile != "" {
+		schema, e := gestalt.LoadSchema(*schemaFile)
+		if e != nil {
+			return fail(e)
+		}
+		for _, spec := range schema.Keys {
+			if !p.Has(spec.Key) {
+				defined = append(defined, spec.Key)
+			}
+		}
+	}
+	found, e := keyrefs.DirRefs(*src)
+	if e != nil {
+		return fail(e)
+	}
+
+	report := keyrefs.Check(found, defined)
+	for _, ref := range report.Undefined {
+		fmt.Printf("%s: key <%s> is read but not defined\n", ref.Pos, ref.Key)
+	}
+	for _, key := range report.Unread {
+		fmt.Printf("key <%s> is defined but not read\n", key)
+	}
+	if len(report.Undefined)+len(report.Unread) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// renames is a flag.Value of repeated -rename old=new flags
+type renames []gestalt.Migration
+
+func (r *renames) String() string { return "" }
+
+func (r *renames) Set(s string) error {
+	old, new, ok := strings.Cut(s, "=")
+	if !ok || old == "" || new == "" {
+		return fmt.Errorf("expected old=new")
+	}
+	*r = append(*r, gestalt.Rename(strings.TrimSpace(old), strings.TrimSpace(new)))
+	return nil
+}
+
+func migrate(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	var migrations renames
+	flags.Var(&migrations, "rename", "rename key old to new (old=new)")
+	write := flags.Bool("w", false, "write migrated files in place")
+	flags.Parse(args)
+
+	if len(migrations) == 0 || flags.NArg() == 0 {
+		return fail(fmt.Errorf("migrate requires -rename and files"))
+	}
+	for _, filename := range flags.Args() {
+		b, e := os.ReadFile(filename)
+		if e != nil {
+			return fail(e)
+		}
+		s, e := gestalt.MigrateStr(string(b), migrations...)
+		if e != nil {
+			return fail(fmt.Errorf("%s - %w", filename, e))
+		}
+		if !*write {
+			fmt.Print(s)
+			continue
+		}
+		fi, e := os.Stat(filename)
+		if e != nil {
+			return fail(e)
+		}
+		if e := os.WriteFile(filename, []byte(s), fi.Mode().Perm()); e != nil {
+			return fail(e)
+		}
+	}
+	return 0
+}
+
+// returns the merged Properties of files, with later files overwriting
+// keys of earlier ones
+func merged(filenames []string) (gestalt.Properties, error) {
+	p := gestalt.Properties{}
+	for _, filename := range filenames {
+		fp, e := gestalt.Load(filename)
+		if e != nil {
+			return nil, e
+		}
+		p.Copy(fp, true)
+	}
+	return p, nil
+}
+
+func dump(args []string) int {
+	flags := flag.NewFlagSet("dump", flag.ExitOnError)
+	resolved := flags.Bool("resolved", false, "expand references")
+	schemaFile := flags.String("schema", "", "schema file")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return fail(fmt.Errorf("dump requires files"))
+	}
+	var schema *gestalt.Schema
+	if *schemaFile != "" {
+		var e error
+		if schema, e = gestalt.LoadSchema(*schemaFile); e != nil {
+			return fail(e)
+		}
+	}
+	var layers []gestalt.Layer
+	for _, filename := range flags.Args() {
+		p, e := gestalt.Load(filename)
+		if e != nil {
+			return fail(e)
+		}
+		layers = append(layers, gestalt.Layer{Name: filename, Properties: p})
+	}
+
+	var entries []gestalt.DumpEntry
+	var e error
+	if *resolved {
+		entries, e = gestalt.DumpResolved(layers, schema, gestalt.EnvInterpolator)
+	} else {
+		entries, e = gestalt.Dump(layers, schema)
+	}
+	if e != nil {
+		return fail(e)
+	}
+	if e := gestalt.WriteDump(os.Stdout, entries); e != nil {
+		return fail(e)
+	}
+	return 0
+}
@@ -0,0 +1,162 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type coercion
+//
+// As converts the value of a key to a requested type. Without the Coerce
+// option, the requested type must match the value's type (per key suffix),
+// and mismatches are ErrTypeMismatch. With Coerce, the following rules
+// apply:
+//
+//	value      requested               result
+//	---------  ----------------------  -------------------------------------
+//	string     string                  the value
+//	string     int, bool, Duration     parsed value (see GetInt, GetBool, GetDuration)
+//	string     float64                 parsed value (see GetFloat)
+//	string     []string                1-element array
+//	[]string   []string                the value
+//	[]string   string                  comma-joined elements
+//	map        map[string]string       the value
+//
+// All other combinations are ErrTypeMismatch.
+// ----------------------------------------------------------------------
+
+// ErrNoSuchKey is the error of lookups of undefined (or @unset) keys.
+var ErrNoSuchKey = errors.New("no such key")
+
+// ErrTypeMismatch is the error of conversions of values to types that
+// (per coercion rules) are not compatible.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// GetOption is an option of typed getters, e.g. As.
+type GetOption func(*getOptions)
+
+type getOptions struct {
+	coerce    bool
+	normalize bool
+	locale    *NumberLocale
+	fractions bool
+}
+
+func newGetOptions(opts []GetOption) *getOptions {
+	o := &getOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Coerce enables the coercion rules of typed getters.
+func Coerce() GetOption {
+	return func(o *getOptions) {
+		o.coerce = true
+	}
+}
+
+// Normalize scales the values of GetWeightedMap to sum to 1.0.
+func Normalize() GetOption {
+	return func(o *getOptions) {
+		o.normalize = true
+	}
+}
+
+// Returns the value of key converted to T, which is one of string,
+// []string, map[string]string, int, float64, bool, or time.Duration.
+//
+// Returns a *KeyError of ErrNoSuchKey if no such key, of ErrTypeMismatch
+// if the value is not of type T (or coercible, with Coerce), or of the
+// parse error of coerced string values.
+func As[T any](p Properties, key string, opts ...GetOption) (T, error) {
+	var t T
+	v, e := p.as(key, &t, newGetOptions(opts))
+	if e != nil {
+		return t, &KeyError{key, e}
+	}
+	return v.(T), nil
+}
+
+// returns the value of key converted to the type of *target
+func (p Properties) as(key string, target interface{}, o *getOptions) (interface{}, error) {
+	v := p.get(key)
+	if v == nil {
+		return nil, ErrNoSuchKey
+	}
+
+	var tag byte
+	switch target.(type) {
+	case *string:
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case []string:
+			if o.coerce {
+				return strings.Join(v, val_delim), nil
+			}
+		}
+		return nil, mismatch(v, "string")
+	case *[]string:
+		switch v := v.(type) {
+		case []string:
+			return v, nil
+		case string:
+			if o.coerce {
+				return []string{v}, nil
+			}
+		}
+		return nil, mismatch(v, "[]string")
+	case *map[string]string:
+		if v, ok := v.(map[string]string); ok {
+			return v, nil
+		}
+		return nil, mismatch(v, "map[string]string")
+	case *int:
+		tag = typed_int
+		if o.locale != nil {
+			return o.parseInt(v)
+		}
+	case *float64:
+		if s, ok := v.(string); ok && o.coerce {
+			return o.parseFloat(s)
+		}
+		return nil, mismatch(v, "float64")
+	case *bool:
+		tag = typed_bool
+	case *time.Duration:
+		tag = typed_duration
+	default:
+		return nil, fmt.Errorf("unsupported type %T", target)
+	}
+
+	if _, ok := v.(string); !ok || !o.coerce {
+		return nil, mismatch(v, fmt.Sprintf("%T", target)[1:])
+	}
+	return p.convert(key, tag, v.(string))
+}
+
+// returns the int value of (coerced) v, per the locale of the options
+func (o *getOptions) parseInt(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok || !o.coerce {
+		return nil, mismatch(v, "int")
+	}
+	s, e := o.locale.normalize(s)
+	if e != nil {
+		return nil, e
+	}
+	return strconv.Atoi(s)
+}
+
+func mismatch(v interface{}, requested string) error {
+	return fmt.Errorf("%w - %T value requested as %s", ErrTypeMismatch, v, requested)
+}
@@ -0,0 +1,70 @@
+package gestalt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAs(t *testing.T) {
+	p, _ := LoadStr(`
+port = 8080
+debug = true
+timeout = 2s
+host = localhost
+hosts[] = a, b
+opts[:] = ssl:on
+`)
+
+	if v, e := As[string](p, "host"); e != nil || v != "localhost" {
+		t.Errorf("TestAs - As[string](host) - expected: localhost, got: %s, %v", v, e)
+	}
+	if v, e := As[[]string](p, "hosts[]"); e != nil || !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("TestAs - As[[]string](hosts[]) - got: %v, %v", v, e)
+	}
+	if v, e := As[map[string]string](p, "opts[:]"); e != nil || v["ssl"] != "on" {
+		t.Errorf("TestAs - As[map[string]string](opts[:]) - got: %v, %v", v, e)
+	}
+
+	// mismatches, without Coerce
+	var ke *KeyError
+	if _, e := As[int](p, "port"); !errors.Is(e, ErrTypeMismatch) || !errors.As(e, &ke) || ke.Key != "port" {
+		t.Errorf("TestAs - As[int](port) - expected: ErrTypeMismatch, got: %v", e)
+	}
+	if _, e := As[string](p, "hosts[]"); !errors.Is(e, ErrTypeMismatch) {
+		t.Errorf("TestAs - As[string](hosts[]) - expected: ErrTypeMismatch, got: %v", e)
+	}
+	if _, e := As[string](p, "nope"); !errors.Is(e, ErrNoSuchKey) {
+		t.Errorf("TestAs - As[string](nope) - expected: ErrNoSuchKey, got: %v", e)
+	}
+
+	// coercions
+	if v, e := As[int](p, "port", Coerce()); e != nil || v != 8080 {
+		t.Errorf("TestAs - As[int](port, Coerce) - expected: 8080, got: %d, %v", v, e)
+	}
+	if v, e := As[bool](p, "debug", Coerce()); e != nil || !v {
+		t.Errorf("TestAs - As[bool](debug, Coerce) - expected: true, got: %t, %v", v, e)
+	}
+	if v, e := As[time.Duration](p, "timeout", Coerce()); e != nil || v != 2*time.Second {
+		t.Errorf("TestAs - As[Duration](timeout, Coerce) - expected: 2s, got: %s, %v", v, e)
+	}
+	if v, e := As[[]string](p, "host", Coerce()); e != nil || !reflect.DeepEqual(v, []string{"localhost"}) {
+		t.Errorf("TestAs - As[[]string](host, Coerce) - got: %v, %v", v, e)
+	}
+	if v, e := As[string](p, "hosts[]", Coerce()); e != nil || v != "a,b" {
+		t.Errorf("TestAs - As[string](hosts[], Coerce) - expected: a,b, got: %s, %v", v, e)
+	}
+	if _, e := As[int](p, "host", Coerce()); e == nil || errors.Is(e, ErrTypeMismatch) {
+		t.Errorf("TestAs - As[int](host, Coerce) - expected parse error, got: %v", e)
+	}
+	if _, e := As[int](p, "hosts[]", Coerce()); !errors.Is(e, ErrTypeMismatch) {
+		t.Errorf("TestAs - As[int](hosts[], Coerce) - expected: ErrTypeMismatch, got: %v", e)
+	}
+	if v, e := As[float64](p, "port", Coerce()); e != nil || v != 8080 {
+		t.Errorf("TestAs - As[float64](port, Coerce) - expected: 8080, got: %g, %v", v, e)
+	}
+	if _, e := As[uint](p, "port", Coerce()); e == nil {
+		t.Errorf("TestAs - As[uint](port) - error expected for unsupported type")
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"sort"
+	"unique"
+)
+
+// CompactProperties is a read-only, memory compact form of Properties,
+// intended for very large (e.g. generated) configurations.
+//
+// All keys and values are interned, and all values are stored in a single
+// shared backing slice, so the per-entry overhead of a Properties map
+// (an interface{} box, and a slice or map header per entry) is avoided.
+// Lookups are by binary search of the sorted keys.
+type CompactProperties struct {
+	keys  []string
+	kinds []byte
+	spans []span
+	vals  []string
+}
+
+// span of an entry's value(s) in the shared backing slice.
+// map values are stored as n/2 (key, value) pairs.
+type span struct {
+	off, n uint32
+}
+
+// Returns a compact copy of the receiver.
+// @unset keys are not retained, and values other than strings, arrays,
+// and maps are retained as strings.
+func (p Properties) Compact() *CompactProperties {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		if p.get(k) != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	interned := make(map[string]string)
+	intern := func(s string) string {
+		if is, ok := interned[s]; ok {
+			return is
+		}
+		interned[s] = s
+		return s
+	}
+
+	c := &CompactProperties{
+		keys:  keys,
+		kinds: make([]byte, len(keys)),
+		spans: make([]span, len(keys)),
+	}
+	for i, k := range keys {
+		keys[i] = intern(k)
+		off := uint32(len(c.vals))
+		switch v := p.get(k).(type) {
+		case []string:
+			c.kinds[i] = bin_array
+			for _, av := range v {
+				c.vals = append(c.vals, intern(av))
+			}
+		case map[string]string:
+			c.kinds[i] = bin_map
+			for _, mk := range sortedKeys(v) {
+				c.vals = append(c.vals, intern(mk), intern(v[mk]))
+			}
+		case string:
+			c.kinds[i] = bin_string
+			c.vals = append(c.vals, intern(v))
+		default: // e.g. of values set directly in the map
+			c.kinds[i] = bin_string
+			c.vals = append(c.vals, intern(fmt.Sprint(v)))
+		}
+		c.spans[i] = span{off, uint32(len(c.vals)) - off}
+	}
+	// trim excess capacity of the backing slice
+	c.vals = append([]string(nil), c.vals...)
+	return c
+}
+
+// Returns the number of entries.
+func (c *CompactProperties) Len() int {
+	return len(c.keys)
+}
+
+// Returns the sorted keys. The returned slice must not be modified.
+func (c *CompactProperties) Keys() []string {
+	return c.keys
+}
+
+func (c *CompactProperties) lookup(key string) (int, bool) {
+	i := sort.SearchStrings(c.keys, key)
+	return i, i < len(c.keys) && c.keys[i] == key
+}
+
+// returns the values of entry i in the shared backing slice.
+// capacity is limited so that appends by caller do not clobber neighbors.
+func (c *CompactProperties) values(i int) []string {
+	s := c.spans[i]
+	return c.vals[s.off : s.off+s.n : s.off+s.n]
+}
+
+// String value property - returns nil/zero-value if no such key or not a string key
+func (c *CompactProperties) GetString(key string) string {
+	if i, ok := c.lookup(key); ok && c.kinds[i] == bin_string {
+		return c.vals[c.spans[i].off]
+	}
+	return ""
+}
+
+// returns nil/zero-value if no such key or key type is not array.
+// The returned slice shares the backing store and must not be modified.
+func (c *CompactProperties) GetArray(key string) []string {
+	if i, ok := c.lookup(key); ok && c.kinds[i] == bin_array {
+		return c.values(i)
+	}
+	return nil
+}
+
+// returns nil/zero-value if no such key or key type is not map.
+// The map is allocated per call.
+func (c *CompactProperties) GetMap(key string) map[string]string {
+	if i, ok := c.lookup(key); ok && c.kinds[i] == bin_map {
+		kvs := c.values(i)
+		mapv := make(map[string]string, len(kvs)/2)
+		for j := 0; j < len(kvs); j += 2 {
+			mapv[kvs[j]] = kvs[j+1]
+		}
+		return mapv
+	}
+	return nil
+}
+
+// Returns an (expanded) Properties copy of the receiver.
+func (c *CompactProperties) Properties() Properties {
+	p := make(Properties, len(c.keys))
+	for i, k := range c.keys {
+		switch c.kinds[i] {
+		case bin_array:
+			p[k] = append([]string(nil), c.values(i)...)
+		case bin_map:
+			p[k] = c.GetMap(k)
+		default:
+			p[k] = c.vals[c.spans[i].off]
+		}
+	}
+	return p
+}
+
+// Intern interns the keys and values of loaded Properties, e.g. of very
+// large (generated) configurations with many repeated values, so that
+// equal strings share their memory, and the loaded Properties do not
+// retain the content they are parsed from. See also Compact.
+func Intern() LoadOption {
+	return func(o *loadOptions) {
+		o.intern = true
+	}
+}
+
+// returns the interned copy of s
+func internString(s string) string {
+	return unique.Make(s).Value()
+}
+
+// returns the (parsed) value v with its strings interned. arrays are
+// interned in place.
+func internValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return internString(v)
+	case []string:
+		for i, av := range v {
+			v[i] = internString(av)
+		}
+		return v
+	case map[string]string:
+		mapv := make(map[string]string, len(v))
+		for mk, mv := range v {
+			mapv[internString(mk)] = internString(mv)
+		}
+		return mapv
+	}
+	return v
+}
@@ -0,0 +1,59 @@
+package gestalt
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestCompact(t *testing.T) {
+	prop, e := Load("test/test.conf")
+	if e != nil {
+		t.Errorf("TestCompact - Load - %s", e)
+	}
+
+	c := prop.Compact()
+	if c.Len() != len(prop) {
+		t.Errorf("TestCompact - Len - expected: %d, got: %d", len(prop), c.Len())
+	}
+	for k := range prop {
+		switch {
+		case isMapKey(k):
+			if !reflect.DeepEqual(prop.GetMap(k), c.GetMap(k)) {
+				t.Errorf("TestCompact - GetMap(%s) - expected: %s, got: %s", k, prop.GetMap(k), c.GetMap(k))
+			}
+		case isArrayKey(k):
+			if !reflect.DeepEqual(prop.GetArray(k), c.GetArray(k)) {
+				t.Errorf("TestCompact - GetArray(%s) - expected: %s, got: %s", k, prop.GetArray(k), c.GetArray(k))
+			}
+		default:
+			if prop.GetString(k) != c.GetString(k) {
+				t.Errorf("TestCompact - GetString(%s) - expected: %s, got: %s", k, prop.GetString(k), c.GetString(k))
+			}
+		}
+	}
+	if !reflect.DeepEqual(prop, c.Properties()) {
+		t.Errorf("TestCompact - Properties - expected: %s, got: %s", prop, c.Properties())
+	}
+	if v := c.GetString("no such key"); v != "" {
+		t.Errorf("TestCompact - GetString(no such key) - expected: <>, got: %s", v)
+	}
+}
+
+func TestIntern(t *testing.T) {
+	p, e := LoadStr("a = eu-west-1\nb = eu-west-1\nc[] = eu-west-1, us-east-1\nd[:] = r:eu-west-1\n", Intern())
+	if e != nil {
+		t.Fatalf("TestIntern - LoadStr - %s", e)
+	}
+	a := unsafe.StringData(p.GetString("a"))
+	for _, s := range []string{p.GetString("b"), p.GetArray("c[]")[0], p.GetMap("d[:]")["r"]} {
+		if unsafe.StringData(s) != a {
+			t.Errorf("TestIntern - expected equal values to share memory: %s", s)
+		}
+	}
+
+	c := Properties{"n": 42, "s": "v"}.Compact()
+	if c.GetString("n") != "42" || c.GetString("s") != "v" {
+		t.Errorf("TestIntern - Compact - expected: n=42 s=v, got: %v", c.Properties())
+	}
+}
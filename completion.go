@@ -0,0 +1,119 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kind is the value kind of keys, one of the Kind constants, per the key
+// suffix (e.g. `[]` of array keys).
+type Kind = string
+
+// value kinds of keys
+const (
+	KindString = "string"
+	KindArray  = "array"
+	KindMap    = "map"
+)
+
+// KeyInfo describes a key, e.g. for shell completion of `key=value`
+// overrides.
+type KeyInfo struct {
+	Key  string
+	Kind string // one of the Kind constants
+	Type string // schema type, if described by schema
+	Doc  string
+}
+
+// Returns the keys of p, in sorted order. Unset keys (and opaque lines,
+// see PassThrough) are excluded.
+func (p Properties) Keys() []string {
+	keys := make([]string, 0, len(p))
+	for k, v := range p {
+		if !isUnset(v) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Returns the string keys of p, in sorted order. See Keys.
+func (p Properties) StringKeys() []string {
+	return p.keysOf(KindString)
+}
+
+// Returns the array keys of p, in sorted order. See Keys.
+func (p Properties) ArrayKeys() []string {
+	return p.keysOf(KindArray)
+}
+
+// Returns the map keys of p, in sorted order. See Keys.
+func (p Properties) MapKeys() []string {
+	return p.keysOf(KindMap)
+}
+
+// returns the keys of p of kind, in sorted order
+func (p Properties) keysOf(kind Kind) []string {
+	keys := []string{}
+	for _, k := range p.Keys() {
+		if keyKind(k) == kind {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Returns the info of the keys of p, and the keys described by schema
+// (if not nil), in sorted order.
+func KeyInfos(p Properties, schema *Schema) []KeyInfo {
+	infos := map[string]*KeyInfo{}
+	for _, k := range p.Keys() {
+		infos[k] = &KeyInfo{Key: k, Kind: keyKind(k)}
+	}
+	if schema != nil {
+		for i := range schema.Keys {
+			spec := &schema.Keys[i]
+			info := infos[spec.Key]
+			if info == nil {
+				info = &KeyInfo{Key: spec.Key, Kind: keyKind(spec.Key)}
+				infos[spec.Key] = info
+			}
+			info.Type, info.Doc = spec.typeName(), spec.Doc
+		}
+	}
+
+	list := make([]KeyInfo, 0, len(infos))
+	for _, k := range sortedKeys(infos) {
+		list = append(list, *infos[k])
+	}
+	return list
+}
+
+// Returns the completion words of `key=value` overrides of the keys of p,
+// and schema (if not nil), beginning with prefix. The words are suitable
+// for e.g. bash `compgen -W` and zsh `compadd`.
+func Completions(p Properties, schema *Schema, prefix string) []string {
+	var words []string
+	for _, info := range KeyInfos(p, schema) {
+		if strings.HasPrefix(info.Key, prefix) {
+			words = append(words, info.Key+pkv_sep)
+		}
+	}
+	return words
+}
+
+// returns the value kind of key, per its suffix
+func keyKind(key string) string {
+	switch {
+	case isArrayKey(key):
+		return KindArray
+	case isMapKey(key):
+		return KindMap
+	}
+	return KindString
+}
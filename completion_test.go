@@ -0,0 +1,52 @@
+package gestalt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeys(t *testing.T) {
+	p, _ := LoadStr("b = 1\na[] = x, y\nc = @unset\n")
+	if keys := p.Keys(); !reflect.DeepEqual(keys, []string{"a[]", "b"}) {
+		t.Errorf("TestKeys - Keys() - expected: [a[] b], got: %v", keys)
+	}
+}
+
+func TestKeysByKind(t *testing.T) {
+	p, _ := LoadStr("b = 1\na = 2\nz[] = x\ny[] = x, y\nm[:] = k:v\nc = @unset\nd[] = @unset\n")
+	for _, c := range []struct {
+		name     string
+		keys     []string
+		expected []string
+	}{
+		{"StringKeys", p.StringKeys(), []string{"a", "b"}},
+		{"ArrayKeys", p.ArrayKeys(), []string{"y[]", "z[]"}},
+		{"MapKeys", p.MapKeys(), []string{"m[:]"}},
+		{"MapKeys (none)", Properties{"a": "1"}.MapKeys(), []string{}},
+	} {
+		if !reflect.DeepEqual(c.keys, c.expected) {
+			t.Errorf("TestKeysByKind - %s() - expected: %v, got: %v", c.name, c.expected, c.keys)
+		}
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	p, _ := LoadStr("db.host = localhost\ndb.opts[:] = ssl:on\nlog.level = info\n")
+	schema := &Schema{Keys: []KeySpec{{Key: "db.port", Type: TypeInt, Doc: "port"}}}
+
+	infos := KeyInfos(p, schema)
+	if len(infos) != 4 {
+		t.Fatalf("TestCompletions - KeyInfos - expected: 4, got: %v", infos)
+	}
+	if info := infos[1]; info.Key != "db.opts[:]" || info.Kind != KindMap {
+		t.Errorf("TestCompletions - KeyInfos - expected: db.opts[:] map, got: %v", info)
+	}
+	if info := infos[2]; info.Key != "db.port" || info.Type != TypeInt || info.Doc != "port" {
+		t.Errorf("TestCompletions - KeyInfos - expected: db.port int, got: %v", info)
+	}
+
+	expected := []string{"db.host=", "db.opts[:]=", "db.port="}
+	if words := Completions(p, schema, "db."); !reflect.DeepEqual(words, expected) {
+		t.Errorf("TestCompletions - Completions(db.) - expected: %v, got: %v", expected, words)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"strings"
+	"time"
+)
+
+// Config is the minimal read-only view of a configuration, implemented by
+// Properties, SafeProperties, and OverlayStack. Libraries accepting Config
+// (rather than any of these) are easily tested with simple fakes.
+type Config interface {
+	GetString(key string) string
+	GetInt(key string) (int, error)
+	GetBool(key string) (bool, error)
+	GetDuration(key string) (time.Duration, error)
+	// returns true if key is defined (and not @unset)
+	Has(key string) bool
+	// returns the view of the keys of the group of prefix (e.g. "db"),
+	// relative to the prefix (e.g. "db.host" as "host").
+	Sub(prefix string) Config
+}
+
+var (
+	_ Config = Properties(nil)
+	_ Config = (*SafeProperties)(nil)
+	_ Config = (*OverlayStack)(nil)
+)
+
+// Returns true if key is defined (and not @unset).
+func (p Properties) Has(key string) bool {
+	return p.get(key) != nil
+}
+
+// Returns true if any key with (string) prefix prefix is defined, e.g.
+// HasPrefix("smtp.") of any key of group smtp.
+func (p Properties) HasPrefix(prefix string) bool {
+	for k := range p {
+		if strings.HasPrefix(k, prefix) && p.defined(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the kind of key (KindString, KindArray, or KindMap) if key is
+// defined, or "" if not. Values are not converted.
+func (p Properties) TypeOf(key string) Kind {
+	if !p.defined(key) {
+		return ""
+	}
+	return keyKind(key)
+}
+
+// returns true if key is defined
+func (p Properties) defined(key string) bool {
+	return p.get(key) != nil
+}
+
+// Returns the Properties of the keys of the group of prefix, relative to
+// the prefix, e.g. key "db.host" as "host" of prefix "db". The returned
+// object shares the values of p.
+func (p Properties) Sub(prefix string) Config {
+	return p.sub(prefix)
+}
+
+func (p Properties) sub(prefix string) Properties {
+	sub := make(Properties)
+	for k, v := range p {
+		if rk, ok := strings.CutPrefix(k, prefix+"."); ok && rk != "" {
+			sub[rk] = v
+		}
+	}
+	sub.adopt(p, func(k string) (string, bool) {
+		rk, ok := strings.CutPrefix(k, prefix+".")
+		return rk, ok && rk != ""
+	}, false)
+	return sub
+}
+
+// ----------------------------------------------------------------------
+// Config of SafeProperties - reads of the current snapshot
+// ----------------------------------------------------------------------
+
+func (sp *SafeProperties) GetString(key string) string {
+	return sp.Properties().GetString(key)
+}
+
+func (sp *SafeProperties) GetInt(key string) (int, error) {
+	return sp.Properties().GetInt(key)
+}
+
+func (sp *SafeProperties) GetBool(key string) (bool, error) {
+	return sp.Properties().GetBool(key)
+}
+
+func (sp *SafeProperties) GetDuration(key string) (time.Duration, error) {
+	return sp.Properties().GetDuration(key)
+}
+
+func (sp *SafeProperties) Has(key string) bool {
+	return sp.Properties().Has(key)
+}
+
+func (sp *SafeProperties) HasPrefix(prefix string) bool {
+	return sp.Properties().HasPrefix(prefix)
+}
+
+func (sp *SafeProperties) TypeOf(key string) Kind {
+	return sp.Properties().TypeOf(key)
+}
+
+// Returns the view of the group of prefix of the current snapshot.
+// See Properties#Sub.
+func (sp *SafeProperties) Sub(prefix string) Config {
+	return sp.Properties().Sub(prefix)
+}
+
+// ----------------------------------------------------------------------
+// Config of OverlayStack - reads of the resolved view of all layers
+// ----------------------------------------------------------------------
+
+func (s *OverlayStack) GetInt(key string) (int, error) {
+	return s.view(key).GetInt(key)
+}
+
+func (s *OverlayStack) GetBool(key string) (bool, error) {
+	return s.view(key).GetBool(key)
+}
+
+func (s *OverlayStack) GetDuration(key string) (time.Duration, error) {
+	return s.view(key).GetDuration(key)
+}
+
+func (s *OverlayStack) Has(key string) bool {
+	return s.Get(key) != nil
+}
+
+func (s *OverlayStack) HasPrefix(prefix string) bool {
+	return s.Flatten().HasPrefix(prefix)
+}
+
+func (s *OverlayStack) TypeOf(key string) Kind {
+	if !s.Has(key) {
+		return ""
+	}
+	return keyKind(key)
+}
+
+// Returns the view of the group of prefix of the flattened stack.
+// See Properties#Sub.
+func (s *OverlayStack) Sub(prefix string) Config {
+	return s.Flatten().Sub(prefix)
+}
+
+// returns the resolved value of key as single-key Properties
+func (s *OverlayStack) view(key string) Properties {
+	if v := s.Get(key); v != nil {
+		return Properties{key: v}
+	}
+	return nil
+}
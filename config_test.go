@@ -0,0 +1,76 @@
+package gestalt
+
+import (
+	"testing"
+	"time"
+)
+
+// exercises c as the Config of "db.host = localhost", "db.port = 5432",
+// "db.ssl = true", and "db.timeout = 5s"
+func testConfig(t *testing.T, name string, c Config) {
+	if !c.Has("db.host") || c.Has("db.user") {
+		t.Errorf("%s - Has - expected db.host only", name)
+	}
+	db := c.Sub("db")
+	if v := db.GetString("host"); v != "localhost" {
+		t.Errorf("%s - Sub(db).GetString(host) - expected: localhost, got: %s", name, v)
+	}
+	if v, e := db.GetInt("port"); e != nil || v != 5432 {
+		t.Errorf("%s - Sub(db).GetInt(port) - expected: 5432, got: %d, %v", name, v, e)
+	}
+	if v, e := c.GetBool("db.ssl"); e != nil || !v {
+		t.Errorf("%s - GetBool(db.ssl) - expected: true, got: %t, %v", name, v, e)
+	}
+	if v, e := c.GetDuration("db.timeout"); e != nil || v != 5*time.Second {
+		t.Errorf("%s - GetDuration(db.timeout) - expected: 5s, got: %s, %v", name, v, e)
+	}
+	if _, e := c.GetInt("db.user"); e == nil {
+		t.Errorf("%s - GetInt(db.user) - error expected", name)
+	}
+	if db.Has("db.host") || c.Sub("d").Has("b.host") {
+		t.Errorf("%s - Sub - expected keys relative to whole prefix", name)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	p := Properties{"db.host": "localhost", "db.port": "5432", "db.ssl": "true", "db.timeout": "5s"}
+	testConfig(t, "TestConfig - Properties", p)
+	testConfig(t, "TestConfig - SafeProperties", NewSafeProperties(p))
+
+	s := NewOverlayStack(Properties{"db.host": "db.internal", "db.port": "5432", "db.user": "admin"})
+	s.Push(Properties{"db.host": "localhost", "db.ssl": "true", "db.timeout": "5s", "db.user": unsetValue{}})
+	testConfig(t, "TestConfig - OverlayStack", s)
+}
+
+func TestIntrospection(t *testing.T) {
+	p, _ := LoadStr("smtp.host = mx\nsmtp.port = 25\nlist[] = a, b\nm[:] = k:v\ngone = @unset")
+	for _, c := range []struct {
+		key      string
+		expected Kind
+	}{
+		{"smtp.host", KindString}, {"list[]", KindArray}, {"m[:]", KindMap}, {"gone", ""}, {"nope", ""},
+	} {
+		if v := p.TypeOf(c.key); v != c.expected {
+			t.Errorf("TestIntrospection - TypeOf(%s) - expected: %q, got: %q", c.key, c.expected, v)
+		}
+	}
+	for prefix, expected := range map[string]bool{"smtp.": true, "smtp.p": true, "list": true, "gone": false, "imap.": false} {
+		if v := p.HasPrefix(prefix); v != expected {
+			t.Errorf("TestIntrospection - HasPrefix(%s) - expected: %t, got: %t", prefix, expected, v)
+		}
+	}
+
+	lp, _ := LoadStr("n = 42\na[] = x", Lazy())
+	if v := lp.TypeOf("a[]"); v != KindArray {
+		t.Errorf("TestIntrospection - Lazy - TypeOf(a[]) - expected: array, got: %q", v)
+	}
+
+	sp := NewSafeProperties(p)
+	if !sp.HasPrefix("smtp.") || sp.TypeOf("list[]") != KindArray {
+		t.Errorf("TestIntrospection - SafeProperties - HasPrefix/TypeOf mismatch")
+	}
+	s := NewOverlayStack(p)
+	if !s.HasPrefix("smtp.") || s.TypeOf("m[:]") != KindMap || s.TypeOf("gone") != "" {
+		t.Errorf("TestIntrospection - OverlayStack - HasPrefix/TypeOf mismatch")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConfigSet is an ordered list of sources (e.g. files and providers) that
+// is loaded, merged, validated, and watched as a single configuration.
+//
+// Sources are layered in order (see OverlayStack): keys of later sources
+// replace those of earlier sources, and @unset keys mask them.
+//
+// ConfigSet is a Source.
+type ConfigSet struct {
+	Sources []Source
+	// if not nil, the merged Properties are validated against the schema
+	Schema *Schema
+}
+
+// Instantiates a new ConfigSet of the sources, in order.
+func NewConfigSet(sources ...Source) *ConfigSet {
+	return &ConfigSet{Sources: sources}
+}
+
+// Instantiates a new ConfigSet of the files, in order.
+func NewConfigFiles(filenames []string, opts ...LoadOption) *ConfigSet {
+	cs := &ConfigSet{}
+	for _, filename := range filenames {
+		cs.Sources = append(cs.Sources, FileSource(filename, opts...))
+	}
+	return cs
+}
+
+// Loads and merges the sources. Returns error if a source can not be
+// loaded, or the merged Properties are not valid per Schema.
+func (cs *ConfigSet) Load(ctx context.Context) (Properties, error) {
+	s := NewOverlayStack(nil)
+	for i, src := range cs.Sources {
+		p, e := src.Load(ctx)
+		if e != nil {
+			return nil, fmt.Errorf("config set source %d - %w", i, e)
+		}
+		s.Push(p)
+	}
+	p := s.Flatten()
+	if cs.Schema != nil {
+		if errs := cs.Schema.Validate(p); len(errs) > 0 {
+			return nil, fmt.Errorf("config set is not valid - %w", errors.Join(errs...))
+		}
+	}
+	return p, nil
+}
+
+// Loads the set and watches it for changes, per Watch. fn is called with
+// the merged Properties when any source changes.
+func (cs *ConfigSet) Watch(ctx context.Context, interval time.Duration, fn ChangeFunc, opts ...WatchOption) (*Watcher, error) {
+	return Watch(ctx, cs, interval, fn, opts...)
+}
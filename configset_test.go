@@ -0,0 +1,61 @@
+package gestalt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigSet(t *testing.T) {
+	dir := writeFragments(t, map[string]string{
+		"base.conf":  "db.host = localhost\ndb.port = 5432\nfeature = on\n",
+		"local.conf": "db.host = db.local\nfeature = @unset\n",
+	})
+	env := SourceFunc(func(ctx context.Context) (Properties, error) {
+		return Properties{"db.port": "6543"}, nil
+	})
+	cs := NewConfigFiles([]string{filepath.Join(dir, "base.conf"), filepath.Join(dir, "local.conf")})
+	cs.Sources = append(cs.Sources, env)
+	cs.Schema = &Schema{Keys: []KeySpec{{Key: "db.port", Type: TypeInt, Required: true}}}
+
+	p, e := cs.Load(context.Background())
+	if e != nil {
+		t.Fatalf("TestConfigSet - Load - %s", e)
+	}
+	for k, expected := range map[string]string{"db.host": "db.local", "db.port": "6543", "feature": ""} {
+		if v := p.GetString(k); v != expected {
+			t.Errorf("TestConfigSet - GetString(%s) - expected: %s, got: %s", k, expected, v)
+		}
+	}
+
+	changes := make(chan Properties, 1)
+	w, e := cs.Watch(context.Background(), 0, func(p Properties, e error) {
+		if e == nil {
+			changes <- p
+		}
+	})
+	if e != nil {
+		t.Fatalf("TestConfigSet - Watch - %s", e)
+	}
+	defer w.Stop()
+
+	os.WriteFile(filepath.Join(dir, "local.conf"), []byte("db.host = db2.local\n"), 0644)
+	w.Reload()
+	select {
+	case p := <-changes:
+		if p.GetString("db.host") != "db2.local" || p.GetString("feature") != "on" {
+			t.Errorf("TestConfigSet - change - unexpected Properties: %v", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestConfigSet - change notification expected")
+	}
+
+	cs.Sources = append(cs.Sources, SourceFunc(func(ctx context.Context) (Properties, error) {
+		return Properties{"db.port": "http"}, nil
+	}))
+	if _, e := cs.Load(context.Background()); e == nil {
+		t.Errorf("TestConfigSet - Load - error expected for invalid config set")
+	}
+}
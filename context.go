@@ -0,0 +1,36 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+)
+
+// context key of Properties
+type contextKey struct{}
+
+// Returns a copy of ctx carrying p, e.g. the configuration view of a
+// request or tenant. See FromContext.
+func NewContext(ctx context.Context, p Properties) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// Returns the Properties carried by ctx, if any.
+// The returned object must not be modified.
+func FromContext(ctx context.Context) (Properties, bool) {
+	p, ok := ctx.Value(contextKey{}).(Properties)
+	return p, ok
+}
+
+// Returns a copy of ctx carrying the Properties of ctx (if any) with
+// overrides applied, e.g. per-request settings. Keys of overrides replace
+// those of ctx, and @unset keys of overrides mask them. The Properties of
+// ctx are not modified.
+func WithOverrides(ctx context.Context, overrides Properties) context.Context {
+	p, _ := FromContext(ctx)
+	scoped := p.Clone()
+	scoped.Copy(overrides, true)
+	return NewContext(ctx, scoped)
+}
@@ -0,0 +1,33 @@
+package gestalt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Errorf("TestContext - FromContext(Background) - expected no Properties")
+	}
+
+	base, _ := LoadStr("db.host = localhost\nlog.level = info\nfeature = on\n")
+	ctx := NewContext(context.Background(), base)
+	if p, ok := FromContext(ctx); !ok || p.GetString("db.host") != "localhost" {
+		t.Errorf("TestContext - FromContext - expected base Properties, got: %v", p)
+	}
+
+	overrides, _ := LoadStr("log.level = debug\nfeature = @unset\n")
+	scoped, _ := FromContext(WithOverrides(ctx, overrides))
+	for k, expected := range map[string]string{"db.host": "localhost", "log.level": "debug", "feature": ""} {
+		if v := scoped.GetString(k); v != expected {
+			t.Errorf("TestContext - WithOverrides - GetString(%s) - expected: %s, got: %s", k, expected, v)
+		}
+	}
+	if base.GetString("log.level") != "info" || base.GetString("feature") != "on" {
+		t.Errorf("TestContext - WithOverrides - base Properties modified: %v", base)
+	}
+
+	if p, ok := FromContext(WithOverrides(context.Background(), overrides)); !ok || p.GetString("log.level") != "debug" {
+		t.Errorf("TestContext - WithOverrides(Background) - expected overrides, got: %v", p)
+	}
+}
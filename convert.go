@@ -0,0 +1,607 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Format of a serialized configuration.
+type Format string
+
+// supported formats. YAML and TOML are output only (see Encode), as their
+// conforming parsers are beyond the standard library, and Decode fails for
+// them. Convert them with an external tool, e.g. to JSON, to read them.
+const (
+	FormatGestalt        Format = "gestalt"
+	FormatJavaProperties Format = "properties"
+	FormatJSON           Format = "json"
+	FormatYAML           Format = "yaml"
+	FormatTOML           Format = "toml"
+	FormatDotenv         Format = "dotenv"
+)
+
+// ----------------------------------------------------------------------
+// Typing rules
+//
+// gestalt and java properties keys are typed per the key suffix, as usual,
+// and java properties array and map values are given per gestalt syntax.
+//
+// json values are typed per their json type, and array and object values
+// are given the `[]` and `[:]` key suffix (if not already present). Scalar
+// (number, bool) values are converted to strings. Nested arrays and objects
+// are not supported.
+//
+// dotenv values are all strings. On output, keys are converted to
+// environment variable names (see Environ) and array and map values are
+// given in their gestalt representation (e.g. "a, b" and "k:v, k2:v2").
+//
+// @unset keys are only retained by the gestalt format.
+// ----------------------------------------------------------------------
+
+// Converts the configuration read from r in format from to format to,
+// written to w.
+func Convert(r io.Reader, from Format, to Format, w io.Writer) error {
+	p, e := Decode(r, from)
+	if e != nil {
+		return e
+	}
+	return p.Encode(w, to)
+}
+
+// Instantiates a new Properties object from the configuration read from r
+// in the specified format. opts apply to the gestalt format, except for
+// MaxContentSize, which limits the content read from r in all formats.
+// YAML and TOML are output only, and are not decoded.
+func Decode(r io.Reader, from Format, opts ...LoadOption) (Properties, error) {
+	b, e := newLoadOptions(opts).readLimited(r)
+	if e != nil {
+		return nil, e
+	}
+	switch from {
+	case FormatGestalt:
+		return LoadStr(string(b), opts...)
+	case FormatJavaProperties:
+		return decodeJavaProperties(string(b))
+	case FormatJSON:
+		return decodeJSON(b)
+	case FormatDotenv:
+		return decodeDotenv(string(b))
+	case FormatYAML, FormatTOML:
+		return nil, fmt.Errorf("decoding format <%s> is not supported - %s is output only", from, from)
+	}
+	return nil, fmt.Errorf("unknown format <%s>", from)
+}
+
+// Writes the receiver to w in the specified format.
+func (p Properties) Encode(w io.Writer, to Format) error {
+	switch to {
+	case FormatGestalt:
+		return p.Store(w)
+	case FormatJavaProperties:
+		return p.encodeJavaProperties(w)
+	case FormatJSON:
+		b, e := json.MarshalIndent(p, "", "  ")
+		if e != nil {
+			return e
+		}
+		_, e = fmt.Fprintf(w, "%s\n", b)
+		return e
+	case FormatYAML:
+		return p.encodeYAML(w)
+	case FormatTOML:
+		return p.encodeTOML(w)
+	case FormatDotenv:
+		return p.encodeDotenv(w)
+	}
+	return fmt.Errorf("unknown format <%s>", to)
+}
+
+// Writes the receiver to w in gestalt file syntax, ordered by key, with
+// the opaque lines (see PassThrough), if any, following the key preceding
+// them in their file (or else before the keys, or after them if the key is
+// not defined). Returns error if a value can not be represented in the file
+// syntax (e.g. it contains reserved chars).
+func (p Properties) Store(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var leading, trailing []string
+	opaque := make(map[string][]string) // by preceding key
+	for _, ol := range p.opaque() {
+		if _, defined := p[ol.after]; ol.after == empty {
+			leading = append(leading, ol.text)
+		} else if defined {
+			opaque[ol.after] = append(opaque[ol.after], ol.text)
+		} else {
+			trailing = append(trailing, ol.text)
+		}
+	}
+	for _, line := range leading {
+		fmt.Fprintln(bw, line)
+	}
+	for _, k := range sortedKeys(p) {
+		if v := p[k]; v != nil {
+			if strings.ContainsAny(k, "#\\=\n") || strings.Trim(k, trimset) != k || k == empty {
+				return fmt.Errorf("key <%s> can not be represented", k)
+			}
+			vrep, e := valueRep(v)
+			if e != nil {
+				return &KeyError{k, e}
+			}
+			fmt.Fprintf(bw, "%s = %s\n", k, vrep)
+		}
+		for _, line := range opaque[k] {
+			fmt.Fprintln(bw, line)
+		}
+	}
+	for _, line := range trailing {
+		fmt.Fprintln(bw, line)
+	}
+	return bw.Flush()
+}
+
+// returns the file syntax representation of v
+func valueRep(v interface{}) (string, error) {
+	// quotes element s if required, given chars reserved for the element.
+	rep := func(s string, reserved string) (string, error) {
+		if strings.ContainsAny(s, "#\\=\n"+reserved) {
+			return "", fmt.Errorf("value <%s> can not be represented", s)
+		}
+		if s == empty || strings.Trim(s, ws) != s || strings.HasPrefix(s, quote) || strings.HasSuffix(s, quote) {
+			s = quote + s + quote
+		}
+		return s, nil
+	}
+
+	var reps []string
+	switch v := v.(type) {
+	case unsetValue:
+		return unset, nil
+	case string:
+		return rep(v, "")
+	case []string:
+		if len(v) == 0 {
+			return "", fmt.Errorf("empty array can not be represented")
+		}
+		for _, av := range v {
+			r, e := rep(av, val_delim)
+			if e != nil {
+				return "", e
+			}
+			reps = append(reps, r)
+		}
+	case map[string]string:
+		if len(v) == 0 {
+			return "", fmt.Errorf("empty map can not be represented")
+		}
+		for _, mk := range sortedKeys(v) {
+			rk, e := rep(mk, val_delim+kv_delim)
+			if e != nil {
+				return "", e
+			}
+			rv, e := rep(v[mk], val_delim+kv_delim)
+			if e != nil {
+				return "", e
+			}
+			reps = append(reps, rk+kv_delim+rv)
+		}
+	default:
+		return "", fmt.Errorf("unsupported type %T", v)
+	}
+	return strings.Join(reps, val_delim+" "), nil
+}
+
+// returns the plain (unquoted) string representation of v
+func plainRep(v interface{}) string {
+	switch v := v.(type) {
+	case []string:
+		return strings.Join(v, val_delim+" ")
+	case map[string]string:
+		kvs := make([]string, 0, len(v))
+		for _, mk := range sortedKeys(v) {
+			kvs = append(kvs, mk+kv_delim+v[mk])
+		}
+		return strings.Join(kvs, val_delim+" ")
+	}
+	return fmt.Sprintf("%s", v)
+}
+
+// MarshalJSON implements json.Marshaler. @unset keys are omitted.
+func (p Properties) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p))
+	for k := range p {
+		if v := p.get(k); v != nil {
+			m[k] = v
+		}
+	}
+	return json.Marshal(m)
+}
+
+func decodeJSON(b []byte) (Properties, error) {
+	var m map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if e := d.Decode(&m); e != nil {
+		return nil, e
+	}
+
+	scalar := func(v interface{}) (string, error) {
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case json.Number, bool:
+			return fmt.Sprint(v), nil
+		}
+		return "", fmt.Errorf("nested value %v is not supported", v)
+	}
+
+	p := make(Properties, len(m))
+	for k, v := range m {
+		switch v := v.(type) {
+		case nil:
+		case []interface{}:
+			arrv := make([]string, len(v))
+			for i, av := range v {
+				var e error
+				if arrv[i], e = scalar(av); e != nil {
+					return nil, &KeyError{k, e}
+				}
+			}
+			if !isArrayKey(k) {
+				k += array
+			}
+			p[k] = arrv
+		case map[string]interface{}:
+			mapv := make(map[string]string, len(v))
+			for mk, mv := range v {
+				var e error
+				if mapv[mk], e = scalar(mv); e != nil {
+					return nil, &KeyError{k, e}
+				}
+			}
+			if !isMapKey(k) {
+				k += cmap
+			}
+			p[k] = mapv
+		default:
+			s, e := scalar(v)
+			if e != nil {
+				return nil, &KeyError{k, e}
+			}
+			p[k] = s
+		}
+	}
+	return p, nil
+}
+
+// ----------------------------------------------------------------------
+// java properties
+// ----------------------------------------------------------------------
+
+func (p Properties) encodeJavaProperties(w io.Writer) error {
+	escape := func(s string, key bool) string {
+		var b strings.Builder
+		for i, c := range s {
+			switch {
+			case c == '\\' || c == '=' || c == ':' || c == '#' || c == '!':
+				b.WriteRune('\\')
+				b.WriteRune(c)
+			case c == ' ' && (key || i == 0):
+				b.WriteString("\\ ")
+			case c == '\n':
+				b.WriteString("\\n")
+			case c == '\t':
+				b.WriteString("\\t")
+			case c == '\r':
+				b.WriteString("\\r")
+			case c > unicode.MaxASCII:
+				for _, u := range utf16Units(c) {
+					fmt.Fprintf(&b, "\\u%04x", u)
+				}
+			default:
+				b.WriteRune(c)
+			}
+		}
+		return b.String()
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, k := range sortedKeys(p) {
+		v := p.get(k)
+		if v == nil {
+			continue
+		}
+		// array and map values are parsed per file syntax on decode
+		vrep := plainRep(v)
+		if isArrayKey(k) || isMapKey(k) {
+			var e error
+			if vrep, e = valueRep(v); e != nil {
+				return &KeyError{k, e}
+			}
+		}
+		fmt.Fprintf(bw, "%s=%s\n", escape(k, true), escape(vrep, false))
+	}
+	return bw.Flush()
+}
+
+func utf16Units(c rune) []rune {
+	if c < 0x10000 {
+		return []rune{c}
+	}
+	c -= 0x10000
+	return []rune{0xd800 + (c>>10)&0x3ff, 0xdc00 + c&0x3ff}
+}
+
+func decodeJavaProperties(s string) (Properties, error) {
+	p := make(Properties)
+
+	// join logical lines
+	var lines []string
+	var logical strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line = strings.TrimLeft(line, " \t\f")
+		if logical.Len() == 0 && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+		// odd count of trailing backslashes is a continuation
+		n := len(line) - len(strings.TrimRight(line, "\\"))
+		if n%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+		lines = append(lines, logical.String())
+		logical.Reset()
+	}
+	if logical.Len() > 0 {
+		lines = append(lines, logical.String())
+	}
+
+	for _, line := range lines {
+		// key ends at the first unescaped '=', ':', or whitespace
+		i := 0
+		for ; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+				continue
+			}
+			if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+				break
+			}
+		}
+		if i > len(line) {
+			i = len(line)
+		}
+		rest := strings.TrimLeft(line[i:], " \t\f")
+		if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+			rest = strings.TrimLeft(rest[1:], " \t\f")
+		}
+		k, e := unescapeJava(line[:i])
+		if e != nil {
+			return nil, e
+		}
+		v, e := unescapeJava(rest)
+		if e != nil {
+			return nil, &KeyError{k, e}
+		}
+		if isArrayKey(k) || isMapKey(k) {
+			pv, e := parseValue(k, v)
+			if e != nil {
+				return nil, e
+			}
+			p[k] = pv
+			continue
+		}
+		p[k] = v
+	}
+	return p, nil
+}
+
+func unescapeJava(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+	var units []uint16
+	var b strings.Builder
+	flush := func() {
+		for _, r := range utf16Decode(units) {
+			b.WriteRune(r)
+		}
+		units = units[:0]
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			flush()
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\u escape in <%s>", s)
+			}
+			u, e := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if e != nil {
+				return "", fmt.Errorf("malformed \\u escape in <%s>", s)
+			}
+			units = append(units, uint16(u))
+			i += 4
+			continue
+		case 'n':
+			c = '\n'
+		case 't':
+			c = '\t'
+		case 'r':
+			c = '\r'
+		case 'f':
+			c = '\f'
+		default:
+			c = s[i]
+		}
+		flush()
+		b.WriteByte(c)
+	}
+	flush()
+	return b.String(), nil
+}
+
+func utf16Decode(units []uint16) []rune {
+	var rs []rune
+	for i := 0; i < len(units); i++ {
+		u := rune(units[i])
+		if 0xd800 <= u && u < 0xdc00 && i+1 < len(units) {
+			if l := rune(units[i+1]); 0xdc00 <= l && l < 0xe000 {
+				rs = append(rs, 0x10000+(u-0xd800)<<10+(l-0xdc00))
+				i++
+				continue
+			}
+		}
+		rs = append(rs, u)
+	}
+	return rs
+}
+
+// ----------------------------------------------------------------------
+// yaml & toml (output only)
+// ----------------------------------------------------------------------
+
+// returns the json (double quoted) string literal of s, which is also a
+// valid yaml and toml string literal.
+func jsonQuote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func (p Properties) encodeYAML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, k := range sortedKeys(p) {
+		switch v := p.get(k).(type) {
+		case nil:
+		case []string:
+			fmt.Fprintf(bw, "%s:", jsonQuote(k))
+			if len(v) == 0 {
+				fmt.Fprint(bw, " []")
+			}
+			fmt.Fprintln(bw)
+			for _, av := range v {
+				fmt.Fprintf(bw, "  - %s\n", jsonQuote(av))
+			}
+		case map[string]string:
+			fmt.Fprintf(bw, "%s:", jsonQuote(k))
+			if len(v) == 0 {
+				fmt.Fprint(bw, " {}")
+			}
+			fmt.Fprintln(bw)
+			for _, mk := range sortedKeys(v) {
+				fmt.Fprintf(bw, "  %s: %s\n", jsonQuote(mk), jsonQuote(v[mk]))
+			}
+		default: // e.g. of values set directly in the map
+			fmt.Fprintf(bw, "%s: %s\n", jsonQuote(k), jsonQuote(fmt.Sprint(v)))
+		}
+	}
+	return bw.Flush()
+}
+
+func (p Properties) encodeTOML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, k := range sortedKeys(p) {
+		switch v := p.get(k).(type) {
+		case nil:
+		case []string:
+			reps := make([]string, len(v))
+			for i, av := range v {
+				reps[i] = jsonQuote(av)
+			}
+			fmt.Fprintf(bw, "%s = [%s]\n", jsonQuote(k), strings.Join(reps, ", "))
+		case map[string]string:
+			reps := make([]string, 0, len(v))
+			for _, mk := range sortedKeys(v) {
+				reps = append(reps, jsonQuote(mk)+" = "+jsonQuote(v[mk]))
+			}
+			fmt.Fprintf(bw, "%s = { %s }\n", jsonQuote(k), strings.Join(reps, ", "))
+		default: // e.g. of values set directly in the map
+			fmt.Fprintf(bw, "%s = %s\n", jsonQuote(k), jsonQuote(fmt.Sprint(v)))
+		}
+	}
+	return bw.Flush()
+}
+
+// ----------------------------------------------------------------------
+// dotenv & environ
+// ----------------------------------------------------------------------
+
+// Returns the environment variable name of key: the key sans type suffix,
+// in upper case, with all chars other than letters and digits replaced
+// with `_`. For example, "db.host" and "db.hosts[]" are DB_HOST and DB_HOSTS.
+func EnvName(key string) string {
+	key = strings.TrimSuffix(strings.TrimSuffix(key, cmap), array)
+	return strings.Map(func(c rune) rune {
+		if c <= unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c)) {
+			return unicode.ToUpper(c)
+		}
+		return '_'
+	}, key)
+}
+
+// Returns the receiver as a sorted list of "NAME=value" environment
+// variables, e.g. for use with os/exec. See EnvName.
+func (p Properties) Environ() []string {
+	env := make([]string, 0, len(p))
+	for _, k := range sortedKeys(p) {
+		if v := p.get(k); v != nil {
+			env = append(env, EnvName(k)+"="+plainRep(v))
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+func (p Properties) encodeDotenv(w io.Writer) error {
+	quoter := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "$", "\\$", "\n", "\\n")
+	bw := bufio.NewWriter(w)
+	for _, kv := range p.Environ() {
+		i := strings.IndexByte(kv, '=')
+		fmt.Fprintf(bw, "%s=\"%s\"\n", kv[:i], quoter.Replace(kv[i+1:]))
+	}
+	return bw.Flush()
+}
+
+func decodeDotenv(s string) (Properties, error) {
+	unquoter := strings.NewReplacer("\\\\", "\\", "\\\"", "\"", "\\$", "$", "\\n", "\n")
+	p := make(Properties)
+	for n, line := range strings.Split(s, "\n") {
+		line = strings.Trim(line, trimset)
+		if line == empty || line[0] == comment {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.IndexByte(line, '=')
+		if i < 1 {
+			return nil, fmt.Errorf("line %d - malformed dotenv entry '%s'", n+1, line)
+		}
+		k, v := strings.Trim(line[:i], ws), strings.Trim(line[i+1:], ws)
+		switch {
+		case len(v) > 1 && v[0] == '"' && v[len(v)-1] == '"':
+			v = unquoter.Replace(v[1 : len(v)-1])
+		case len(v) > 1 && v[0] == '\'' && v[len(v)-1] == '\'':
+			v = v[1 : len(v)-1]
+		default:
+			if j := strings.Index(v, " #"); j >= 0 {
+				v = strings.Trim(v[:j], ws)
+			}
+		}
+		p[k] = v
+	}
+	return p, nil
+}
@@ -0,0 +1,116 @@
+package gestalt
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvertRoundTrip(t *testing.T) {
+	prop, e := Load("test/test.conf")
+	if e != nil {
+		t.Fatalf("TestConvertRoundTrip - Load - %s", e)
+	}
+
+	for _, format := range []Format{FormatGestalt, FormatJSON, FormatJavaProperties} {
+		var buf bytes.Buffer
+		if e := prop.Encode(&buf, format); e != nil {
+			t.Errorf("TestConvertRoundTrip - Encode(%s) - %s", format, e)
+			continue
+		}
+		got, e := Decode(&buf, format)
+		if e != nil {
+			t.Errorf("TestConvertRoundTrip - Decode(%s) - %s", format, e)
+			continue
+		}
+		if !reflect.DeepEqual(prop, got) {
+			t.Errorf("TestConvertRoundTrip - %s - expected: %s, got: %s", format, prop, got)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	spec := `
+db.host = localhost
+hosts[] = a, " b"
+m[:] = k:v
+`
+	var buf bytes.Buffer
+	if e := Convert(strings.NewReader(spec), FormatGestalt, FormatDotenv, &buf); e != nil {
+		t.Fatalf("TestConvert - Convert(dotenv) - %s", e)
+	}
+	expected := "DB_HOST=\"localhost\"\nHOSTS=\"a,  b\"\nM=\"k:v\"\n"
+	if buf.String() != expected {
+		t.Errorf("TestConvert - Convert(dotenv) - expected: %q, got: %q", expected, buf.String())
+	}
+	env, e := Decode(&buf, FormatDotenv)
+	if e != nil || env.GetString("HOSTS") != "a,  b" {
+		t.Errorf("TestConvert - Decode(dotenv) - expected: HOSTS=a,  b, got: %s (%v)", env, e)
+	}
+
+	buf.Reset()
+	if e := Convert(strings.NewReader(spec), FormatGestalt, FormatTOML, &buf); e != nil {
+		t.Fatalf("TestConvert - Convert(toml) - %s", e)
+	}
+	expected = "\"db.host\" = \"localhost\"\n\"hosts[]\" = [\"a\", \" b\"]\n\"m[:]\" = { \"k\" = \"v\" }\n"
+	if buf.String() != expected {
+		t.Errorf("TestConvert - Convert(toml) - expected: %q, got: %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	if e := Convert(strings.NewReader(spec), FormatGestalt, FormatYAML, &buf); e != nil {
+		t.Fatalf("TestConvert - Convert(yaml) - %s", e)
+	}
+	expected = "\"db.host\": \"localhost\"\n\"hosts[]\":\n  - \"a\"\n  - \" b\"\n\"m[:]\":\n  \"k\": \"v\"\n"
+	if buf.String() != expected {
+		t.Errorf("TestConvert - Convert(yaml) - expected: %q, got: %q", expected, buf.String())
+	}
+
+	json := `{"port": 8080, "hosts": ["a", "b"], "m": {"k": true}}`
+	p, e := Decode(strings.NewReader(json), FormatJSON)
+	if e != nil {
+		t.Fatalf("TestConvert - Decode(json) - %s", e)
+	}
+	if p.GetString("port") != "8080" || len(p.GetArray("hosts[]")) != 2 || p.GetMap("m[:]")["k"] != "true" {
+		t.Errorf("TestConvert - Decode(json) - got: %s", p)
+	}
+
+	if _, e := Decode(strings.NewReader("a: b"), FormatYAML); e == nil {
+		t.Errorf("TestConvert - Decode(yaml) - error expected")
+	}
+	for _, to := range []Format{FormatYAML, FormatTOML} {
+		buf.Reset()
+		if e := (Properties{"n": 42}).Encode(&buf, to); e != nil || !strings.Contains(buf.String(), `"42"`) {
+			t.Errorf("TestConvert - Encode(%s) - expected: n of \"42\", got: %q, %v", to, buf.String(), e)
+		}
+	}
+	if e := (Properties{"a": "b=c"}).Store(&buf); e == nil {
+		t.Errorf("TestConvert - Store - error expected for reserved char")
+	}
+}
+
+func TestJavaProperties(t *testing.T) {
+	spec := `
+# comment
+! another comment
+key1 = value one
+key2:value2
+key\ 3 valueé \
+    continued
+map[\:] = a:1, b:2
+`
+	p, e := Decode(strings.NewReader(spec), FormatJavaProperties)
+	if e != nil {
+		t.Fatalf("TestJavaProperties - Decode - %s", e)
+	}
+	expected := Properties{
+		"key1":   "value one",
+		"key2":   "value2",
+		"key 3":  "valueé continued",
+		"map[:]": map[string]string{"a": "1", "b": "2"},
+	}
+	if !reflect.DeepEqual(expected, p) {
+		t.Errorf("TestJavaProperties - Decode - expected: %s, got: %s", expected, p)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ----------------------------------------------------------------------
+// Whole file encryption
+//
+// format:  magic | version | iterations | salt | nonce | ciphertext
+//
+// the file key is derived from a passphrase with PBKDF2-SHA256 and the
+// content is sealed with AES-256-GCM, with the header (all fields preceding
+// the ciphertext) as additional authenticated data.
+// ----------------------------------------------------------------------
+
+const (
+	enc_magic      = "GSTLENC"
+	enc_version    = 1
+	enc_iterations = 600000
+	enc_min_iters  = enc_iterations / 6 // of headers, which are not authenticated
+	enc_max_iters  = enc_iterations * 4 // before key derivation
+	enc_salt_len   = 16
+	enc_key_len    = 32
+	enc_header_len = len(enc_magic) + 1 + 4 + enc_salt_len + 12
+)
+
+// KeySource provides the passphrase of encrypted files.
+type KeySource func() (string, error)
+
+// Returns a KeySource providing passphrase.
+func Passphrase(passphrase string) KeySource {
+	return func() (string, error) {
+		return passphrase, nil
+	}
+}
+
+// Returns a KeySource providing the value of environment variable name.
+func PassphraseFromEnv(name string) KeySource {
+	return func() (string, error) {
+		passphrase, _ := lookupEnv(name)
+		if passphrase == "" {
+			return "", fmt.Errorf("environment variable <%s> is not set", name)
+		}
+		return passphrase, nil
+	}
+}
+
+// Returns a KeySource providing the (first line of the) content of filename.
+func PassphraseFromFile(filename string) KeySource {
+	return func() (string, error) {
+		b, e := readHostFile(filename)
+		if e != nil {
+			return "", e
+		}
+		return strings.SplitN(string(b), "\n", 2)[0], nil
+	}
+}
+
+// Instantiates a new Properties object from the content of the specified
+// encrypted file (see EncryptFile). MaxContentSize limits the size of the
+// encrypted file, which is read up to the limit.
+func LoadEncrypted(filename string, ks KeySource, opts ...LoadOption) (Properties, error) {
+	ciphertext, e := newLoadOptions(opts).readFile(filename)
+	if e != nil {
+		return nil, fmt.Errorf("Error reading gestalt file <%s> : %w", filename, e)
+	}
+	plaintext, e := Decrypt(ciphertext, ks)
+	if e != nil {
+		return nil, fmt.Errorf("Error decrypting gestalt file <%s> : %s", filename, e)
+	}
+	return LoadStr(string(plaintext), opts...)
+}
+
+// Encrypts the content of file src to file dst.
+func EncryptFile(src, dst string, ks KeySource) error {
+	return cryptFile(src, dst, ks, Encrypt)
+}
+
+// Decrypts the content of (encrypted) file src to file dst.
+func DecryptFile(src, dst string, ks KeySource) error {
+	return cryptFile(src, dst, ks, Decrypt)
+}
+
+func cryptFile(src, dst string, ks KeySource, crypt func([]byte, KeySource) ([]byte, error)) error {
+	in, e := readHostFile(src)
+	if e != nil {
+		return e
+	}
+	out, e := crypt(in, ks)
+	if e != nil {
+		return e
+	}
+	return writeHostFile(dst, out, 0600)
+}
+
+// Encrypts plaintext with the passphrase of ks.
+func Encrypt(plaintext []byte, ks KeySource) ([]byte, error) {
+	header := make([]byte, enc_header_len)
+	copy(header, enc_magic)
+	header[len(enc_magic)] = enc_version
+	binary.BigEndian.PutUint32(header[len(enc_magic)+1:], enc_iterations)
+	if _, e := rand.Read(header[len(enc_magic)+5:]); e != nil {
+		return nil, e
+	}
+
+	aead, e := newAEAD(header, ks)
+	if e != nil {
+		return nil, e
+	}
+	nonce := header[enc_header_len-aead.NonceSize():]
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+// Decrypts ciphertext (see Encrypt) with the passphrase of ks.
+func Decrypt(ciphertext []byte, ks KeySource) ([]byte, error) {
+	if len(ciphertext) < enc_header_len || !bytes.HasPrefix(ciphertext, []byte(enc_magic)) {
+		return nil, errors.New("not a gestalt encrypted file")
+	}
+	if v := ciphertext[len(enc_magic)]; v != enc_version {
+		return nil, fmt.Errorf("unsupported gestalt encryption version %d", v)
+	}
+	header := ciphertext[:enc_header_len]
+	aead, e := newAEAD(header, ks)
+	if e != nil {
+		return nil, e
+	}
+	nonce := header[enc_header_len-aead.NonceSize():]
+	plaintext, e := aead.Open(nil, nonce, ciphertext[enc_header_len:], header)
+	if e != nil {
+		return nil, errors.New("decryption failed - wrong passphrase or corrupted file")
+	}
+	return plaintext, nil
+}
+
+// returns the AEAD keyed per the header's kdf params and ks
+func newAEAD(header []byte, ks KeySource) (cipher.AEAD, error) {
+	passphrase, e := ks()
+	if e != nil {
+		return nil, e
+	}
+	iterations := binary.BigEndian.Uint32(header[len(enc_magic)+1:])
+	if iterations < enc_min_iters || iterations > enc_max_iters {
+		return nil, fmt.Errorf("unsupported gestalt encryption iterations %d", iterations)
+	}
+	salt := header[len(enc_magic)+5 : len(enc_magic)+5+enc_salt_len]
+	key, e := pbkdf2.Key(sha256.New, passphrase, salt, int(iterations), enc_key_len)
+	if e != nil {
+		return nil, e
+	}
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return nil, e
+	}
+	return cipher.NewGCM(block)
+}
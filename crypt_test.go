@@ -0,0 +1,73 @@
+package gestalt
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptFile(t *testing.T) {
+	dir := t.TempDir()
+	encrypted := filepath.Join(dir, "test.conf.enc")
+	ks := Passphrase("correct horse battery staple")
+
+	if e := EncryptFile("test/test.conf", encrypted, ks); e != nil {
+		t.Fatalf("TestEncryptFile - EncryptFile - %s", e)
+	}
+	p, e := LoadEncrypted(encrypted, ks)
+	if e != nil {
+		t.Fatalf("TestEncryptFile - LoadEncrypted - %s", e)
+	}
+	if v := p.GetString("prop one"); v != "prop one value" {
+		t.Errorf("TestEncryptFile - GetString(prop one) - expected: prop one value, got: %s", v)
+	}
+
+	if _, e := LoadEncrypted(encrypted, Passphrase("wrong")); e == nil {
+		t.Errorf("TestEncryptFile - LoadEncrypted - error expected for wrong passphrase")
+	}
+	if _, e := LoadEncrypted("test/test.conf", ks); e == nil {
+		t.Errorf("TestEncryptFile - LoadEncrypted - error expected for plaintext file")
+	}
+	if _, e := LoadEncrypted(encrypted, ks, MaxContentSize(16)); !errors.Is(e, ErrContentTooLarge) {
+		t.Errorf("TestEncryptFile - LoadEncrypted - expected: ErrContentTooLarge, got: %v", e)
+	}
+
+	decrypted := filepath.Join(dir, "test.conf")
+	if e := DecryptFile(encrypted, decrypted, ks); e != nil {
+		t.Fatalf("TestEncryptFile - DecryptFile - %s", e)
+	}
+	want, _ := os.ReadFile("test/test.conf")
+	got, _ := os.ReadFile(decrypted)
+	if string(want) != string(got) {
+		t.Errorf("TestEncryptFile - DecryptFile - content mismatch")
+	}
+}
+
+func TestPassphraseFromEnv(t *testing.T) {
+	t.Setenv("GESTALT_TEST_PASSPHRASE", "s3cret")
+	if p, e := PassphraseFromEnv("GESTALT_TEST_PASSPHRASE")(); e != nil || p != "s3cret" {
+		t.Errorf("TestPassphraseFromEnv - expected: s3cret, got: %s (%v)", p, e)
+	}
+	if _, e := PassphraseFromEnv("GESTALT_TEST_NO_SUCH_VAR")(); e == nil {
+		t.Errorf("TestPassphraseFromEnv - error expected for unset variable")
+	}
+}
+
+func TestDecryptIterations(t *testing.T) {
+	ks := Passphrase("correct horse battery staple")
+	ciphertext, e := Encrypt([]byte("foo = bar\n"), ks)
+	if e != nil {
+		t.Fatalf("TestDecryptIterations - Encrypt - %s", e)
+	}
+	for _, n := range []uint32{0, 1, enc_max_iters + 1, math.MaxUint32} {
+		tampered := append([]byte(nil), ciphertext...)
+		binary.BigEndian.PutUint32(tampered[len(enc_magic)+1:], n)
+		if _, e := Decrypt(tampered, ks); e == nil || !strings.Contains(e.Error(), "iterations") {
+			t.Errorf("TestDecryptIterations - Decrypt - expected iterations error for %d, got: %v", n, e)
+		}
+	}
+}
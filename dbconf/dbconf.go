@@ -0,0 +1,176 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dbconf builds driver-specific database DSNs from a conventional
+// group of gestalt properties:
+//
+//	db.driver     = postgres          # postgres, mysql, or sqlite
+//	db.host       = db.example.com
+//	db.port       = 5432              # default per driver
+//	db.user       = app
+//	db.password   = s3cret
+//	db.name       = app
+//	db.path       = /var/lib/app.db   # sqlite only
+//	db.params[:]  = sslmode:require   # driver parameters
+//
+// host, user, and name are required for postgres and mysql, and path for
+// sqlite. Configs print with their password redacted.
+//
+// MySQL DSNs follow the semantics of the DSNs of the go-sql-driver/mysql
+// driver (see its FormatDSN), which does not unescape user and password:
+// passwords are written as is, as the driver parses them up to the last
+// `@`, and users must not contain `:` or `@`.
+package dbconf
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alphazero/gestalt"
+)
+
+// conventional keys, relative to the group prefix
+const (
+	KeyDriver   = "driver"
+	KeyHost     = "host"
+	KeyPort     = "port"
+	KeyUser     = "user"
+	KeyPassword = "password"
+	KeyName     = "name"
+	KeyPath     = "path"
+	KeyParams   = "params[:]"
+)
+
+// supported drivers (DSN flavors)
+const (
+	Postgres = "postgres"
+	MySQL    = "mysql"
+	SQLite   = "sqlite"
+)
+
+// default ports, by driver
+var ports = map[string]int{Postgres: 5432, MySQL: 3306}
+
+var drivers = map[string]string{Postgres: Postgres, MySQL: MySQL, SQLite: SQLite}
+
+// redacted representation of passwords
+const redacted = "xxxxx"
+
+// Config is the database configuration of a key group.
+type Config struct {
+	Driver   string
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Name     string
+	Path     string
+	Params   map[string]string
+}
+
+// Returns the Config of the key group of prefix (e.g. "db") of p.
+// Returns error if a value is not valid, or a required key is missing.
+func New(p gestalt.Properties, prefix string) (*Config, error) {
+	key := func(k string) string { return prefix + "." + k }
+
+	driver, e := gestalt.GetEnum(p, key(KeyDriver), drivers)
+	if e != nil {
+		return nil, e
+	}
+	c := &Config{
+		Driver:   driver,
+		Host:     p.GetString(key(KeyHost)),
+		Port:     ports[driver],
+		User:     p.GetString(key(KeyUser)),
+		Password: p.GetString(key(KeyPassword)),
+		Name:     p.GetString(key(KeyName)),
+		Path:     p.GetString(key(KeyPath)),
+		Params:   p.GetMap(key(KeyParams)),
+	}
+	if p.GetString(key(KeyPort)) != "" {
+		if c.Port, e = p.GetInt(key(KeyPort)); e != nil {
+			return nil, e
+		}
+	}
+
+	required := []string{key(KeyHost), key(KeyUser), key(KeyName)}
+	if driver == SQLite {
+		required = []string{key(KeyPath)}
+	}
+	if ok, missing := p.VerifyMust(required...); !ok {
+		return nil, fmt.Errorf("dbconf - %s DSN requires keys %s", driver, strings.Join(missing, ", "))
+	}
+	if driver == MySQL && strings.ContainsAny(c.User, ":@") {
+		return nil, fmt.Errorf("dbconf - %s user <%s> must not contain ':' or '@'", driver, c.User)
+	}
+	return c, nil
+}
+
+// Returns the DSN of the config, per its driver.
+func (c *Config) DSN() string {
+	return c.dsn(c.Password)
+}
+
+// Returns the DSN with the password redacted, e.g. for logging. Configs
+// (and pointers to configs) print redacted.
+func (c Config) String() string {
+	if c.Password == "" {
+		return c.dsn("")
+	}
+	return c.dsn(redacted)
+}
+
+func (c *Config) dsn(password string) string {
+	switch c.Driver {
+	case Postgres:
+		u := &url.URL{
+			Scheme:   Postgres,
+			Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+			Path:     "/" + c.Name,
+			RawQuery: c.query(),
+		}
+		if password != "" {
+			u.User = url.UserPassword(c.User, password)
+		} else {
+			u.User = url.User(c.User)
+		}
+		return u.String()
+	case MySQL:
+		// per FormatDSN (see above)
+		dsn := c.User
+		if password != "" {
+			dsn += ":" + password
+		}
+		dsn += fmt.Sprintf("@tcp(%s)/%s", net.JoinHostPort(c.Host, strconv.Itoa(c.Port)), c.Name)
+		if q := c.query(); q != "" {
+			dsn += "?" + q
+		}
+		return dsn
+	case SQLite:
+		dsn := "file:" + c.Path
+		if q := c.query(); q != "" {
+			dsn += "?" + q
+		}
+		return dsn
+	}
+	return ""
+}
+
+// returns the query-encoded params, in key order
+func (c *Config) query() string {
+	keys := make([]string, 0, len(c.Params))
+	for k := range c.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var q []string
+	for _, k := range keys {
+		q = append(q, url.QueryEscape(k)+"="+url.QueryEscape(c.Params[k]))
+	}
+	return strings.Join(q, "&")
+}
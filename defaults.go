@@ -0,0 +1,108 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Defaults
+//
+// defaults declared with SetDefaults are the values of keys not otherwise
+// defined, and are consulted last by all getters, i.e. keys defined by
+// files, Copy, or Inherit take precedence. Unlike the defval of the
+// Get*OrDefault getters, defaults are declared once, e.g. at startup:
+//
+//	p, e := gestalt.NewWithDefaults(map[string]interface{}{
+//		"server.port":    8080,
+//		"server.timeout": 30 * time.Second,
+//		"server.tags[]":  []string{"web"},
+//	})
+// ----------------------------------------------------------------------
+
+// Instantiates a new Properties object with defaults. See SetDefaults.
+func NewWithDefaults(defaults map[string]interface{}) (Properties, error) {
+	p := make(Properties)
+	if e := p.SetDefaults(defaults); e != nil {
+		return nil, e
+	}
+	return p, nil
+}
+
+// Sets the defaults of keys, replacing prior defaults. Keys already defined
+// retain their values. Values of array keys must be
+// []string, and of map keys map[string]string. Values of string keys are
+// strings, or are formatted per fmt.Sprint, e.g. 8080, true, or 30s (of a
+// time.Duration). Returns a *KeyError if the type of a value does not match
+// its key, in which case no defaults are set.
+func (p Properties) SetDefaults(defaults map[string]interface{}) error {
+	values := make(map[string]interface{}, len(defaults))
+	for _, k := range sortedKeys(defaults) {
+		v, e := defaultOf(k, defaults[k])
+		if e != nil {
+			return &KeyError{k, e}
+		}
+		values[k] = v
+	}
+	sd := p.side()
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	sd.defaults = values
+	return nil
+}
+
+// Returns true if the value of key is its default, i.e. key is not defined
+// and has a default. See SetDefaults.
+func (p Properties) IsDefault(key string) bool {
+	if _, defined := p[key]; defined {
+		return false
+	}
+	return p.defaults()[key] != nil
+}
+
+// Returns the keys with default values, sorted. See IsDefault.
+func (p Properties) DefaultKeys() []string {
+	var keys []string
+	for k := range p.defaults() {
+		if _, defined := p[k]; !defined {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// returns the defaults of p, or nil if none. The defaults are replaced (not
+// modified) by SetDefaults, and so may be read without locking.
+func (p Properties) defaults() map[string]interface{} {
+	if s := sideOf(p); s != nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return s.defaults
+	}
+	return nil
+}
+
+// returns the value of a default of key
+func defaultOf(key string, v interface{}) (interface{}, error) {
+	switch {
+	case isMapKey(key):
+		if mapv, ok := v.(map[string]string); ok {
+			return mapv, nil
+		}
+	case isArrayKey(key):
+		if arrv, ok := v.([]string); ok {
+			return arrv, nil
+		}
+	default:
+		switch v.(type) {
+		case []string, map[string]string, nil:
+		default:
+			return fmt.Sprint(v), nil
+		}
+	}
+	return nil, fmt.Errorf("%w - default of type %T", ErrTypeMismatch, v)
+}
@@ -0,0 +1,104 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewWithDefaults(t *testing.T) {
+	p, e := NewWithDefaults(map[string]interface{}{
+		"server.port":    8080,
+		"server.timeout": 30 * time.Second,
+		"server.debug":   true,
+		"server.tags[]":  []string{"web"},
+		"server.env[:]":  map[string]string{"mode": "dev"},
+	})
+	if e != nil {
+		t.Fatalf("TestNewWithDefaults - unexpected error: %s", e)
+	}
+	if port, e := p.GetInt("server.port"); e != nil || port != 8080 {
+		t.Errorf("TestNewWithDefaults - GetInt(server.port) - expected: 8080, got: %d, %v", port, e)
+	}
+	if d, e := p.GetDuration("server.timeout"); e != nil || d != 30*time.Second {
+		t.Errorf("TestNewWithDefaults - GetDuration(server.timeout) - expected: 30s, got: %v, %v", d, e)
+	}
+	if v, e := As[bool](p, "server.debug", Coerce()); e != nil || !v {
+		t.Errorf("TestNewWithDefaults - As[bool](server.debug) - expected: true, got: %t, %v", v, e)
+	}
+	if v := p.GetArray("server.tags[]"); !reflect.DeepEqual(v, []string{"web"}) {
+		t.Errorf("TestNewWithDefaults - GetArray(server.tags[]) - got: %v", v)
+	}
+	if v := p.GetMap("server.env[:]"); v["mode"] != "dev" {
+		t.Errorf("TestNewWithDefaults - GetMap(server.env[:]) - got: %v", v)
+	}
+	if v := p.GetStringOrDefault("server.host", "localhost"); v != "localhost" {
+		t.Errorf("TestNewWithDefaults - GetStringOrDefault(server.host) - expected call-site default, got: %s", v)
+	}
+
+	for _, defaults := range []map[string]interface{}{
+		{"a[]": "x"},
+		{"a[:]": []string{"x"}},
+		{"a": []string{"x"}},
+		{"a": nil},
+	} {
+		if _, e := NewWithDefaults(defaults); !errors.Is(e, ErrTypeMismatch) {
+			t.Errorf("TestNewWithDefaults - NewWithDefaults(%v) - expected ErrTypeMismatch, got: %v", defaults, e)
+		}
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	p, _ := LoadStr("port = 9090\n")
+	if e := p.SetDefaults(map[string]interface{}{"port": 8080, "host": "localhost"}); e != nil {
+		t.Fatalf("TestSetDefaults - unexpected error: %s", e)
+	}
+	if p.GetString("port") != "9090" || p.GetString("host") != "localhost" {
+		t.Errorf("TestSetDefaults - expected defined port and default host, got: %s, %s", p.GetString("port"), p.GetString("host"))
+	}
+	if p.IsDefault("port") || !p.IsDefault("host") || !reflect.DeepEqual(p.DefaultKeys(), []string{"host"}) {
+		t.Errorf("TestSetDefaults - IsDefault - got: %v", p.DefaultKeys())
+	}
+
+	// defaults yield to copied and inherited values
+	p.Copy(Properties{"host": "a.example.com"}, false)
+	if p.GetString("host") != "a.example.com" || p.IsDefault("host") {
+		t.Errorf("TestSetDefaults - Copy - expected copied host, got: %s", p.GetString("host"))
+	}
+	p.SetDefaults(map[string]interface{}{"user": "admin"})
+	p.Inherit(Properties{"user": "root"})
+	if p.GetString("user") != "root" {
+		t.Errorf("TestSetDefaults - Inherit - expected inherited user, got: %s", p.GetString("user"))
+	}
+
+	p.SetDefaults(map[string]interface{}{"retries": 3})
+	p.PreResolve()
+	if n, e := p.GetInt("retries"); e != nil || n != 3 {
+		t.Errorf("TestSetDefaults - PreResolve - expected: 3, got: %d, %v", n, e)
+	}
+
+	// prior defaults are replaced
+	if !reflect.DeepEqual(p.DefaultKeys(), []string{"retries"}) {
+		t.Errorf("TestSetDefaults - SetDefaults - expected stale defaults removed, got: %v", p.DefaultKeys())
+	}
+	q, _ := NewWithDefaults(map[string]interface{}{"mode": "dev"})
+	q.SetDefaults(map[string]interface{}{"level": "info"})
+	if q.Has("mode") || !reflect.DeepEqual(q.DefaultKeys(), []string{"level"}) {
+		t.Errorf("TestSetDefaults - SetDefaults - expected stale default mode removed, got: %v", q.DefaultKeys())
+	}
+
+	// defaults of from never overwrite values
+	from, _ := NewWithDefaults(map[string]interface{}{"port": 1, "mode": "prod"})
+	p.Copy(from, true)
+	if p.GetString("port") != "9090" || p.GetString("mode") != "prod" || !p.IsDefault("mode") {
+		t.Errorf("TestSetDefaults - Copy(overwrite) - expected defined port and default mode, got: %s, %s", p.GetString("port"), p.GetString("mode"))
+	}
+	if len(p) != 3 {
+		t.Errorf("TestSetDefaults - Copy(overwrite) - expected defaults not copied as keys, got: %v", p)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ----------------------------------------------------------------------
+// Derived keys
+//
+// With the Derived option, values that are arithmetic expressions over
+// (numeric) keys are computed, e.g.
+//
+//	pool.min = 8
+//	pool.max = ${pool.min} * 4            # => "32"
+//	pool.idle = (${pool.max} - 2) / 3.0   # => "10"
+//
+// Expressions consist of references, numbers, the operators + - * / %,
+// and parentheses, and include at least one reference and one operator.
+// Operands are integers, unless any is a float (e.g. 3.0), in which case
+// the result is a float. Integer division truncates, and % is only defined
+// for integers.
+//
+// Expressions referencing values that are not numbers (or references
+// that are not keys, e.g. ${env:HOME}) are not derived, and left as is.
+// ----------------------------------------------------------------------
+
+const expr_chars = "0123456789.+-*/%() \t"
+
+// errNotDerived is the error of values that are not (numeric) expressions
+var errNotDerived = errors.New("not derived")
+
+// Derived computes the values of keys that are arithmetic expressions on
+// load. See Properties#Derive.
+func Derived() LoadOption {
+	return func(o *loadOptions) {
+		o.derive = true
+	}
+}
+
+// Returns a copy of p with the values of keys that are arithmetic
+// expressions (see above) computed.
+//
+// Returns a *KeyError if an expression is malformed, cyclic, or divides
+// by zero.
+func (p Properties) Derive() (Properties, error) {
+	dp := p.Clone()
+	for _, k := range sortedKeys(p) {
+		s, ok := p[k].(string)
+		if !ok || !isExpr(s) {
+			continue
+		}
+		n, e := p.derive(k, nil)
+		if errors.Is(e, errNotDerived) {
+			continue
+		}
+		if e != nil {
+			return nil, &KeyError{k, e}
+		}
+		dp[k] = n.String()
+	}
+	return dp, nil
+}
+
+// returns true if s is (syntactically) an expression with at least one
+// reference and one operator.
+func isExpr(s string) bool {
+	if !strings.Contains(s, ref_open) {
+		return false
+	}
+	rest, ops := s, false
+	for rest != empty {
+		if strings.HasPrefix(rest, ref_open) {
+			j := strings.Index(rest, ref_close)
+			if j < 0 {
+				return false
+			}
+			rest = rest[j+len(ref_close):]
+			continue
+		}
+		if !strings.ContainsRune(expr_chars, rune(rest[0])) {
+			return false
+		}
+		ops = ops || strings.ContainsRune("+-*/%", rune(rest[0]))
+		rest = rest[1:]
+	}
+	return ops
+}
+
+// returns the numeric value of key, derived if an expression. path is
+// the chain of keys referencing key.
+func (p Properties) derive(key string, path []string) (number, error) {
+	for _, k := range path {
+		if k == key {
+			return number{}, fmt.Errorf("cyclic reference ${%s} - %s", key, strings.Join(append(path, key), " -> "))
+		}
+	}
+	s, ok := p.get(key).(string)
+	if !ok {
+		return number{}, errNotDerived
+	}
+	s = strings.Trim(s, ws)
+	if !isExpr(s) {
+		return parseNumber(s)
+	}
+	ev := &evaluator{p: p, path: append(path, key), s: s}
+	n, e := ev.expr()
+	if e == nil && ev.skipSpace() != empty {
+		e = fmt.Errorf("malformed expression <%s>", s)
+	}
+	return n, e
+}
+
+// number is an integer or float operand.
+type number struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+func parseNumber(s string) (number, error) {
+	if i, e := strconv.ParseInt(s, 10, 64); e == nil {
+		return number{i: i}, nil
+	}
+	if f, e := strconv.ParseFloat(s, 64); e == nil {
+		return number{f: f, isFloat: true}, nil
+	}
+	return number{}, errNotDerived
+}
+
+func (n number) float() float64 {
+	if n.isFloat {
+		return n.f
+	}
+	return float64(n.i)
+}
+
+func (n number) String() string {
+	if n.isFloat {
+		return strconv.FormatFloat(n.f, 'g', -1, 64)
+	}
+	return strconv.FormatInt(n.i, 10)
+}
+
+// returns the result of a op b
+func arith(a number, op byte, b number) (number, error) {
+	if a.isFloat || b.isFloat {
+		x, y := a.float(), b.float()
+		switch op {
+		case '+':
+			return number{f: x + y, isFloat: true}, nil
+		case '-':
+			return number{f: x - y, isFloat: true}, nil
+		case '*':
+			return number{f: x * y, isFloat: true}, nil
+		case '/':
+			if y == 0 {
+				return number{}, errors.New("division by zero")
+			}
+			return number{f: x / y, isFloat: true}, nil
+		}
+		return number{}, errors.New("% of float operands")
+	}
+	x, y := a.i, b.i
+	switch op {
+	case '+':
+		return number{i: x + y}, nil
+	case '-':
+		return number{i: x - y}, nil
+	case '*':
+		return number{i: x * y}, nil
+	}
+	if y == 0 {
+		return number{}, errors.New("division by zero")
+	}
+	if op == '/' {
+		return number{i: x / y}, nil
+	}
+	return number{i: x % y}, nil
+}
+
+// evaluator is a recursive descent evaluator of expression s:
+//
+//	expr   = term { (+|-) term }
+//	term   = factor { (*|/|%) factor }
+//	factor = number | ${ref} | ( expr ) | - factor
+type evaluator struct {
+	p    Properties
+	path []string
+	s    string
+}
+
+// skips leading white space, and returns the remaining expression
+func (ev *evaluator) skipSpace() string {
+	ev.s = strings.TrimLeft(ev.s, ws)
+	return ev.s
+}
+
+func (ev *evaluator) expr() (number, error) {
+	return ev.binary("+-", ev.term)
+}
+
+func (ev *evaluator) term() (number, error) {
+	return ev.binary("*/%", ev.factor)
+}
+
+// evaluates operands (per operand) of the operators ops, left to right
+func (ev *evaluator) binary(ops string, operand func() (number, error)) (number, error) {
+	a, e := operand()
+	if e != nil {
+		return a, e
+	}
+	for {
+		s := ev.skipSpace()
+		if s == empty || !strings.ContainsRune(ops, rune(s[0])) {
+			return a, nil
+		}
+		op := s[0]
+		ev.s = s[1:]
+		b, e := operand()
+		if e != nil {
+			return b, e
+		}
+		if a, e = arith(a, op, b); e != nil {
+			return a, e
+		}
+	}
+}
+
+func (ev *evaluator) factor() (number, error) {
+	s := ev.skipSpace()
+	switch {
+	case s == empty:
+		return number{}, errors.New("malformed expression - operand expected")
+	case s[0] == '-':
+		ev.s = s[1:]
+		n, e := ev.factor()
+		if e != nil {
+			return n, e
+		}
+		return arith(number{}, '-', n)
+	case s[0] == '(':
+		ev.s = s[1:]
+		n, e := ev.expr()
+		if e != nil {
+			return n, e
+		}
+		if s := ev.skipSpace(); s == empty || s[0] != ')' {
+			return n, errors.New("malformed expression - ')' expected")
+		}
+		ev.s = ev.s[1:]
+		return n, nil
+	case strings.HasPrefix(s, ref_open):
+		j := strings.Index(s, ref_close)
+		ref := s[len(ref_open):j]
+		ev.s = s[j+len(ref_close):]
+		if ev.p.get(ref) == nil {
+			return number{}, errNotDerived
+		}
+		return ev.p.derive(ref, ev.path)
+	}
+	i := 0
+	for i < len(s) && strings.ContainsRune("0123456789.", rune(s[i])) {
+		i++
+	}
+	if i == 0 {
+		return number{}, fmt.Errorf("malformed expression - unexpected <%c>", s[0])
+	}
+	ev.s = s[i:]
+	n, e := parseNumber(s[:i])
+	if e != nil {
+		return n, fmt.Errorf("malformed number <%s>", s[:i])
+	}
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package gestalt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDerived(t *testing.T) {
+	p, e := LoadStr(`
+pool.min = 8
+pool.max = ${pool.min} * 4
+pool.idle = (${pool.max} - 2) / 3.0
+pool.step = ${pool.max} / 5 + ${pool.max} % 5
+pool.neg = -${pool.min} + 1
+ratio = 1.5
+scaled = ${ratio} * 2
+zone = ${region}-${az}
+region = eu
+az = 1
+home = ${env:HOME}/x - 1
+`, Derived())
+	if e != nil {
+		t.Fatalf("TestDerived - LoadStr - %s", e)
+	}
+	for key, expected := range map[string]string{
+		"pool.max":  "32",
+		"pool.idle": "10",
+		"pool.step": "8",
+		"pool.neg":  "-7",
+		"scaled":    "3",
+		"zone":      "${region}-${az}",
+		"home":      "${env:HOME}/x - 1",
+	} {
+		if v := p.GetString(key); v != expected {
+			t.Errorf("TestDerived - %s - expected: %s, got: %s", key, expected, v)
+		}
+	}
+	if v, e := p.GetInt("pool.max"); e != nil || v != 32 {
+		t.Errorf("TestDerived - GetInt(pool.max) - expected: 32, got: %d, %v", v, e)
+	}
+
+	if p, _ := LoadStr("a = 2\nb = ${a} * 2\n"); p.GetString("b") != "${a} * 2" {
+		t.Errorf("TestDerived - without option - expected: ${a} * 2, got: %s", p.GetString("b"))
+	}
+}
+
+func TestDeriveErrors(t *testing.T) {
+	for spec, expected := range map[string]string{
+		"a = ${b} + 1\nb = ${a} + 1\n": "cyclic reference",
+		"a = 1\nb = ${a} / 0\n":        "division by zero",
+		"a = 1.5\nb = ${a} % 2\n":      "% of float operands",
+		"a = 1\nb = (${a} + 1\n":       "')' expected",
+		"a = 1\nb = ${a} + \n":         "operand expected",
+	} {
+		_, e := LoadStr(spec, Derived())
+		var ke *KeyError
+		if !errors.As(e, &ke) || !strings.Contains(e.Error(), expected) {
+			t.Errorf("TestDeriveErrors - %q - expected: %s, got: %v", spec, expected, e)
+		}
+	}
+}
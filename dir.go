@@ -0,0 +1,122 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// files loaded by LoadDir
+const dir_pattern = "*.conf"
+
+// Workers sets the maximum number of files read concurrently by LoadDir.
+// Only reads are concurrent; fragments are parsed sequentially. Default is
+// runtime.GOMAXPROCS(0).
+func Workers(n int) LoadOption {
+	return func(o *loadOptions) {
+		o.workers = n
+	}
+}
+
+// Instantiates a new Properties object merged from all `*.conf` files
+// (fragments) of the specified directory, e.g. a conf.d directory.
+//
+// Fragments are read concurrently (see Workers), but parsed sequentially,
+// in lexical order of file names, as one file: later fragments overwrite
+// keys of earlier ones, and extend (+=) their array and map keys, so a
+// fragment is parsed against the keys merged before it. The schema
+// directive of a fragment validates the keys merged so far. Loading stops
+// on the first error, or if ctx is done.
+func LoadDir(ctx context.Context, dirname string, opts ...LoadOption) (p Properties, e error) {
+	o := newLoadOptions(opts)
+	ctx, span := startSpan(ctx, o.tracer, "gestalt.load")
+	defer span.End()
+	span.SetAttribute("dir", dirname)
+	defer func() {
+		if e != nil {
+			span.RecordError(e)
+			return
+		}
+		span.SetAttribute("keys", len(p))
+	}()
+
+	filenames, e := o.fsys.Glob(filepath.Join(dirname, dir_pattern))
+	if e != nil {
+		return nil, fmt.Errorf("Error listing gestalt dir <%s> : %s", dirname, e)
+	}
+
+	workers := o.workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	contents := make([]string, len(filenames))
+	errs := make([]error, len(filenames))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if contents[i], errs[i] = readFile(o, filenames[i]); errs[i] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+feed:
+	for i := range filenames {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("fragment <%s> - %s", filenames[i], err)
+		}
+	}
+	if e = ctx.Err(); e != nil {
+		return nil, e
+	}
+
+	p = make(Properties)
+	for i, s := range contents {
+		if e = loadFragment(p, filenames[i], s, o); e != nil {
+			return nil, fmt.Errorf("fragment <%s> - %s", filenames[i], e)
+		}
+	}
+	return o.complete(p)
+}
+
+// parses the fragment s of filename into p. Empty fragments are allowed.
+func loadFragment(p Properties, filename string, s string, o *loadOptions) error {
+	if strings.Trim(s, trimset) == empty {
+		return nil
+	}
+	fo := *o
+	fo.dir = filepath.Dir(filename)
+	fo.into = p
+	fo.keyring, fo.derive, fo.policy = nil, false, nil // of the merged fragments
+	fo.logger = o.logger.With("file", filename)
+	if _, e := loadBuffer(s, &fo); e != nil {
+		return e
+	}
+	o.logger.Debug("gestalt: keys merged", "file", filename, "keys", len(p))
+	return nil
+}
@@ -0,0 +1,79 @@
+package gestalt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFragments(t *testing.T, fragments map[string]string) string {
+	dir := t.TempDir()
+	for name, spec := range fragments {
+		if e := os.WriteFile(filepath.Join(dir, name), []byte(spec), 0644); e != nil {
+			t.Fatalf("writeFragments - %s", e)
+		}
+	}
+	return dir
+}
+
+func TestLoadDir(t *testing.T) {
+	fragments := map[string]string{
+		"00-base.conf":  "foo = base\nhosts[] = a, b\n",
+		"50-empty.conf": "# nothing here\n",
+		"ignored.txt":   "foo = ignored\n",
+	}
+	for i := 10; i < 40; i++ {
+		fragments[fmt.Sprintf("%02d-frag.conf", i)] = fmt.Sprintf("foo = %d\nkey.%d = v\n", i, i)
+	}
+	dir := writeFragments(t, fragments)
+
+	prop, e := LoadDir(context.Background(), dir, Workers(4))
+	if e != nil {
+		t.Fatalf("TestLoadDir - LoadDir - %s", e)
+	}
+	if v := prop.GetString("foo"); v != "39" {
+		t.Errorf("TestLoadDir - GetString(foo) - expected: 39, got: %s", v)
+	}
+	if v := prop.GetString("key.10"); v != "v" {
+		t.Errorf("TestLoadDir - GetString(key.10) - expected: v, got: %s", v)
+	}
+	if v := prop.GetArray("hosts[]"); len(v) != 2 {
+		t.Errorf("TestLoadDir - GetArray(hosts[]) - expected: [a b], got: %s", v)
+	}
+}
+
+func TestLoadDirWithError(t *testing.T) {
+	dir := writeFragments(t, map[string]string{
+		"00-ok.conf":  "foo = bar\n",
+		"10-bad.conf": "foo bar\n",
+	})
+	if _, e := LoadDir(context.Background(), dir); e == nil {
+		t.Errorf("TestLoadDirWithError - LoadDir - error expected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, e := LoadDir(ctx, dir); e == nil {
+		t.Errorf("TestLoadDirWithError - LoadDir - error expected for cancelled context")
+	}
+}
+
+func TestLoadDirExtend(t *testing.T) {
+	dir := writeFragments(t, map[string]string{
+		"00a.conf": "servers[] = a, b\nm[:] = x:1\n",
+		"50m.conf": "m[:] += y:2\n",
+		"99z.conf": "servers[] += c\n",
+	})
+	prop, e := LoadDir(context.Background(), dir, Workers(2))
+	if e != nil {
+		t.Fatalf("TestLoadDirExtend - LoadDir - %s", e)
+	}
+	if v := prop.GetArray("servers[]"); fmt.Sprint(v) != "[a b c]" {
+		t.Errorf("TestLoadDirExtend - GetArray(servers[]) - expected: [a b c], got: %v", v)
+	}
+	if v := prop.GetMap("m[:]"); len(v) != 2 || v["x"] != "1" || v["y"] != "2" {
+		t.Errorf("TestLoadDirExtend - GetMap(m[:]) - expected: map[x:1 y:2], got: %v", v)
+	}
+}
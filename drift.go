@@ -0,0 +1,135 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DriftFunc is notified of the drift of held Properties from their source,
+// i.e. the changes from the held Properties to those of the source, or of
+// nil changes when they are back in sync, or of the error of a failed
+// load of the source.
+type DriftFunc func(changes []Change, e error)
+
+// DriftOption is an option of MonitorDrift.
+type DriftOption func(*DriftMonitor)
+
+// DriftClock sets the Clock of a DriftMonitor, e.g. a ManualClock for tests.
+func DriftClock(c Clock) DriftOption {
+	return func(m *DriftMonitor) {
+		m.clock = c
+	}
+}
+
+// RedactDrift redacts the values of sensitive keys of the changes of a
+// DriftMonitor. See RedactedDiff.
+func RedactDrift(sensitive Sensitive) DriftOption {
+	return func(m *DriftMonitor) {
+		m.sensitive = sensitive
+	}
+}
+
+// DriftMonitor periodically compares the Properties of a source (e.g. a
+// file) with those held in memory (e.g. by SafeProperties), and reports
+// their divergence, e.g. of a file edited while reloads are disabled.
+type DriftMonitor struct {
+	src       Source
+	h         Holder
+	fn        DriftFunc
+	clock     Clock
+	sensitive Sensitive
+
+	mu      sync.Mutex // serializes checks
+	changes []Change   // of the latest check
+
+	done   chan struct{}
+	stop   sync.Once
+	cancel context.CancelFunc
+}
+
+// Monitors the Properties of h for drift from those of src, checking every
+// interval, until ctx is done or the monitor is stopped. A non-positive
+// interval disables periodic checks (see Check). fn (if not nil)
+// is called when drift is detected, or changes, or is resolved, and when a
+// check fails to load src. Calls to fn are serialized. Drift is logged
+// (see SetLogger), and checks are traced (see SetTracer).
+func MonitorDrift(ctx context.Context, src Source, h Holder, interval time.Duration, fn DriftFunc, opts ...DriftOption) *DriftMonitor {
+	m := &DriftMonitor{src: src, h: h, fn: fn, clock: SystemClock, done: make(chan struct{})}
+	for _, opt := range opts {
+		opt(m)
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	var tick <-chan time.Time
+	stop := func() {}
+	if interval > 0 {
+		tick, stop = m.clock.NewTicker(interval)
+	}
+	go func() {
+		defer close(m.done)
+		defer stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+				m.Check(ctx)
+			}
+		}
+	}()
+	return m
+}
+
+// Checks for drift now. Returns the changes from the held Properties to
+// those of the source, or nil if in sync, or the error of loading the source.
+func (m *DriftMonitor) Check(ctx context.Context) ([]Change, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sctx, span := startSpan(ctx, nil, "gestalt.drift")
+	defer span.End()
+
+	p, e := m.src.Load(sctx)
+	if e != nil {
+		log().Debug("gestalt: drift check failed", "error", e)
+		span.RecordError(e)
+		m.notify(nil, e)
+		return nil, e
+	}
+	changes := RedactedDiff(m.h.Properties(), p, m.sensitive)
+	span.SetAttribute("changes", len(changes))
+	if reflect.DeepEqual(changes, m.changes) {
+		return changes, nil
+	}
+	if len(changes) > 0 {
+		log().Debug("gestalt: drift detected", "changes", len(changes))
+	} else {
+		log().Debug("gestalt: drift resolved")
+	}
+	m.changes = changes
+	m.notify(changes, nil)
+	return changes, nil
+}
+
+// Returns the changes of the latest check, or nil if in sync.
+func (m *DriftMonitor) Drift() []Change {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.changes
+}
+
+// Stops monitoring. Blocks until the monitor has exited.
+func (m *DriftMonitor) Stop() {
+	m.stop.Do(m.cancel)
+	<-m.done
+}
+
+func (m *DriftMonitor) notify(changes []Change, e error) {
+	if m.fn != nil {
+		m.fn(changes, e)
+	}
+}
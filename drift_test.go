@@ -0,0 +1,86 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDriftMonitor(t *testing.T) {
+	var mu sync.Mutex
+	src := Properties{"db.host": "a", "db.password": "x"}
+	source := SourceFunc(func(ctx context.Context) (Properties, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if src == nil {
+			return nil, errors.New("unavailable")
+		}
+		return src.Clone(), nil
+	})
+	set := func(p Properties) {
+		mu.Lock()
+		defer mu.Unlock()
+		src = p
+	}
+	held := NewSafeProperties(src)
+
+	type report struct {
+		changes []Change
+		e       error
+	}
+	reports := make(chan report, 10)
+	clock := NewManualClock(time.Now())
+	schema := &Schema{Keys: []KeySpec{{Key: "db.password", Secret: true}}}
+	m := MonitorDrift(context.Background(), source, held, time.Minute, func(changes []Change, e error) {
+		reports <- report{changes, e}
+	}, DriftClock(clock), RedactDrift(schema.Sensitive))
+	defer m.Stop()
+
+	expect := func(step string, expected []Change, failed bool) {
+		t.Helper()
+		clock.Advance(time.Minute)
+		select {
+		case r := <-reports:
+			if (r.e != nil) != failed || !reflect.DeepEqual(r.changes, expected) {
+				t.Errorf("TestDriftMonitor - %s - expected: %v (failed: %t), got: %v, %v", step, expected, failed, r.changes, r.e)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("TestDriftMonitor - %s - expected report", step)
+		}
+	}
+
+	if changes, e := m.Check(context.Background()); e != nil || changes != nil {
+		t.Errorf("TestDriftMonitor - Check - expected no drift, got: %v, %v", changes, e)
+	}
+	set(Properties{"db.host": "b", "db.password": "y"})
+	expect("edit", []Change{
+		{Key: "db.host", Kind: ChangeChanged, Old: "a", New: "b"},
+		{Key: "db.password", Kind: ChangeChanged, Redacted: true},
+	}, false)
+	if len(m.Drift()) != 2 {
+		t.Errorf("TestDriftMonitor - Drift - expected 2 changes, got: %v", m.Drift())
+	}
+	set(nil)
+	expect("unavailable", nil, true)
+	set(Properties{"db.host": "b", "db.password": "y"})
+	held.Replace(Properties{"db.host": "b", "db.password": "y"})
+	expect("resolved", nil, false)
+}
+
+func TestDriftMonitorNoInterval(t *testing.T) {
+	source := SourceFunc(func(ctx context.Context) (Properties, error) {
+		return Properties{"a": "1"}, nil
+	})
+	m := MonitorDrift(context.Background(), source, NewSafeProperties(Properties{"a": "2"}), 0, nil)
+	defer m.Stop()
+	if changes, e := m.Check(context.Background()); e != nil || len(changes) != 1 {
+		t.Errorf("TestDriftMonitorNoInterval - Check - expected drift of a, got: %v, %v", changes, e)
+	}
+}
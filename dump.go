@@ -0,0 +1,159 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Layer is a named source of Properties of a dump, e.g. a file.
+type Layer struct {
+	Name       string
+	Properties Properties
+}
+
+// DumpEntry is a key of a dump, with its value and provenance.
+type DumpEntry struct {
+	Key    string      `json:"key"`
+	Kind   string      `json:"kind"`
+	Type   string      `json:"type,omitempty"` // of the schema, if any
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"` // name of the layer defining the key
+}
+
+// Returns the entries of the merged view of layers, in increasing order of
+// precedence (see OverlayStack), sorted by key, for comparison of (e.g.)
+// environments. @unset keys are omitted. Values of keys described by schema
+// (if not nil) are converted per their type (e.g. 8080 rather than "8080",
+// and durations, regexps, etc. as strings). The values of secret keys, per
+// schema or marked secret by a layer (see MarkSecret), are redacted.
+// Returns a *KeyError if a value is not of its type.
+func Dump(layers []Layer, schema *Schema) ([]DumpEntry, error) {
+	return dump(layers, schema, false)
+}
+
+// Returns the entries of the resolved view of layers, as Dump, with all
+// references expanded (see OverlayStack.Resolve), per the interpolators in.
+// Values expanding references of a scheme, e.g. ${env:DB_PASSWORD}, which
+// commonly resolve to credentials, are redacted as well, unless schema
+// describes the key as not secret. Returns a *KeyError of ErrOverride if a
+// key is defined by a layer that may not override it, per schema (see
+// OverlayStack.Restrict).
+func DumpResolved(layers []Layer, schema *Schema, in ...Interpolator) ([]DumpEntry, error) {
+	return dump(layers, schema, true, in...)
+}
+
+func dump(layers []Layer, schema *Schema, resolved bool, in ...Interpolator) ([]DumpEntry, error) {
+	s := NewOverlayStack(nil)
+	keys := make(map[string]bool)
+	for _, l := range layers {
+		s.PushLayer(l.Name, l.Properties)
+		for k := range l.Properties {
+			keys[k] = true
+		}
+	}
+	s.Use(in...)
+	s.Restrict(schema)
+
+	var entries []DumpEntry
+	for _, k := range sortedKeys(keys) {
+		v := s.Get(k)
+		if v == nil {
+			continue
+		}
+		spec := schemaSpec(schema, k)
+		secret := spec != nil && spec.Secret
+		if resolved {
+			if spec == nil && s.schemeRefs(v, map[string]bool{k: true}) {
+				secret = true
+			}
+			var e error
+			if v, e = s.Resolve(k); e != nil {
+				return nil, e
+			}
+		}
+		entry := DumpEntry{Key: k, Kind: keyKind(k), Value: v}
+		for i := len(layers) - 1; i >= 0; i-- {
+			if layers[i].Properties.IsSecret(k) {
+				secret = true
+			}
+			if entry.Source == empty && layers[i].Properties.value(k) != nil {
+				entry.Source = layers[i].Name
+			}
+		}
+		if spec != nil {
+			entry.Type = spec.typeName()
+			tv, e := spec.convert(v)
+			if e != nil {
+				return nil, &KeyError{k, e}
+			}
+			entry.Value = dumpValue(tv)
+		}
+		if secret {
+			entry.Value = redacted
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// returns true if the (unresolved) value v references a scheme, e.g.
+// ${env:HOME}, directly or by the keys it references. seen are the keys
+// visited.
+func (s *OverlayStack) schemeRefs(v interface{}, seen map[string]bool) bool {
+	found := false
+	expandValue(v, func(ref string) (string, error) {
+		switch rv := s.lookup(ref); {
+		case rv == nil:
+			found = found || strings.Contains(ref, kv_delim)
+		case !seen[ref]:
+			seen[ref] = true
+			found = found || s.schemeRefs(rv, seen)
+		}
+		return empty, nil
+	})
+	return found
+}
+
+// returns the spec of key, or nil if schema is nil or does not describe key
+func schemaSpec(schema *Schema, key string) *KeySpec {
+	if schema == nil {
+		return nil
+	}
+	return schema.Spec(key)
+}
+
+// returns the JSON representation of the typed value v
+func dumpValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = dumpValue(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = dumpValue(v[k])
+		}
+	case fmt.Stringer:
+		return v.String()
+	}
+	return v
+}
+
+// Writes entries to w, as JSON lines, i.e. one (compact) object per line,
+// so that dumps diff line by line.
+func WriteDump(w io.Writer, entries []DumpEntry) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, entry := range entries {
+		if e := enc.Encode(entry); e != nil {
+			return e
+		}
+	}
+	return nil
+}
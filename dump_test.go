@@ -0,0 +1,95 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	base, _ := LoadStr(`
+db.host = localhost
+db.port = 5432
+db.url = postgres://${db.host}:${db.port}
+db.password = secret
+timeouts[:] = read:5s, write:10s
+legacy = x
+`)
+	prod, _ := LoadStr(`
+db.host = db.prod
+legacy = @unset
+`)
+	layers := []Layer{{"base.conf", base}, {"prod.conf", prod}}
+	schema := &Schema{Keys: []KeySpec{
+		{Key: "db.port", Type: TypeInt},
+		{Key: "db.password", Secret: true},
+		{Key: "timeouts[:]", Type: TypeDuration},
+	}}
+
+	entries, e := DumpResolved(layers, schema)
+	if e != nil {
+		t.Fatalf("TestDump - DumpResolved - unexpected error: %s", e)
+	}
+	expected := []DumpEntry{
+		{"db.host", KindString, "", "db.prod", "prod.conf"},
+		{"db.password", KindString, TypeString, redacted, "base.conf"},
+		{"db.port", KindString, TypeInt, 5432, "base.conf"},
+		{"db.url", KindString, "", "postgres://db.prod:5432", "base.conf"},
+		{"timeouts[:]", KindMap, TypeDuration, map[string]interface{}{"read": "5s", "write": "10s"}, "base.conf"},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("TestDump - DumpResolved - expected: %v, got: %v", expected, entries)
+	}
+
+	entries, _ = Dump(layers, nil)
+	if entries[3].Value != "postgres://${db.host}:${db.port}" {
+		t.Errorf("TestDump - Dump - expected unresolved db.url, got: %v", entries[3])
+	}
+
+	var buf bytes.Buffer
+	WriteDump(&buf, entries[:2])
+	if s := buf.String(); s != `{"key":"db.host","kind":"string","value":"db.prod","source":"prod.conf"}
+{"key":"db.password","kind":"string","value":"secret","source":"base.conf"}
+` {
+		t.Errorf("TestDump - WriteDump - got: %s", s)
+	}
+
+	bad, _ := LoadStr("db.port = x\n")
+	if _, e := Dump([]Layer{{"bad.conf", bad}}, schema); e == nil {
+		t.Errorf("TestDump - Dump - expected error of malformed db.port")
+	}
+}
+
+func TestDumpRedacted(t *testing.T) {
+	p, _ := LoadStr(`
+api.token = ${vault:app/token}
+api.auth = Bearer ${api.token}
+api.region = ${vault:app/region}
+api.host = api.local
+salt = 0a0b
+`)
+	p.MarkSecret("salt")
+	vault := SchemeInterpolator("vault", func(path string) (string, error) { return "s3cret", nil })
+	schema := &Schema{Keys: []KeySpec{{Key: "api.region"}}}
+
+	entries, e := DumpResolved([]Layer{{"app.conf", p}}, schema, vault)
+	if e != nil {
+		t.Fatalf("TestDumpRedacted - DumpResolved - unexpected error: %s", e)
+	}
+	expected := map[string]interface{}{
+		"api.auth":   redacted,
+		"api.host":   "api.local",
+		"api.region": "s3cret", // described by schema as not secret
+		"api.token":  redacted,
+		"salt":       redacted,
+	}
+	for _, entry := range entries {
+		if entry.Value != expected[entry.Key] {
+			t.Errorf("TestDumpRedacted - DumpResolved(%s) - expected: %v, got: %v", entry.Key, expected[entry.Key], entry.Value)
+		}
+	}
+}
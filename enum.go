@@ -0,0 +1,33 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"strings"
+)
+
+// Enum value property - returns the constant mapped to the (string) value
+// of key by allowed, e.g.
+//
+//	level, e := p.GetEnum("log.level", map[string]int{"debug": Debug, "info": Info})
+//
+// Returns error if no such key or value is not allowed.
+func (p Properties) GetEnum(key string, allowed map[string]int) (int, error) {
+	return GetEnum(p, key, allowed)
+}
+
+// Enum value property of application type T. See Properties.GetEnum.
+func GetEnum[T any](p Properties, key string, allowed map[string]T) (T, error) {
+	var t T
+	s, e := p.stringValue(key)
+	if e != nil {
+		return t, e
+	}
+	t, ok := allowed[s]
+	if !ok {
+		return t, wrongType("key <%s> - invalid value <%s> - allowed values are %s", key, s, strings.Join(sortedKeys(allowed), ", "))
+	}
+	return t, nil
+}
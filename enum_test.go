@@ -0,0 +1,34 @@
+package gestalt
+
+import (
+	"strings"
+	"testing"
+)
+
+type level uint8
+
+const (
+	debug level = iota
+	info
+	warn
+)
+
+func TestGetEnum(t *testing.T) {
+	p, _ := LoadStr("log.level = warn\nbad.level = trace\n")
+	allowed := map[string]int{"debug": 0, "info": 1, "warn": 2}
+	if v, e := p.GetEnum("log.level", allowed); e != nil || v != 2 {
+		t.Errorf("TestGetEnum - GetEnum(log.level) - expected: 2, got: %d, %v", v, e)
+	}
+	_, e := p.GetEnum("bad.level", allowed)
+	if e == nil || !strings.Contains(e.Error(), "allowed values are debug, info, warn") {
+		t.Errorf("TestGetEnum - GetEnum(bad.level) - expected allowed values error, got: %v", e)
+	}
+	if _, e := p.GetEnum("nope", allowed); e == nil {
+		t.Errorf("TestGetEnum - GetEnum(nope) - error expected")
+	}
+
+	levels := map[string]level{"debug": debug, "info": info, "warn": warn}
+	if v, e := GetEnum(p, "log.level", levels); e != nil || v != warn {
+		t.Errorf("TestGetEnum - GetEnum[level](log.level) - expected: %d, got: %d, %v", warn, v, e)
+	}
+}
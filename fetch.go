@@ -0,0 +1,110 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// Guarded fetching
+//
+// remote sources (e.g. BlobSource, RedisSource) are guarded against
+// misbehaving endpoints by GuardedSource, which caps the frequency of
+// fetches and breaks the circuit after repeated failures, e.g.
+//
+//	src, _ := gestalt.BlobSource("s3://bucket/app.conf", gestalt.MaxContentSize(1<<20))
+//	src = gestalt.GuardedSource(src, gestalt.MinFetchInterval(time.Minute), gestalt.BreakAfter(3, 5*time.Minute))
+//
+// the size of fetched content is capped by the MaxContentSize option of
+// the source.
+// ----------------------------------------------------------------------
+
+// ErrCircuitOpen is the error of loads of a GuardedSource failing fast,
+// after repeated failures. See BreakAfter.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// FetchOption is an option of GuardedSource.
+type FetchOption func(*guardedSource)
+
+// MinFetchInterval caps the frequency of fetches to one per d. Loads within
+// d of the latest fetch return its result.
+func MinFetchInterval(d time.Duration) FetchOption {
+	return func(s *guardedSource) {
+		s.every = d
+	}
+}
+
+// BreakAfter breaks the circuit after n consecutive failed fetches: loads
+// then fail fast with ErrCircuitOpen, without fetching, for cooldown,
+// after which a single fetch is tried, closing the circuit if it succeeds,
+// or breaking it again if not.
+func BreakAfter(n int, cooldown time.Duration) FetchOption {
+	return func(s *guardedSource) {
+		s.threshold, s.cooldown = n, cooldown
+	}
+}
+
+// FetchClock sets the Clock of the fetch intervals and cooldowns of a
+// GuardedSource, e.g. a ManualClock of tests. Default is SystemClock.
+func FetchClock(c Clock) FetchOption {
+	return func(s *guardedSource) {
+		s.clock = c
+	}
+}
+
+// Returns a Source loading src, guarded per opts. Loads are serialized.
+func GuardedSource(src Source, opts ...FetchOption) Source {
+	s := &guardedSource{src: src, clock: SystemClock}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type guardedSource struct {
+	src       Source
+	clock     Clock
+	every     time.Duration // see MinFetchInterval
+	threshold int           // see BreakAfter
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	fetched  time.Time // of the latest fetch
+	p        Properties
+	e        error     // of the latest fetch
+	failures int       // consecutive
+	open     time.Time // of the latest break of the circuit, if open
+}
+
+func (s *guardedSource) Load(ctx context.Context) (Properties, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if !s.open.IsZero() && now.Sub(s.open) < s.cooldown {
+		return nil, fmt.Errorf("%w - %w", ErrCircuitOpen, s.e)
+	}
+	if s.every > 0 && !s.fetched.IsZero() && now.Sub(s.fetched) < s.every {
+		return s.p, s.e
+	}
+
+	s.p, s.e = s.src.Load(ctx)
+	s.fetched = now
+	if s.e == nil {
+		s.failures, s.open = 0, time.Time{}
+		return s.p, nil
+	}
+	s.failures++
+	if s.threshold > 0 && (s.failures >= s.threshold || !s.open.IsZero()) {
+		log().Warn("gestalt: fetch circuit open", "failures", s.failures, "cooldown", s.cooldown, "error", s.e)
+		s.open = now
+	}
+	return nil, s.e
+}
@@ -0,0 +1,54 @@
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGuardedSource(t *testing.T) {
+	errDown := errors.New("endpoint down")
+	var fetches int
+	var fail bool
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		fetches++
+		if fail {
+			return nil, errDown
+		}
+		return Properties{"n": "1"}, nil
+	})
+	clock := NewManualClock(time.Unix(0, 0))
+	g := GuardedSource(src, MinFetchInterval(time.Minute), BreakAfter(2, 10*time.Minute), FetchClock(clock))
+
+	for i, c := range []struct {
+		advance  time.Duration
+		fail     bool
+		fetches  int
+		expected error
+		open     bool
+	}{
+		{0, false, 1, nil, false},
+		{30 * time.Second, false, 1, nil, false}, // within interval
+		{time.Minute, false, 2, nil, false},
+		{time.Minute, true, 3, errDown, false},
+		{time.Minute, true, 4, errDown, false}, // breaks
+		{5 * time.Minute, true, 4, errDown, true},
+		{5 * time.Minute, true, 5, errDown, false}, // trial fails, and breaks again
+		{time.Minute, false, 5, errDown, true},
+		{10 * time.Minute, false, 6, nil, false}, // trial succeeds, and closes
+		{time.Minute, true, 7, errDown, false},
+	} {
+		clock.Advance(c.advance)
+		fail = c.fail
+		p, e := g.Load(context.Background())
+		switch {
+		case fetches != c.fetches:
+			t.Errorf("TestGuardedSource - Load %d - expected fetches: %d, got: %d", i, c.fetches, fetches)
+		case c.expected == nil && (e != nil || p.GetString("n") != "1"):
+			t.Errorf("TestGuardedSource - Load %d - expected: n = 1, got: %v, %v", i, p, e)
+		case !errors.Is(e, c.expected) || errors.Is(e, ErrCircuitOpen) != c.open:
+			t.Errorf("TestGuardedSource - Load %d - expected: %v (open: %t), got: %v", i, c.expected, c.open, e)
+		}
+	}
+}
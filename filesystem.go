@@ -0,0 +1,55 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"io/fs"
+)
+
+// FileSystem provides the files loaded by e.g. Load and LoadDir.
+// See OSFileSystem and FS.
+type FileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	// Returns the (sorted) names of files matching pattern.
+	// See filepath.Glob.
+	Glob(pattern string) ([]string, error)
+}
+
+type osFileSystem struct{}
+
+func (osFileSystem) ReadFile(name string) ([]byte, error) { return readHostFile(name) }
+
+func (osFileSystem) Glob(pattern string) ([]string, error) { return globFiles(pattern) }
+
+func (osFileSystem) Open(name string) (fs.File, error) { return openHostFile(name) }
+
+// OSFileSystem is the FileSystem of the operating system. On targets
+// without an OS (js/wasm and tinygo), reads fail, and files are provided
+// by a FileSystem of WithFileSystem, e.g. of an embed.FS.
+var OSFileSystem FileSystem = osFileSystem{}
+
+type ioFileSystem struct {
+	fsys fs.FS
+}
+
+func (f ioFileSystem) ReadFile(name string) ([]byte, error) { return fs.ReadFile(f.fsys, name) }
+
+func (f ioFileSystem) Glob(pattern string) ([]string, error) { return fs.Glob(f.fsys, pattern) }
+
+func (f ioFileSystem) Open(name string) (fs.File, error) { return f.fsys.Open(name) }
+
+// Returns the FileSystem of fsys, e.g. an embed.FS or fstest.MapFS.
+// Names are per fs.FS, i.e. unrooted and slash-separated.
+func FS(fsys fs.FS) FileSystem {
+	return ioFileSystem{fsys}
+}
+
+// WithFileSystem sets the FileSystem of loaded files, e.g. for tests.
+// nil fsys is the OSFileSystem.
+func WithFileSystem(fsys FileSystem) LoadOption {
+	return func(o *loadOptions) {
+		o.fsys = fsys
+	}
+}
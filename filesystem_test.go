@@ -0,0 +1,33 @@
+package gestalt
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileSystem(t *testing.T) {
+	fsys := FS(fstest.MapFS{
+		"app.conf":         {Data: []byte("db.host = localhost\n")},
+		"conf.d/10-a.conf": {Data: []byte("a = 1\nb = 1\n")},
+		"conf.d/20-b.conf": {Data: []byte("b = 2\n")},
+	})
+
+	p, e := Load("app.conf", WithFileSystem(fsys))
+	if e != nil || p.GetString("db.host") != "localhost" {
+		t.Errorf("TestFileSystem - Load(app.conf) - got: %v, %v", p, e)
+	}
+	if _, e := Load("nope.conf", WithFileSystem(fsys)); e == nil {
+		t.Errorf("TestFileSystem - Load(nope.conf) - error expected")
+	}
+
+	p, e = LoadDir(context.Background(), "conf.d", WithFileSystem(fsys))
+	if e != nil || p.GetString("a") != "1" || p.GetString("b") != "2" {
+		t.Errorf("TestFileSystem - LoadDir(conf.d) - got: %v, %v", p, e)
+	}
+
+	p, e = FileSource("app.conf", WithFileSystem(fsys)).Load(context.Background())
+	if e != nil || p.GetString("db.host") != "localhost" {
+		t.Errorf("TestFileSystem - FileSource(app.conf) - got: %v, %v", p, e)
+	}
+}
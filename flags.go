@@ -0,0 +1,133 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Feature flags
+//
+// Flags are defined by the entries of a map property, e.g.
+//
+//	flags[:] = dark.mode:on, search.v2:25%|alice|bob, beta:off|carol
+//
+// The spec of a flag is a `|` separated list of terms, and the flag is
+// enabled for a subject if any term matches:
+//
+//	on, true    enabled for all subjects
+//	off, false  matches no subject
+//	N%          enabled for (a stable) N percent of subjects
+//	<id>        enabled for the subject with the specified id
+// ----------------------------------------------------------------------
+
+const (
+	flag_sep     = "|"
+	flag_percent = "%"
+)
+
+// Flags is a set of feature flags. See NewFlags.
+//
+// Flags is safe for concurrent use, and can be updated on reload (see
+// Flags.OnChange).
+type Flags struct {
+	key   string
+	specs atomic.Pointer[map[string]*flagSpec]
+}
+
+type flagSpec struct {
+	all     bool
+	percent float64
+	allow   map[string]bool
+}
+
+// Instantiates new Flags per the (map) key of p.
+// Returns error if key is not a map key, or a flag spec is malformed.
+// Flags of an undefined key are all disabled.
+func NewFlags(p Properties, key string) (*Flags, error) {
+	if !isMapKey(key) {
+		return nil, wrongType("key <%s> is not a map key", key)
+	}
+	f := &Flags{key: key}
+	if e := f.Update(p); e != nil {
+		return nil, e
+	}
+	return f, nil
+}
+
+// Updates the flags per p. The flags are unchanged if p is not valid.
+func (f *Flags) Update(p Properties) error {
+	specs := make(map[string]*flagSpec)
+	for name, srep := range p.GetMap(f.key) {
+		spec, e := parseFlagSpec(srep)
+		if e != nil {
+			return fmt.Errorf("key <%s> - flag <%s> - %s", f.key, name, e)
+		}
+		specs[name] = spec
+	}
+	f.specs.Store(&specs)
+	return nil
+}
+
+// OnChange updates the flags per p. It is a ChangeFunc, e.g.
+//
+//	w, e := Watch(ctx, src, time.Minute, flags.OnChange)
+//
+// Reload errors and invalid flags are ignored, and the flags are retained.
+func (f *Flags) OnChange(p Properties, e error) {
+	if e == nil {
+		f.Update(p)
+	}
+}
+
+// Returns the (sorted) names of the flags.
+func (f *Flags) Names() []string {
+	return sortedKeys(*f.specs.Load())
+}
+
+// Returns true if the named flag is enabled for the subject, e.g. a user
+// or tenant id. Undefined flags are disabled. Percentage rollouts are
+// stable per flag and subject.
+func (f *Flags) IsEnabled(name string, subjectID string) bool {
+	spec := (*f.specs.Load())[name]
+	switch {
+	case spec == nil:
+		return false
+	case spec.all, spec.allow[subjectID]:
+		return true
+	case spec.percent > 0:
+		h := fnv.New32a()
+		h.Write([]byte(name + flag_sep + subjectID))
+		return float64(h.Sum32()%10000) < spec.percent*100
+	}
+	return false
+}
+
+func parseFlagSpec(srep string) (*flagSpec, error) {
+	spec := &flagSpec{allow: make(map[string]bool)}
+	for _, term := range strings.Split(srep, flag_sep) {
+		term = strings.Trim(term, ws)
+		switch {
+		case term == "":
+			return nil, fmt.Errorf("empty term in <%s>", srep)
+		case term == "on" || term == "true":
+			spec.all = true
+		case term == "off" || term == "false":
+		case strings.HasSuffix(term, flag_percent):
+			pct, e := strconv.ParseFloat(strings.TrimSuffix(term, flag_percent), 64)
+			if e != nil || pct < 0 || pct > 100 {
+				return nil, fmt.Errorf("invalid percentage <%s>", term)
+			}
+			spec.percent = pct
+		default:
+			spec.allow[term] = true
+		}
+	}
+	return spec, nil
+}
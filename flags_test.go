@@ -0,0 +1,63 @@
+package gestalt
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFlags(t *testing.T) {
+	p, _ := LoadStr("flags[:] = dark.mode:on, search.v2:25%|alice|bob, beta:off|carol\n")
+	f, e := NewFlags(p, "flags[:]")
+	if e != nil {
+		t.Fatalf("TestFlags - NewFlags - %s", e)
+	}
+	if names := f.Names(); !reflect.DeepEqual(names, []string{"beta", "dark.mode", "search.v2"}) {
+		t.Errorf("TestFlags - Names() - got: %v", names)
+	}
+
+	for _, c := range []struct {
+		name, subject string
+		expected      bool
+	}{
+		{"dark.mode", "anyone", true},
+		{"beta", "carol", true},
+		{"beta", "dave", false},
+		{"search.v2", "alice", true},
+		{"nope", "alice", false},
+	} {
+		if v := f.IsEnabled(c.name, c.subject); v != c.expected {
+			t.Errorf("TestFlags - IsEnabled(%s, %s) - expected: %t, got: %t", c.name, c.subject, c.expected, v)
+		}
+	}
+
+	// rollout is stable and roughly proportional
+	enabled := 0
+	for i := 0; i < 10000; i++ {
+		subject := fmt.Sprintf("user-%d", i)
+		v := f.IsEnabled("search.v2", subject)
+		if v != f.IsEnabled("search.v2", subject) {
+			t.Fatalf("TestFlags - IsEnabled(search.v2, %s) - unstable", subject)
+		}
+		if v {
+			enabled++
+		}
+	}
+	if enabled < 2000 || enabled > 3000 {
+		t.Errorf("TestFlags - IsEnabled(search.v2) - expected ~25%% enabled, got: %d of 10000", enabled)
+	}
+
+	// reload
+	q, _ := LoadStr("flags[:] = dark.mode:off\n")
+	f.OnChange(q, nil)
+	if f.IsEnabled("dark.mode", "anyone") || f.IsEnabled("beta", "carol") {
+		t.Errorf("TestFlags - OnChange - expected flags to be updated")
+	}
+	bad, _ := LoadStr("flags[:] = dark.mode:150%\n")
+	if e := f.Update(bad); e == nil {
+		t.Errorf("TestFlags - Update - error expected for invalid percentage")
+	}
+	if _, e := NewFlags(p, "flags"); e == nil {
+		t.Errorf("TestFlags - NewFlags(flags) - error expected for non-map key")
+	}
+}
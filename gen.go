@@ -0,0 +1,211 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ----------------------------------------------------------------------
+// Code generation
+//
+// GenerateGo generates a Go package of typed access to the keys of a
+// schema. Keys are mapped to Go names by their words (runs of letters and
+// digits), e.g. "db.host" and "db.hosts[]" to DbHost and DbHosts. The
+// package defines:
+//
+//	const KeyDbHost = "db.host"      key name constants
+//	var Schema                       the schema
+//	type Config struct { ... }       typed fields of the keys
+//	func Load(filename, opts...)     loads filename as *Config
+//	func New(p)                      returns the *Config of p
+//	func Validate(p)                 validates p against Schema
+//
+// WriteKeyConsts generates only the key name constants, e.g. of the keys
+// of existing configuration files.
+// ----------------------------------------------------------------------
+
+// Go types and getters of schema types, by kind
+var gen_types = map[string]map[string][2]string{
+	KindString: {
+		TypeString:    {"string", "gestalt.As[string](p, %s)"},
+		TypeInt:       {"int", "p.GetInt(%s)"},
+		TypeBool:      {"bool", "p.GetBool(%s)"},
+		TypeDuration:  {"time.Duration", "p.GetDuration(%s)"},
+		TypeRegexp:    {"*regexp.Regexp", "p.GetRegexp(%s)"},
+		TypeGlob:      {"*gestalt.Glob", "p.GetGlob(%s)"},
+		TypeMediaType: {"string", "p.GetMediaType(%s)"},
+		TypeCharset:   {"string", "p.GetCharset(%s)"},
+		TypeHostPort:  {"string", "gestalt.As[string](p, %s)"},
+		TypeLocation:  {"*time.Location", "p.GetLocation(%s)"},
+		TypeSemver:    {"gestalt.Semver", "p.GetSemver(%s)"},
+	},
+	KindArray: {
+		TypeString:    {"[]string", "gestalt.As[[]string](p, %s)"},
+		TypeDuration:  {"[]time.Duration", "p.GetDurationArray(%s)"},
+		TypeGlob:      {"[]*gestalt.Glob", "p.GetGlobArray(%s)"},
+		TypeMediaType: {"[]string", "gestalt.As[[]string](p, %s)"},
+	},
+	KindMap: {
+		TypeString:    {"map[string]string", "gestalt.As[map[string]string](p, %s)"},
+		TypeDuration:  {"map[string]time.Duration", "p.GetDurationMap(%s)"},
+		TypeMediaType: {"map[string]string", "p.GetMediaTypeMap(%s)"},
+	},
+}
+
+// Returns the Go name of key, e.g. DbHost of "db.host".
+func GoName(key string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(key, func(c rune) bool {
+		return !(unicode.IsLetter(c) || unicode.IsDigit(c))
+	}) {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return "K" + b.String()
+	}
+	return b.String()
+}
+
+// Generates the Go source of package pkg of typed access to the keys of
+// the schema (see above), written to w.
+//
+// Returns a *KeyError if the Go names of keys collide, or the type of an
+// array or map key is not supported (e.g. int).
+func (s *Schema) GenerateGo(w io.Writer, pkg string) error {
+	var consts, schema, fields, getters bytes.Buffer
+	names := make(map[string]string)
+	imports := map[string]bool{}
+	for i := range s.Keys {
+		spec := &s.Keys[i]
+		name := GoName(spec.Key)
+		if other, ok := names[name]; ok {
+			return &KeyError{spec.Key, fmt.Errorf("Go name %s collides with key <%s>", name, other)}
+		}
+		names[name] = spec.Key
+		gt, ok := gen_types[keyKind(spec.Key)][spec.typeName()]
+		if spec.Unit != "" && keyKind(spec.Key) == KindString {
+			gt, ok = [2]string{"float64", "p.GetQuantity(%s, " + strconv.Quote(spec.Unit) + ")"}, true
+		}
+		if !ok {
+			return &KeyError{spec.Key, fmt.Errorf("type %s of %s keys is not supported", spec.typeName(), keyKind(spec.Key))}
+		}
+		for _, pkg := range []string{"time", "regexp"} {
+			if strings.Contains(gt[0], pkg+".") {
+				imports[pkg] = true
+			}
+		}
+
+		doc := genDoc(spec.Doc)
+		fmt.Fprintf(&consts, "%sKey%s = %q\n", doc, name, spec.Key)
+		fmt.Fprintf(&schema, "{Key: Key%s, Type: %q, Unit: %q, Required: %t, Secret: %t, Default: %q, Doc: %q",
+			name, spec.typeName(), spec.Unit, spec.Required, spec.Secret, spec.Default, spec.Doc)
+		if spec.Layers != nil {
+			fmt.Fprintf(&schema, ", Layers: %#v", spec.Layers)
+		}
+		schema.WriteString("},\n")
+		fmt.Fprintf(&fields, "%s%s %s\n", doc, name, gt[0])
+		fmt.Fprintf(&getters, "if p.Has(Key%s) {\nif c.%s, e = %s; e != nil {\nreturn nil, e\n}\n}\n",
+			name, name, fmt.Sprintf(gt[1], "Key"+name))
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by gestalt gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "// Package %s provides typed access to its configuration.\n", pkg)
+	fmt.Fprintf(&src, "package %s\n\nimport (\n\"errors\"\n", pkg)
+	for _, pkg := range sortedKeys(imports) {
+		fmt.Fprintf(&src, "%q\n", pkg)
+	}
+	fmt.Fprintf(&src, "\n\"github.com/alphazero/gestalt\"\n)\n\n")
+	fmt.Fprintf(&src, "// key names\nconst (\n%s)\n\n", consts.String())
+	fmt.Fprintf(&src, "// Schema of the configuration.\nvar Schema = &gestalt.Schema{Strict: %t, Keys: []gestalt.KeySpec{\n%s}}\n\n", s.Strict, schema.String())
+	fmt.Fprintf(&src, "// Config is the typed configuration.\ntype Config struct {\n%s}\n\n", fields.String())
+	fmt.Fprint(&src, `// Loads the Config of the specified file. See New.
+func Load(filename string, opts ...gestalt.LoadOption) (*Config, error) {
+	p, e := gestalt.Load(filename, opts...)
+	if e != nil {
+		return nil, e
+	}
+	return New(p)
+}
+
+// Validates p against Schema.
+func Validate(p gestalt.Properties) error {
+	return errors.Join(Schema.Validate(p)...)
+}
+
+// Returns the Config of p, with the defaults of Schema applied. Fields of
+// undefined keys are zero valued. Returns error if p is not valid.
+func New(p gestalt.Properties) (c *Config, e error) {
+	if p, e = Schema.ApplyDefaults(p); e != nil {
+		return nil, e
+	}
+	if e = Validate(p); e != nil {
+		return nil, e
+	}
+	c = &Config{}
+`)
+	fmt.Fprintf(&src, "%sreturn c, nil\n}\n", getters.String())
+
+	b, e := format.Source(src.Bytes())
+	if e != nil {
+		return fmt.Errorf("gestalt gen - %s", e)
+	}
+	_, e = w.Write(b)
+	return e
+}
+
+// returns doc as Go comment lines
+func genDoc(doc string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(doc, "\n") {
+		if line = strings.Trim(line, trimset); line != empty {
+			fmt.Fprintf(&b, "// %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// Generates the Go source of the key name constants (see GoName) of the
+// keys of p, in package pkg, written to w. Array and map keys are commented
+// with their kind, e.g.
+//
+//	KeyDbHosts = "db.hosts[]" // array
+//
+// Returns a *KeyError if the Go names of keys collide.
+func WriteKeyConsts(w io.Writer, pkg string, p Properties) error {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by gestalt consts. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n// key names\nconst (\n", pkg)
+	names := make(map[string]string)
+	for _, key := range p.Keys() {
+		name := GoName(key)
+		if other, ok := names[name]; ok {
+			return &KeyError{key, fmt.Errorf("Go name %s collides with key <%s>", name, other)}
+		}
+		names[name] = key
+		fmt.Fprintf(&src, "Key%s = %q", name, key)
+		if kind := keyKind(key); kind != KindString {
+			fmt.Fprintf(&src, " // %s", kind)
+		}
+		fmt.Fprintln(&src)
+	}
+	fmt.Fprintln(&src, ")")
+
+	b, e := format.Source(src.Bytes())
+	if e != nil {
+		return fmt.Errorf("gestalt consts - %s", e)
+	}
+	_, e = w.Write(b)
+	return e
+}
@@ -0,0 +1,106 @@
+package gestalt
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGoName(t *testing.T) {
+	for key, expected := range map[string]string{
+		"db.host":       "DbHost",
+		"db.hosts[]":    "DbHosts",
+		"http.max_idle": "HttpMaxIdle",
+		"limits[:]":     "Limits",
+		"prop one":      "PropOne",
+		"2fa.enabled":   "K2faEnabled",
+	} {
+		if name := GoName(key); name != expected {
+			t.Errorf("TestGoName - GoName(%s) - expected: %s, got: %s", key, expected, name)
+		}
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	schema, e := LoadSchemaStr(`
+[document:db.host]
+required = true
+doc = host of the database server
+---
+[document:db.port]
+type = int
+default = 5432
+---
+[document:db.timeouts[]]
+type = duration
+---
+[document:db.pattern]
+type = regexp
+---
+[document:db.timeout]
+unit = ms
+`)
+	if e != nil {
+		t.Fatalf("TestGenerateGo - LoadSchemaStr - %s", e)
+	}
+	var buf bytes.Buffer
+	if e := schema.GenerateGo(&buf, "config"); e != nil {
+		t.Fatalf("TestGenerateGo - %s", e)
+	}
+	src := buf.String()
+	if _, e := parser.ParseFile(token.NewFileSet(), "config.go", src, 0); e != nil {
+		t.Fatalf("TestGenerateGo - generated source - %s\n%s", e, src)
+	}
+	for _, expected := range []string{
+		"package config",
+		`"regexp"`,
+		`"time"`,
+		"// host of the database server\n\tKeyDbHost ",
+		"DbTimeouts []time.Duration",
+		"c.DbTimeout, e = p.GetQuantity(KeyDbTimeout, \"ms\")",
+		"DbPattern  *regexp.Regexp",
+		"c.DbPort, e = p.GetInt(KeyDbPort)",
+		`{Key: KeyDbPort, Type: "int", Unit: "", Required: false, Secret: false, Default: "5432", Doc: ""}`,
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("TestGenerateGo - expected: %s, got:\n%s", expected, src)
+		}
+	}
+
+	for _, invalid := range []*Schema{
+		{Keys: []KeySpec{{Key: "db.host"}, {Key: "db_host"}}},
+		{Keys: []KeySpec{{Key: "ports[]", Type: TypeInt}}},
+	} {
+		if e := invalid.GenerateGo(&buf, "config"); e == nil {
+			t.Errorf("TestGenerateGo - %v - error expected", invalid.Keys)
+		}
+	}
+}
+
+func TestWriteKeyConsts(t *testing.T) {
+	p := Properties{"db.host": "localhost", "db.hosts[]": []string{"a"}, "limits[:]": map[string]string{}, "gone": unsetValue{}}
+	var buf bytes.Buffer
+	if e := WriteKeyConsts(&buf, "keys", p); e != nil {
+		t.Fatalf("TestWriteKeyConsts - %s", e)
+	}
+	expected := `// Code generated by gestalt consts. DO NOT EDIT.
+
+package keys
+
+// key names
+const (
+	KeyDbHost  = "db.host"
+	KeyDbHosts = "db.hosts[]" // array
+	KeyLimits  = "limits[:]"  // map
+)
+`
+	if src := buf.String(); src != expected {
+		t.Errorf("TestWriteKeyConsts - expected:\n%s\ngot:\n%s", expected, src)
+	}
+
+	if e := WriteKeyConsts(&buf, "keys", Properties{"db.host": "a", "db_host": "b"}); e == nil {
+		t.Errorf("TestWriteKeyConsts - colliding keys - error expected")
+	}
+}
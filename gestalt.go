@@ -87,6 +87,54 @@
 //                         list:/do/list, \           # note the `,`
 //                         login:/do/user/login
 //
+//  # ------------------------------------------
+//  # the `+=` operator extends an array or map property defined earlier
+//  # (or inherited - see Properties#Inherit) instead of replacing it.
+//
+//  web.resource.type.extensions[] += svg             # => [... "png", "svg"]
+//  dispatch.table[:] += logout:/do/user/logout
+//
+//  # ------------------------------------------
+//  # the @unset directive masks a key inherited from a parent
+//  # (see Properties#Inherit) and applies to all property types.
+//
+//  dispatch.tablex[:] = @unset
+//
+// Multiple property sets can be bundled in a single file (see LoadAll).  Documents
+// are separated by a `---` line, or introduced by a `[document:<name>]` line:
+//
+//  [document:tenant.a]
+//  db.host = a.example.com
+//
+//  [document:tenant.b]
+//  db.host = b.example.com
+//
+// Values can be restricted to a validity window by a `@<from>..<to>` key suffix,
+// and are resolved at read time (see WithClock):
+//
+//  banner.msg = Welcome
+//  banner.msg@2024-12-01..2024-12-31 = Happy holidays
+//
+// A `@schema <path>` line at the top of a file names the schema (see LoadSchema)
+// the file is validated against on load:
+//
+//  @schema schemas/db.gs
+//
+// Blocks of keys can be defined once, and reused under key prefixes:
+//
+//  @define pool
+//    timeout = 5s
+//    size = 10
+//  @end
+//  @use pool as db.primary                           # => db.primary.timeout, db.primary.size
+//  @use pool as db.replica
+//
+// and templates of keys repeated per element of a list:
+//
+//  @foreach shard in a, b, c
+//    shard.{{shard}}.host = {{shard}}.db.example.com  # => shard.a.host = a.db.example.com, ...
+//  @end
+//
 // The associated Properties (type) defines the properties API, but is itself simply a
 // a map[string]interface{} and can be used as such (without any type safety).
 //
@@ -96,10 +144,14 @@ package gestalt
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"log/slog"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -115,6 +167,7 @@ const (
 	kv_delim      = ":"
 	quote         = "\""
 	pkv_sep       = "="
+	append_op     = "+"
 	trimset       = "\n\r \t"
 	ws            = " \t"
 	array         = "[]"
@@ -124,6 +177,10 @@ const (
 	min_entry_len = len("a=b")
 	continuation  = '\\'
 	comment       = '#'
+	doc_sep       = "---"
+	doc_prefix    = "[document:"
+	doc_suffix    = "]"
+	unset         = "@unset"
 )
 
 // Properties is based on map and can be accessed as such
@@ -131,46 +188,192 @@ const (
 // REVU: should be private.
 type Properties map[string]interface{}
 
+// unsetValue is the value of keys defined with the @unset directive.
+// An unset key masks the same key of the parent on Inherit, and is
+// otherwise treated as if it were not defined.
+type unsetValue struct{}
+
+func (unsetValue) String() string { return unset }
+
+func isUnset(v interface{}) bool {
+	_, ok := v.(unsetValue)
+	return ok
+}
+
+// LoadOption configures the Load family of functions.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	lazy     bool
+	workers  int
+	keyring  Keyring
+	prompter Prompter
+	clock    Clock
+	fsys     FileSystem
+	logger   *slog.Logger
+	tracer   Tracer
+	derive   bool
+	hints    bool
+	hinted   *Schema    // records hinted types, if not nil
+	dir      string     // of the loaded file, if any
+	into     Properties // of fragments, parsed in order, if not nil
+	policy   Policy
+	intern   bool
+
+	maxValueLen  int
+	maxSize      int
+	lengthPolicy LengthPolicy
+	passThrough  bool
+	utf8Policy   UTF8Policy
+}
+
+func newLoadOptions(opts []LoadOption) *loadOptions {
+	o := &loadOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.clock == nil {
+		o.clock = SystemClock
+	}
+	if o.fsys == nil {
+		o.fsys = OSFileSystem
+	}
+	if o.logger == nil {
+		o.logger = log()
+	}
+	return o
+}
+
+// Lazy memoizes the conversions of typed (e.g. int, duration) values per
+// key, on first access, e.g. of hot paths reading the same keys repeatedly.
+// Note that values are parsed at load, and held as plain values.
+func Lazy() LoadOption {
+	return func(o *loadOptions) {
+		o.lazy = true
+	}
+}
+
 // ----------------------------------------------------------------------
 // API
 // ----------------------------------------------------------------------
 
 // Instantiates a new Properties object initialized from the
 // content of the specified file.
-func Load(filename string) (p Properties, e error) {
+func Load(filename string, opts ...LoadOption) (p Properties, e error) {
 
-	if filename == "" {
-		e = fmt.Errorf("filename is nil")
+	o := newLoadOptions(opts)
+	_, span := startSpan(context.Background(), o.tracer, "gestalt.load")
+	defer span.End()
+	span.SetAttribute("file", filename)
+
+	s, e := readFile(o, filename)
+	if e != nil {
+		span.RecordError(e)
 		return
 	}
+	span.SetAttribute("bytes", len(s))
 
-	b, err := ioutil.ReadFile(filename)
-	if err != nil {
-		e = fmt.Errorf("Error reading gestalt file <%s> : %s", filename, e)
+	o.dir = filepath.Dir(filename)
+	if p, e = loadBuffer(s, o); e != nil {
+		span.RecordError(e)
 		return
 	}
-
-	return loadBuffer(bytes.NewBuffer(b).String())
+	span.SetAttribute("keys", len(p))
+	o.logger.Debug("gestalt: file loaded", "file", filename, "keys", len(p))
+	return
 }
 
 // Support embedded properties (e.g. without files)
-func LoadStr(spec string) (p Properties, e error) {
-	return loadBuffer(spec)
+func LoadStr(spec string, opts ...LoadOption) (p Properties, e error) {
+	return loadBuffer(spec, newLoadOptions(opts))
+}
+
+// Instantiates the set of named Properties objects defined in the
+// (multi-document) content of the specified file. See LoadAllStr.
+func LoadAll(filename string, opts ...LoadOption) (docs map[string]Properties, e error) {
+	o := newLoadOptions(opts)
+	s, e := readFile(o, filename)
+	if e != nil {
+		return
+	}
+
+	if docs, e = LoadAllStr(s, opts...); e == nil {
+		o.logger.Debug("gestalt: file loaded", "file", filename, "documents", len(docs))
+	}
+	return
+}
+
+// Support embedded multi-document properties.
+// Documents introduced by a `[document:<name>]` line are named <name>,
+// and all others (separated by `---` lines) are named by their position
+// in spec, e.g. "0", "1", etc. Anonymous documents that define nothing
+// (e.g. only comments) are ignored.
+func LoadAllStr(spec string, opts ...LoadOption) (docs map[string]Properties, e error) {
+	docs = make(map[string]Properties)
+	o := newLoadOptions(opts)
+
+	name, named, buf := "", false, []string{}
+	flush := func() error {
+		s := strings.Join(buf, "\n")
+		if !named {
+			if strings.Trim(s, trimset) == empty {
+				return nil
+			}
+			name = fmt.Sprintf("%d", len(docs))
+		}
+		if _, dup := docs[name]; dup {
+			return fmt.Errorf("duplicate document <%s>", name)
+		}
+		p := make(Properties)
+		if strings.Trim(s, trimset) != empty {
+			var err error
+			if p, err = loadBuffer(s, o); err != nil {
+				return fmt.Errorf("document <%s> - %s", name, err)
+			}
+		}
+		docs[name] = p
+		return nil
+	}
+
+	for _, line := range strings.Split(spec, "\n") {
+		tline := strings.Trim(line, trimset)
+		switch {
+		case tline == doc_sep:
+			if e = flush(); e != nil {
+				return nil, e
+			}
+			name, named, buf = "", false, buf[:0]
+		case strings.HasPrefix(tline, doc_prefix) && strings.HasSuffix(tline, doc_suffix):
+			if e = flush(); e != nil {
+				return nil, e
+			}
+			name = strings.Trim(tline[len(doc_prefix):len(tline)-len(doc_suffix)], ws)
+			named, buf = true, buf[:0]
+		default:
+			buf = append(buf, line)
+		}
+	}
+	if e = flush(); e != nil {
+		return nil, e
+	}
+	return
 }
 
 // Return a clone of the argument Properties object
 func (p Properties) Clone() (clone Properties) {
-
+	clone = make(Properties, len(p))
 	for k, v := range p {
 		clone[k] = v
 	}
+	clone.adopt(p, nil, true)
 	return
 }
 
 // Copy all entries from specified Properties to the receiver
 // Note this will overwrite existing matching values if overwrite is true,
 // otherwise if overwrite is false it will only append keys that do not exist
-// in receiver
+// in receiver. Defaults (see SetDefaults) never overwrite values, and
+// opaque lines (see PassThrough) are appended.
 func (p Properties) Copy(from Properties, overwrite bool) {
 	// TODO - REVU - either silently Debug log or return error on nil 'from'
 	for k, v := range from {
@@ -178,23 +381,34 @@ func (p Properties) Copy(from Properties, overwrite bool) {
 			p[k] = v
 		}
 	}
+	p.adopt(from, nil, overwrite)
 }
 
 // Inherits from the parent key/value pairs if receiver[key] is nil.
-// If key is array, receiver's value array will be PRE-pended with parent's.
+// Defaults of the parent (see SetDefaults) apply to keys with no default.
+// If key is array, receiver's value array will be PRE-pended with parent's,
+// or merged per opts (see OrderedArrays).
 // If key is map, receiver's value map will be augmented with parent's.
+// If receiver[key] is @unset, the parent's value is masked.
 // nil input is silently ignored.
 //  REVU - issue regarding preserving order in parent array key values
-func (p Properties) Inherit(from Properties) {
+//  (by default) - see OrderedArrays
+func (p Properties) Inherit(from Properties, opts ...MergeOption) {
 	if from == nil {
 		return
 	}
+	o := newMergeOptions(opts)
+	defer p.adopt(from, nil, false)
 	for k, v := range from {
 		pv := p[k]
 		if pv == nil {
 			p[k] = v
+		} else if v == nil || isUnset(pv) || isUnset(v) {
+			continue
 		} else {
 			switch {
+			case isArrayKey(k) && o.ordered:
+				p[k] = mergeArrays(v.([]string), pv.([]string), o.unique)
 			case isArrayKey(k):
 				// REVU - somewhat funky semantics here
 				// attempting to preserve order of array values (in child)
@@ -230,7 +444,7 @@ func (p Properties) VerifyMust(keys ...string) (bool, []string) {
 	missing := []string{}
 	if keys != nil {
 		for _, rk := range keys {
-			if p[rk] == nil {
+			if p.get(rk) == nil {
 				missing = append(missing, rk)
 			}
 		}
@@ -247,13 +461,30 @@ func (p Properties) VerifyMust(keys ...string) (bool, []string) {
 //	return
 //}
 
+// returns the value of key, or nil if no such key or key is @unset
+func (p Properties) get(key string) interface{} {
+	if v := p.value(key); !isUnset(v) {
+		return v
+	}
+	return nil
+}
+
+// returns the value of key, i.e. of its current window, if windowed, or
+// its default, if not defined (see side). @unset values are returned as is.
+func (p Properties) value(key string) interface{} {
+	v := p[key]
+	if s := sideOf(p); s != nil {
+		return s.value(p, key, v)
+	}
+	return v
+}
+
 // returns nil/zero-value if no such key or key type is not array
 func (p Properties) GetArray(key string) []string {
 	if isArrayKey(key) {
-		if v := p[key]; v == nil {
-			return nil
+		if v := p.get(key); v != nil {
+			return v.([]string)
 		}
-		return p[key].([]string)
 	}
 	return nil
 }
@@ -269,10 +500,9 @@ func (p Properties) GetArrayOrDefault(key string, defval []string) (v []string)
 // returns nil/zero-value if no such key or not a map, or if key type is not map
 func (p Properties) GetMap(key string) map[string]string {
 	if isMapKey(key) {
-		if v := p[key]; v == nil {
-			return nil
+		if v := p.get(key); v != nil {
+			return v.(map[string]string)
 		}
-		return p[key].(map[string]string)
 	}
 	return nil
 }
@@ -285,13 +515,45 @@ func (p Properties) GetMapOrDefault(key string, defval map[string]string) (v map
 	return
 }
 
+// returns the i-th element of array property key.
+// Returns error if no such key, key type is not array, or i is out of bounds.
+func (p Properties) GetIndexed(key string, i int) (string, error) {
+	if !isArrayKey(key) {
+		return "", wrongType("key <%s> is not an array key", key)
+	}
+	arrv := p.GetArray(key)
+	if arrv == nil {
+		return "", missingKey(key)
+	}
+	if i < 0 || i >= len(arrv) {
+		return "", fmt.Errorf("index %d out of bounds for key <%s> (len %d)", i, key, len(arrv))
+	}
+	return arrv[i], nil
+}
+
+// returns the value of map property key for map key mk.
+// Returns error if no such key, key type is not map, or mk is not in map.
+func (p Properties) GetMapValue(key string, mk string) (string, error) {
+	if !isMapKey(key) {
+		return "", wrongType("key <%s> is not a map key", key)
+	}
+	mapv := p.GetMap(key)
+	if mapv == nil {
+		return "", missingKey(key)
+	}
+	v, ok := mapv[mk]
+	if !ok {
+		return "", fmt.Errorf("no such map key <%s> for key <%s>", mk, key)
+	}
+	return v, nil
+}
+
 // String value property - returns nil/zero-value if no such key or not a map
 func (p Properties) GetString(key string) string {
 	if !(isMapKey(key) || isArrayKey(key)) {
-		if v := p[key]; v == nil {
-			return ""
+		if v := p.get(key); v != nil {
+			return v.(string)
 		}
-		return p[key].(string)
 	}
 	return ""
 }
@@ -306,23 +568,185 @@ func (p Properties) MustGetString(key string) (v string) {
 	return p.GetString(key)
 }
 
+// String value property split by sep, for plain (string) keys holding
+// lists, e.g. "a, b, c". Elements are trimmed of whitespace and quotes, per
+// array values, and sep within quoted elements is not a separator. Values
+// loaded from files are split as written, e.g. `"a,b", c` as [a,b c], and
+// wholly quoted values as their (unquoted) value, e.g. `"a,b"` as [a b].
+// Returns nil if no such key, not a string key, or the value is empty.
+func (p Properties) GetStringsSplit(key string, sep string) []string {
+	s := p.GetString(key)
+	if s == "" || sep == "" {
+		return nil
+	}
+	if sd := sideOf(p); sd != nil {
+		s = sd.rep(p, key, s) // of the file, if quoted
+	}
+	var list []string
+	quoted, start := false, 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == quote[0]:
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(s[i:], sep):
+			list = append(list, s[start:i])
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+	list = append(list, s[start:])
+	for i, v := range list {
+		list[i] = strings.Trim(strings.Trim(v, ws), quote)
+	}
+	return list
+}
+
+// Int value property - returns error if no such key or value is not an int
+func (p Properties) GetInt(key string) (int, error) {
+	v, e := p.typed(key, typed_int)
+	if e != nil {
+		return 0, e
+	}
+	return v.(int), nil
+}
+
+// Bool value property - returns error if no such key or value is not a bool.
+// See strconv.ParseBool for accepted values.
+func (p Properties) GetBool(key string) (bool, error) {
+	v, e := p.typed(key, typed_bool)
+	if e != nil {
+		return false, e
+	}
+	return v.(bool), nil
+}
+
+// Duration value property - returns error if no such key or value is not a duration.
+// See time.ParseDuration for accepted values.
+func (p Properties) GetDuration(key string) (time.Duration, error) {
+	v, e := p.typed(key, typed_duration)
+	if e != nil {
+		return 0, e
+	}
+	return v.(time.Duration), nil
+}
+
+// Duration array value property, e.g. `backoff[] = 100ms, 500ms, 2s` -
+// returns error if no such key or an element is not a duration.
+func (p Properties) GetDurationArray(key string) ([]time.Duration, error) {
+	arrv, e := p.arrayValue(key)
+	if e != nil {
+		return nil, e
+	}
+	durations := make([]time.Duration, len(arrv))
+	for i, av := range arrv {
+		if durations[i], e = time.ParseDuration(av); e != nil {
+			return nil, wrongType("key <%s> - element %d - %s", key, i, e)
+		}
+	}
+	return durations, nil
+}
+
+// Duration map value property, e.g. `timeouts[:] = read:5s, write:10s` -
+// returns error if no such key or a map value is not a duration.
+func (p Properties) GetDurationMap(key string) (map[string]time.Duration, error) {
+	mapv, e := p.mapValue(key)
+	if e != nil {
+		return nil, e
+	}
+	durations := make(map[string]time.Duration, len(mapv))
+	for _, mk := range sortedKeys(mapv) {
+		if durations[mk], e = time.ParseDuration(mapv[mk]); e != nil {
+			return nil, wrongType("key <%s> - map key <%s> - %s", key, mk, e)
+		}
+	}
+	return durations, nil
+}
+
+// Regexp value property - returns error if no such key or value is not a
+// valid regular expression. See regexp.Compile for accepted values.
+// Compiled patterns are cached. Note that the `\` char is reserved, and
+// patterns must use character classes instead of escapes, e.g. [0-9] for \d.
+func (p Properties) GetRegexp(key string) (*regexp.Regexp, error) {
+	v, e := p.typed(key, typed_regexp)
+	if e != nil {
+		return nil, e
+	}
+	return v.(*regexp.Regexp), nil
+}
+
+// returns the string value of key, or error if no such key or not a string key
+func (p Properties) stringValue(key string) (string, error) {
+	if isMapKey(key) || isArrayKey(key) {
+		return "", wrongType("key <%s> is not a string key", key)
+	}
+	v := p.get(key)
+	if v == nil {
+		return "", missingKey(key)
+	}
+	return v.(string), nil
+}
+
+// returns the map value of key, or error if no such key or not a map key
+func (p Properties) mapValue(key string) (map[string]string, error) {
+	if !isMapKey(key) {
+		return nil, wrongType("key <%s> is not a map key", key)
+	}
+	v := p.get(key)
+	if v == nil {
+		return nil, missingKey(key)
+	}
+	return v.(map[string]string), nil
+}
+
+// returns the array value of key, or error if no such key or not an array key
+func (p Properties) arrayValue(key string) ([]string, error) {
+	if !isArrayKey(key) {
+		return nil, wrongType("key <%s> is not an array key", key)
+	}
+	v := p.get(key)
+	if v == nil {
+		return nil, missingKey(key)
+	}
+	return v.([]string), nil
+}
+
+// returns the conversion of the string value of key.
+// conversions are memoized per Lazy and PreResolve.
+func (p Properties) typed(key string, tag byte) (v interface{}, e error) {
+	if isMapKey(key) || isArrayKey(key) {
+		return nil, wrongType("key <%s> is not a string key", key)
+	}
+	s := p.get(key)
+	if s == nil {
+		return nil, missingKey(key)
+	}
+	if v, e = p.convert(key, tag, s.(string)); e != nil {
+		return nil, fmt.Errorf("key <%s> - %w", key, typeError{e})
+	}
+	return v, nil
+}
+
 // Returns true if provided key is a valid array property value key,
 // suitable for use with GetMap(mapkey)
 func isMapKey(key string) bool {
-	return strings.HasSuffix(key, cmap)
+	return strings.HasSuffix(plainKey(key), cmap)
 }
 
 // Returns true if provided key is a valid map property value key
 // suitable for use with GetArray(arrkey)
 func isArrayKey(key string) bool {
-	return !isMapKey(key) && strings.HasSuffix(key, array)
+	return !isMapKey(key) && strings.HasSuffix(plainKey(key), array)
 }
 
 // Returns a pretty print string for Properties.
 // See also Properties#Print
+// The values of secret keys are redacted (see MarkSecret).
 func (p Properties) String() string {
 	srep := "-- properties --\n"
 	for k, v := range p {
+		if p.IsSecret(k) {
+			v = redacted
+		}
 		srep += fmt.Sprintf("'%s' => '%s'", k, v)
 		srep += "\n"
 	}
@@ -344,91 +768,328 @@ func (p Properties) Print() {
 // TODO: try lexing this thing ..
 // ----------------------------------------------------------------------
 
-func loadBuffer(s string) (p Properties, e error) {
+func readFile(o *loadOptions, filename string) (s string, e error) {
+
+	if filename == "" {
+		e = fmt.Errorf("filename is nil")
+		return
+	}
+
+	b, err := o.readFile(filename)
+	if err != nil {
+		e = fmt.Errorf("Error reading gestalt file <%s> : %w", filename, err)
+		return
+	}
+
+	return bytes.NewBuffer(b).String(), nil
+}
+
+func loadBuffer(s string, o *loadOptions) (p Properties, e error) {
+	return parseBuffer(s, o, new(parseBuffers))
+}
+
+// parses s per o, using (and retaining) the buffers of bufs. See Parser.
+func parseBuffer(s string, o *loadOptions, bufs *parseBuffers) (p Properties, e error) {
 
 	if s == empty {
 		e = errors.New("s is nil")
 		return
 	}
+	if e = o.checkSize(s); e != nil {
+		return
+	}
+	if e = o.checkUTF8(s); e != nil {
+		e = fmt.Errorf("error parsing properties- %w", e)
+		return
+	}
+	if path, rest, ok := schemaDirective(s); ok {
+		return o.loadWithSchema(path, rest)
+	}
 
-	specs := splitCleanPropSpecs(s)
+	specs, e := expandBlocks(bufs.splitCleanPropSpecs(s, o.utf8Policy == UTF8Raw))
+	if e != nil {
+		e = fmt.Errorf("error parsing properties- %s", e)
+		return
+	}
 
-	p = make(Properties)
+	if p = o.into; p == nil {
+		p = make(Properties, len(specs))
+	}
+	var anchor string // the latest key, of opaque lines
 	for _, spec := range specs {
-		k, v, err := parseProperty(spec)
+		// preserves spec as an opaque line, per PassThrough
+		passed := func() bool {
+			if o.passThrough {
+				o.logger.Debug("gestalt: opaque line preserved", "line", strings.Trim(spec, trimset))
+				p.addOpaque(strings.Trim(spec, trimset), anchor)
+			}
+			return o.passThrough
+		}
+		k, vrep, extend, err := parseProperty(spec)
+		if err != nil {
+			if passed() {
+				continue
+			}
+			e = fmt.Errorf("error parsing properties- %s", err)
+			return
+		}
+		if k == empty {
+			if strings.Trim(spec, trimset) != empty {
+				passed()
+			}
+			continue
+		}
+		if vrep, err = o.limitValue(k, vrep); err != nil {
+			e = fmt.Errorf("error parsing properties- %w", err)
+			return
+		}
+		var secret bool
+		if o.hints {
+			if k, secret, err = o.typeHint(k, vrep); err != nil {
+				e = fmt.Errorf("error parsing properties- %w", err)
+				return
+			}
+		}
+		plain, w, windowed, err := splitWindow(k)
 		if err != nil {
+			if passed() {
+				continue
+			}
+			e = fmt.Errorf("error parsing properties- %s", err)
+			return
+		}
+		v, err := parseValue(plain, vrep)
+		if err != nil {
+			if passed() {
+				continue
+			}
 			e = fmt.Errorf("error parsing properties- %s", err)
 			return
 		}
-		if k != empty {
+		if o.intern {
+			k, v = internString(k), internValue(v)
+		}
+		if extend {
+			p.extend(k, v)
+		} else {
+			if _, dup := p[k]; dup {
+				o.logger.Debug("gestalt: duplicate key overwritten", "key", k)
+			}
 			p[k] = v
+			// the quoted form is recorded (for GetStringsSplit) only if it
+			// quotes elements, so that ordinary quoted values have no side
+			// table.
+			if sv, ok := v.(string); ok && strings.Contains(sv, quote) {
+				p.addRep(k, sv, vrep)
+			}
 		}
+		if windowed {
+			p.addWindow(plain, w, o.clock)
+		}
+		if secret {
+			p.MarkSecret(k)
+		}
+		anchor = k
 	}
-	return
+	if o.lazy {
+		p.memoize()
+	}
+	return o.complete(p)
+}
+
+// completes the loaded Properties p per o: resolves the references of the
+// keyring, derives keys, and enforces the policy, in that order
+func (o *loadOptions) complete(p Properties) (Properties, error) {
+	if o.keyring != nil {
+		if e := p.resolveKeyring(o.keyring); e != nil {
+			return nil, e
+		}
+	}
+	if o.derive {
+		var e error
+		if p, e = p.Derive(); e != nil {
+			return nil, e
+		}
+	}
+	if o.policy != nil {
+		if e := CheckPolicy(context.Background(), o.policy, p); e != nil {
+			return nil, e
+		}
+	}
+	return p, nil
 }
 
 // converts to []string of lines.  this is mainly addressing
 // comments (both flavors) & continuations (multi-line values)
 // beyond a general split on crlf
 func splitCleanPropSpecs(s string) (pspecs []string) {
+	return new(parseBuffers).splitCleanPropSpecs(s, false)
+}
+
+// splitCleanPropSpecs, using the buffers of bufs. Invalid UTF-8 bytes are
+// replaced with utf8.RuneError, unless raw. The returned specs are valid
+// until the next call.
+func (bufs *parseBuffers) splitCleanPropSpecs(s string, raw bool) (pspecs []string) {
 
 	// trim overall buffer
 	s = strings.Trim(s, trimset)
 
-	erase := false
-	cont := false
-	reset := false
-	b := make([]byte, len(s))
-	off := 0
-	s = strings.Trim(s, trimset)
-	for _, c := range s {
-		if c == rune(continuation) {
-			erase = true
-			cont = true
-		} else if c == comment {
-			erase = true
-		} else if c == '\n' {
-			if cont {
-				cont = false
-				reset = true
-			} else {
-				erase = false
+	var st cleanState
+	if cap(bufs.b) < len(s) {
+		bufs.b = make([]byte, 0, len(s))
+	}
+	b := bufs.b[:0]
+	if raw || utf8.ValidString(s) {
+		// fast path: the chars of the transitions are ASCII, and so never
+		// part of multibyte chars (or invalid bytes), so runs of other chars
+		// are copied as is
+		for len(s) > 0 {
+			i := indexClean(s)
+			if i > 0 && st.next(rune(s[0])) {
+				b = append(b, s[:i]...)
+			}
+			if i < len(s) && st.next(rune(s[i])) {
+				b = append(b, s[i])
 			}
-		} else if reset {
-			erase = false
-			reset = false
+			s = s[min(i+1, len(s)):]
 		}
-		if !erase {
-			off += utf8.EncodeRune(b[off:], c)
+	} else {
+		// invalid bytes are replaced by utf8.RuneError
+		for _, c := range s {
+			if st.next(c) {
+				b = utf8.AppendRune(b, c)
+			}
 		}
 	}
-	s = string(b[0:off])
+	bufs.b = b
+	s = string(b)
 
 	// split to get distinct specs.
-	pspecs = strings.Split(s, "\n")
+	pspecs = bufs.specs[:0]
+	for {
+		spec, rest, more := strings.Cut(s, "\n")
+		pspecs = append(pspecs, spec)
+		if !more {
+			break
+		}
+		s = rest
+	}
+	bufs.specs = pspecs
 
 	return
 }
 
+// returns the index of the first char of a transition of cleanState (i.e.
+// newline, comment, or continuation) in s, or len(s)
+func indexClean(s string) int {
+	i := strings.IndexByte(s, '\n')
+	if i < 0 {
+		i = len(s)
+	}
+	if j := strings.IndexByte(s[:i], comment); j >= 0 {
+		i = j
+	}
+	if j := strings.IndexByte(s[:i], continuation); j >= 0 {
+		i = j
+	}
+	return i
+}
+
+// state of the erasure of comments and continuations of splitCleanPropSpecs
+type cleanState struct {
+	erase bool
+	cont  bool
+	reset bool
+}
+
+// transitions per the next char c. Returns true if c is retained.
+func (st *cleanState) next(c rune) bool {
+	switch c {
+	case continuation:
+		st.erase = true
+		st.cont = true
+	case comment:
+		st.erase = true
+	case '\n':
+		if st.cont {
+			st.cont = false
+			st.reset = true
+		} else {
+			st.erase = false
+		}
+	default:
+		if st.reset {
+			st.erase = false
+			st.reset = false
+		}
+	}
+	return !st.erase
+}
+
+// extends the array or map value of key with v.
+// Defines key if it is not already defined.
+func (p Properties) extend(key string, v interface{}) {
+	pv := p.get(key)
+	if pv == nil || isUnset(v) {
+		p[key] = v
+		return
+	}
+	switch {
+	case isArrayKey(key):
+		arrv := append([]string{}, pv.([]string)...)
+		p[key] = append(arrv, v.([]string)...)
+	case isMapKey(key):
+		mapv := make(map[string]string)
+		for mk, mv := range pv.(map[string]string) {
+			mapv[mk] = mv
+		}
+		for mk, mv := range v.(map[string]string) {
+			mapv[mk] = mv
+		}
+		p[key] = mapv
+	}
+}
+
 // attempts to parse a single <key> = <value> property def spec.
 // Returns ("", "") if comment or malformed.
-// Otherwise (key, value) pair are returned.
+// Otherwise (key, value) pair are returned, with the (unparsed) value
+// representation. extend is true if spec is an append-assign (+=) of an
+// array or map key; append-assigns of other keys are malformed.
 // REVU TODO support true quotes to allow use of ':', '\', and '#' in k/v
-func parseProperty(spec string) (key string, value interface{}, e error) {
+func parseProperty(spec string) (key string, vrep string, extend bool, e error) {
 	if len(spec) < min_entry_len {
-		return empty, value, e
+		return empty, vrep, false, e
 	}
 
-	propTuple := strings.Split(strings.Trim(spec, trimset), pkv_sep)
+	krep, vrep, _ := strings.Cut(strings.Trim(spec, trimset), pkv_sep)
 
 	// Verify well-formedness
-	if len(propTuple) != 2 || propTuple[1] == empty {
+	if vrep == empty || strings.Contains(vrep, pkv_sep) {
 		e = errors.New(fmt.Sprintf("property spec '%s' is malformed", spec))
 		return
 	}
 
-	key = strings.Trim(propTuple[0], ws)
-	vrep := strings.Trim(propTuple[1], ws)
+	key = strings.Trim(krep, ws)
+	vrep = strings.Trim(vrep, ws)
+
+	if strings.HasSuffix(key, append_op) {
+		k := strings.Trim(strings.TrimSuffix(key, append_op), ws)
+		if !isMapKey(k) && !isArrayKey(k) {
+			e = fmt.Errorf("property spec '%s' is malformed - '%s=' applies to array and map keys", spec, append_op)
+			return
+		}
+		key, extend = k, true
+	}
+
+	return
+}
+
+// parses the value representation of key per its type.
+func parseValue(key string, vrep string) (value interface{}, e error) {
+	if vrep == unset {
+		value = unsetValue{}
+		return
+	}
 
 	// do NOT change order of parse - maps first
 	if isMapKey(key) {
@@ -437,6 +1098,10 @@ func parseProperty(spec string) (key string, value interface{}, e error) {
 		for _, _kv := range kvpairs {
 			_kv = strings.Trim(_kv, ws)
 			_kvarr := strings.Split(_kv, kv_delim)
+			if len(_kvarr) < 2 {
+				e = fmt.Errorf("map entry '%s' of key '%s' is malformed", _kv, key)
+				return
+			}
 			ek := strings.Trim(_kvarr[0], ws)
 			ev := strings.Trim(_kvarr[1], ws)
 			kvmap[strings.Trim(ek, quote)] = strings.Trim(ev, quote)
@@ -450,7 +1115,6 @@ func parseProperty(spec string) (key string, value interface{}, e error) {
 		}
 		value = arrv
 	} else {
-		value = strings.Trim(propTuple[1], ws)
 		value = strings.Trim(vrep, quote)
 	}
 
@@ -2,7 +2,9 @@ package gestalt
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadFileWithError(t *testing.T) {
@@ -100,3 +102,316 @@ some.array.with.quoted.values[] = a, b, "   c", "d "
 	}
 	return
 }
+
+func TestUnset(t *testing.T) {
+	parent, e := LoadStr(`
+foo = bar
+hosts[] = a, b
+woof = meow
+`)
+	if e != nil {
+		t.Errorf("TestUnset - LoadStr(parent) - %s", e)
+	}
+	child, e := LoadStr(`
+foo = @unset
+hosts[] = @unset
+`)
+	if e != nil {
+		t.Errorf("TestUnset - LoadStr(child) - %s", e)
+	}
+
+	child.Inherit(parent)
+	if v := child.GetString("foo"); v != "" {
+		t.Errorf("TestUnset - GetString(foo) - expected: <>, got: <%s>", v)
+	}
+	if v := child.GetArray("hosts[]"); v != nil {
+		t.Errorf("TestUnset - GetArray(hosts[]) - expected: nil, got: %s", v)
+	}
+	if v := child.GetString("woof"); v != "meow" {
+		t.Errorf("TestUnset - GetString(woof) - expected: meow, got: %s", v)
+	}
+	if ok, missing := child.VerifyMust("foo", "woof"); ok || len(missing) != 1 {
+		t.Errorf("TestUnset - VerifyMust - expected: [foo] missing, got: %s", missing)
+	}
+}
+
+func TestGetIndexedAndMapValue(t *testing.T) {
+	prop, e := LoadStr(`
+servers[] = alpha, beta, gamma
+dispatch.table[:] = *:/ , list : /do/list, login: /do/user/login
+`)
+	if e != nil {
+		t.Errorf("TestGetIndexedAndMapValue - LoadStr - %s", e)
+	}
+
+	if v, e := prop.GetIndexed("servers[]", 2); e != nil || v != "gamma" {
+		t.Errorf("TestGetIndexedAndMapValue - GetIndexed(servers[], 2) - expected: gamma, got: %s (%v)", v, e)
+	}
+	if _, e := prop.GetIndexed("servers[]", 3); e == nil {
+		t.Errorf("TestGetIndexedAndMapValue - GetIndexed(servers[], 3) - error expected")
+	}
+	if _, e := prop.GetIndexed("nosuch[]", 0); e == nil {
+		t.Errorf("TestGetIndexedAndMapValue - GetIndexed(nosuch[], 0) - error expected")
+	}
+
+	if v, e := prop.GetMapValue("dispatch.table[:]", "login"); e != nil || v != "/do/user/login" {
+		t.Errorf("TestGetIndexedAndMapValue - GetMapValue(dispatch.table[:], login) - expected: /do/user/login, got: %s (%v)", v, e)
+	}
+	if _, e := prop.GetMapValue("dispatch.table[:]", "logout"); e == nil {
+		t.Errorf("TestGetIndexedAndMapValue - GetMapValue(dispatch.table[:], logout) - error expected")
+	}
+	if _, e := prop.GetMapValue("servers[]", "login"); e == nil {
+		t.Errorf("TestGetIndexedAndMapValue - GetMapValue(servers[], login) - error expected")
+	}
+}
+
+func TestAppendAssign(t *testing.T) {
+	prop, e := LoadStr(`
+servers[] = alpha, beta
+servers[] += gamma
+labels[:] = env:dev, tier:web
+labels[:] += env:prod, zone:a
+fresh[] += one
+`)
+	if e != nil {
+		t.Errorf("TestAppendAssign - LoadStr - %s", e)
+	}
+
+	expected := []string{"alpha", "beta", "gamma"}
+	got := prop.GetArray("servers[]")
+	if len(got) != len(expected) {
+		t.Errorf("TestAppendAssign - GetArray(servers[]) - expected: %s, got: %s", expected, got)
+	}
+	for i, av := range got {
+		if av != expected[i] {
+			t.Errorf("TestAppendAssign - GetArray(servers[]) - at index %d expected: %s, got: %s", i, expected[i], av)
+		}
+	}
+
+	labels := prop.GetMap("labels[:]")
+	if labels["env"] != "prod" || labels["tier"] != "web" || labels["zone"] != "a" {
+		t.Errorf("TestAppendAssign - GetMap(labels[:]) - got: %s", labels)
+	}
+
+	if v := prop.GetArray("fresh[]"); len(v) != 1 || v[0] != "one" {
+		t.Errorf("TestAppendAssign - GetArray(fresh[]) - expected: [one], got: %s", v)
+	}
+
+	if p, e := LoadStr("foo = a\nfoo += b\n"); e == nil {
+		t.Errorf("TestAppendAssign - LoadStr(foo += b) - error expected, got: %s", p)
+	}
+}
+
+func TestLoadAllStr(t *testing.T) {
+	spec := `
+# shared header comment
+a = 1
+---
+b = 2
+[document:tenant.x]
+db.host = x.example.com    # comment
+[document:tenant.y]
+`
+	docs, e := LoadAllStr(spec)
+	if e != nil {
+		t.Errorf("TestLoadAllStr - LoadAllStr - %s", e)
+	}
+	if len(docs) != 4 {
+		t.Errorf("TestLoadAllStr - LoadAllStr - expected: 4 documents, got: %d", len(docs))
+	}
+	for name, expected := range map[string][2]string{"0": {"a", "1"}, "1": {"b", "2"}, "tenant.x": {"db.host", "x.example.com"}} {
+		if v := docs[name].GetString(expected[0]); v != expected[1] {
+			t.Errorf("TestLoadAllStr - docs[%s].GetString(%s) - expected: %s, got: %s", name, expected[0], expected[1], v)
+		}
+	}
+	if p, ok := docs["tenant.y"]; !ok || len(p) != 0 {
+		t.Errorf("TestLoadAllStr - docs[tenant.y] - expected: empty Properties, got: %v", p)
+	}
+
+	if _, e := LoadAllStr("[document:x]\na=b\n[document:x]\nc=d"); e == nil {
+		t.Errorf("TestLoadAllStr - LoadAllStr - duplicate document error expected")
+	}
+}
+
+func TestTypedGetters(t *testing.T) {
+	prop, e := LoadStr(`
+port = 8080
+debug = true
+timeout = 1m30s
+name = foo
+`)
+	if e != nil {
+		t.Errorf("TestTypedGetters - LoadStr - %s", e)
+	}
+
+	if v, e := prop.GetInt("port"); e != nil || v != 8080 {
+		t.Errorf("TestTypedGetters - GetInt(port) - expected: 8080, got: %d (%v)", v, e)
+	}
+	if v, e := prop.GetBool("debug"); e != nil || !v {
+		t.Errorf("TestTypedGetters - GetBool(debug) - expected: true, got: %t (%v)", v, e)
+	}
+	if v, e := prop.GetDuration("timeout"); e != nil || v != 90*time.Second {
+		t.Errorf("TestTypedGetters - GetDuration(timeout) - expected: 1m30s, got: %s (%v)", v, e)
+	}
+	if _, e := prop.GetInt("name"); e == nil {
+		t.Errorf("TestTypedGetters - GetInt(name) - error expected")
+	}
+	if _, e := prop.GetBool("nosuch"); e == nil {
+		t.Errorf("TestTypedGetters - GetBool(nosuch) - error expected")
+	}
+}
+
+func TestMalformedMapEntry(t *testing.T) {
+	if _, e := LoadStr("a.map[:] = a:1, b"); e == nil {
+		t.Errorf("TestMalformedMapEntry - LoadStr - error expected")
+	}
+}
+
+func BenchmarkGetString(b *testing.B) {
+	prop, _ := LoadStr("foo = bar")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		prop.GetString("foo")
+	}
+}
+
+func BenchmarkGetArray(b *testing.B) {
+	prop, _ := LoadStr("foo[] = a, b, c")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		prop.GetArray("foo[]")
+	}
+}
+
+func BenchmarkGetInt(b *testing.B) {
+	prop, _ := LoadStr("foo = 8080")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		prop.GetInt("foo")
+	}
+}
+
+func TestGetStringsSplit(t *testing.T) {
+	p, _ := LoadStr(`
+plain = a, b ,c
+piped = x | " y " | z
+quoted = x, "a,b", c
+leading = "a,b", c
+both = "a", "b"
+whole = "a, b"
+single = a
+`)
+	for _, c := range []struct {
+		key, sep string
+		expected []string
+	}{
+		{"plain", ",", []string{"a", "b", "c"}},
+		{"piped", "|", []string{"x", " y ", "z"}},
+		{"quoted", ",", []string{"x", "a,b", "c"}},
+		{"leading", ",", []string{"a,b", "c"}},
+		{"both", ",", []string{"a", "b"}},
+		{"whole", ",", []string{"a", "b"}},
+		{"single", ",", []string{"a"}},
+		{"nope", ",", nil},
+	} {
+		if v := p.GetStringsSplit(c.key, c.sep); fmt.Sprint(v) != fmt.Sprint(c.expected) || len(v) != len(c.expected) {
+			t.Errorf("TestGetStringsSplit - GetStringsSplit(%s, %s) - expected: %q, got: %q", c.key, c.sep, c.expected, v)
+		}
+	}
+
+	if p, _ := LoadStr("msg = \"hello, world\"\n"); sideOf(p) != nil {
+		t.Errorf("TestGetStringsSplit - LoadStr - expected no side table of quoted values")
+	}
+}
+
+func TestGetRegexp(t *testing.T) {
+	p, _ := LoadStr("route = ^/api/v[0-9]+/users$\nbad = a(b\n")
+	re, e := p.GetRegexp("route")
+	if e != nil || !re.MatchString("/api/v2/users") {
+		t.Errorf("TestGetRegexp - GetRegexp(route) - got: %v, %v", re, e)
+	}
+	if again, _ := p.GetRegexp("route"); again != re {
+		t.Errorf("TestGetRegexp - GetRegexp(route) - expected cached regexp")
+	}
+	if _, e := p.GetRegexp("bad"); e == nil {
+		t.Errorf("TestGetRegexp - GetRegexp(bad) - error expected")
+	}
+	if _, e := p.GetRegexp("nope"); e == nil {
+		t.Errorf("TestGetRegexp - GetRegexp(nope) - error expected")
+	}
+}
+
+func TestGetDurationArrayAndMap(t *testing.T) {
+	p, _ := LoadStr(`
+backoff[] = 100ms, 500ms, 2s
+bad.backoff[] = 100ms, soon
+timeouts[:] = read:5s, write:10s
+bad.timeouts[:] = read:5s, write:10
+`)
+	arrv, e := p.GetDurationArray("backoff[]")
+	if e != nil || fmt.Sprint(arrv) != "[100ms 500ms 2s]" {
+		t.Errorf("TestGetDurationArrayAndMap - GetDurationArray(backoff[]) - got: %v, %v", arrv, e)
+	}
+	if _, e := p.GetDurationArray("bad.backoff[]"); e == nil || !strings.Contains(e.Error(), "element 1") {
+		t.Errorf("TestGetDurationArrayAndMap - GetDurationArray(bad.backoff[]) - expected element 1 error, got: %v", e)
+	}
+
+	mapv, e := p.GetDurationMap("timeouts[:]")
+	if e != nil || mapv["read"] != 5*time.Second || mapv["write"] != 10*time.Second {
+		t.Errorf("TestGetDurationArrayAndMap - GetDurationMap(timeouts[:]) - got: %v, %v", mapv, e)
+	}
+	if _, e := p.GetDurationMap("bad.timeouts[:]"); e == nil || !strings.Contains(e.Error(), "map key <write>") {
+		t.Errorf("TestGetDurationArrayAndMap - GetDurationMap(bad.timeouts[:]) - expected write error, got: %v", e)
+	}
+	if _, e := p.GetDurationMap("backoff[]"); e == nil {
+		t.Errorf("TestGetDurationArrayAndMap - GetDurationMap(backoff[]) - error expected")
+	}
+}
+
+func TestSplitCleanPropSpecs(t *testing.T) {
+	// reference: the rune by rune transitions of all chars
+	ref := func(s string) string {
+		var st cleanState
+		var sb strings.Builder
+		for _, c := range strings.Trim(s, trimset) {
+			if st.next(c) {
+				sb.WriteRune(c)
+			}
+		}
+		return sb.String()
+	}
+	for _, s := range []string{
+		"a = b",
+		"# comment\na = b # trailing\n\nc = d",
+		"a[] = x, \\\n  y, \\ # comment\n  z\nb = c",
+		"a = b \\\n#x\nc = d",
+		"a = b # c \\\n d\ne = f",
+		"clé = välue # ça\n日本 = 語 \\\n 続き",
+		"\\", "#", "\n\n",
+	} {
+		expected := strings.Split(ref(s), "\n")
+		if specs := splitCleanPropSpecs(s); fmt.Sprintf("%q", specs) != fmt.Sprintf("%q", expected) {
+			t.Errorf("TestSplitCleanPropSpecs - splitCleanPropSpecs(%q) - expected: %q, got: %q", s, expected, specs)
+		}
+	}
+	// invalid bytes are replaced
+	invalid := strings.Repeat("a = \xff\xfe # \xff\n", 100)
+	if specs := splitCleanPropSpecs(invalid); len(specs) != 100 || specs[0] != "a = \uFFFD\uFFFD " {
+		t.Errorf("TestSplitCleanPropSpecs - splitCleanPropSpecs(invalid) - got: %q", specs[:1])
+	}
+}
+
+func BenchmarkSplitCleanPropSpecs(b *testing.B) {
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&sb, "# comment of key %d\nservice.key.%d = some value of key %d # trailing comment\n", i, i, i)
+		fmt.Fprintf(&sb, "service.list.%d[] = a, b, \\\n  c, d\n", i)
+	}
+	s := sb.String()
+	b.SetBytes(int64(len(s)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		splitCleanPropSpecs(s)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+)
+
+// ----------------------------------------------------------------------
+// Error returning getters
+//
+// the plain getters (e.g. GetString) return zero values for undefined
+// keys, which is ambiguous when zero values (e.g. "") are legal values.
+// The getters of this section (and the typed getters, e.g. GetInt) return
+// errors distinguishing undefined keys (ErrMissingKey) from values not of
+// the requested type (ErrWrongType), e.g.
+//
+//	s, e := p.GetStringOrError("banner")
+//	switch {
+//	case errors.Is(e, gestalt.ErrMissingKey):
+//		s = defaultBanner
+//	case e != nil:
+//		return e
+//	}
+// ----------------------------------------------------------------------
+
+// ErrMissingKey is ErrNoSuchKey, i.e. the error of lookups of undefined
+// (or @unset) keys.
+var ErrMissingKey = ErrNoSuchKey
+
+// ErrWrongType is ErrTypeMismatch, i.e. the error of keys of another kind
+// (e.g. array keys of GetStringOrError), or of values that can not be
+// converted to the requested type (e.g. "abc" of GetInt).
+var ErrWrongType = ErrTypeMismatch
+
+// error of a value that is not of the requested type. The message is that
+// of err, which is wrapped along with ErrTypeMismatch.
+type typeError struct {
+	err error
+}
+
+func (e typeError) Error() string {
+	return e.err.Error()
+}
+
+func (e typeError) Unwrap() []error {
+	return []error{ErrTypeMismatch, e.err}
+}
+
+// returns the error of a lookup of undefined key
+func missingKey(key string) error {
+	return fmt.Errorf("%w <%s>", ErrNoSuchKey, key)
+}
+
+// returns a typeError of the formatted message
+func wrongType(format string, args ...interface{}) error {
+	return typeError{fmt.Errorf(format, args...)}
+}
+
+// String value property - returns an error of ErrMissingKey if no such
+// key, or of ErrWrongType if key is an array or map key.
+func (p Properties) GetStringOrError(key string) (string, error) {
+	return p.stringValue(key)
+}
+
+// Array value property - returns an error of ErrMissingKey if no such key,
+// or of ErrWrongType if key is not an array key.
+func (p Properties) GetArrayOrError(key string) ([]string, error) {
+	return p.arrayValue(key)
+}
+
+// Map value property - returns an error of ErrMissingKey if no such key,
+// or of ErrWrongType if key is not a map key.
+func (p Properties) GetMapOrError(key string) (map[string]string, error) {
+	return p.mapValue(key)
+}
@@ -0,0 +1,51 @@
+package gestalt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetOrError(t *testing.T) {
+	p, _ := LoadStr(`
+empty = ""
+port = http
+a[] = x, y
+m[:] = k:v
+gone = 1
+gone = @unset
+`)
+	if v, e := p.GetStringOrError("empty"); e != nil || v != "" {
+		t.Errorf("TestGetOrError - GetStringOrError(empty) - expected: \"\", nil, got: %q, %v", v, e)
+	}
+	for _, key := range []string{"nope", "gone"} {
+		if _, e := p.GetStringOrError(key); !errors.Is(e, ErrMissingKey) || errors.Is(e, ErrWrongType) {
+			t.Errorf("TestGetOrError - GetStringOrError(%s) - expected: ErrMissingKey, got: %v", key, e)
+		}
+	}
+	if _, e := p.GetStringOrError("a[]"); !errors.Is(e, ErrWrongType) || e.Error() != "key <a[]> is not a string key" {
+		t.Errorf("TestGetOrError - GetStringOrError(a[]) - expected: ErrWrongType, got: %v", e)
+	}
+	if v, e := p.GetArrayOrError("a[]"); e != nil || len(v) != 2 {
+		t.Errorf("TestGetOrError - GetArrayOrError(a[]) - expected: [x y], got: %v, %v", v, e)
+	}
+	if _, e := p.GetArrayOrError("m[:]"); !errors.Is(e, ErrWrongType) {
+		t.Errorf("TestGetOrError - GetArrayOrError(m[:]) - expected: ErrWrongType, got: %v", e)
+	}
+	if v, e := p.GetMapOrError("m[:]"); e != nil || v["k"] != "v" {
+		t.Errorf("TestGetOrError - GetMapOrError(m[:]) - expected: map[k:v], got: %v, %v", v, e)
+	}
+	if _, e := p.GetMapOrError("n[:]"); !errors.Is(e, ErrMissingKey) {
+		t.Errorf("TestGetOrError - GetMapOrError(n[:]) - expected: ErrMissingKey, got: %v", e)
+	}
+
+	// typed getters
+	if _, e := p.GetInt("port"); !errors.Is(e, ErrWrongType) || errors.Is(e, ErrMissingKey) {
+		t.Errorf("TestGetOrError - GetInt(port) - expected: ErrWrongType, got: %v", e)
+	}
+	if _, e := p.GetInt("nope"); !errors.Is(e, ErrMissingKey) {
+		t.Errorf("TestGetOrError - GetInt(nope) - expected: ErrMissingKey, got: %v", e)
+	}
+	if _, e := p.GetIndexed("a[]", 5); errors.Is(e, ErrMissingKey) || errors.Is(e, ErrWrongType) {
+		t.Errorf("TestGetOrError - GetIndexed(a[], 5) - expected: out of bounds error, got: %v", e)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// GitSource is a Source loading a config file at a given ref of a git
+// repository, using the git command. For GitOps style config management.
+type GitSource struct {
+	// url of the remote repository, or "" if Dir is a local checkout
+	// that is used as is (not fetched)
+	Repo string
+	// local repository directory. The remote repository is cloned here
+	// on first load, and fetched on subsequent loads.
+	Dir string
+	// branch, tag, or commit (abbreviated commits require Offline, as they
+	// can not be fetched). default is HEAD
+	Ref string
+	// path of the config file in the repository
+	Path string
+	// if true, a failed fetch (e.g. of an unreachable remote, or of an
+	// abbreviated commit) is logged, and Ref is resolved in the local
+	// repository. Otherwise the load fails.
+	Offline bool
+
+	opts []LoadOption
+
+	mu     sync.Mutex
+	commit string
+}
+
+// Returns a new GitSource. See GitSource for the semantics of args.
+func NewGitSource(repo, dir, ref, path string, opts ...LoadOption) *GitSource {
+	return &GitSource{Repo: repo, Dir: dir, Ref: ref, Path: path, opts: opts}
+}
+
+// Returns the commit hash of the last successful load, or "" if none.
+func (s *GitSource) Commit() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commit
+}
+
+// Returns the provenance metadata of the last successful load (see
+// MarshalCanonical), i.e. the source, ref, and resolved commit, or nil
+// if none.
+func (s *GitSource) Provenance() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.commit == "" {
+		return nil
+	}
+	source := s.Repo
+	if source == "" {
+		source = s.Dir
+	}
+	return map[string]string{"source": source + "/" + s.Path, "ref": s.Ref, "commit": s.commit}
+}
+
+// Load implements Source.
+func (s *GitSource) Load(ctx context.Context) (Properties, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// args are not options
+	for _, arg := range []string{s.Repo, s.Ref, s.Path} {
+		if strings.HasPrefix(arg, "-") {
+			return nil, fmt.Errorf("git source - invalid argument <%s>", arg)
+		}
+	}
+	ref := s.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	rev := ref
+	if s.Repo != "" {
+		if _, e := statFile(filepath.Join(s.Dir, ".git")); errors.Is(e, fs.ErrNotExist) {
+			if _, e := s.git(ctx, "clone", "--quiet", "--no-checkout", "--", s.Repo, s.Dir); e != nil {
+				return nil, e
+			}
+		}
+		// fetched refs are resolved via FETCH_HEAD.
+		_, e := s.git(ctx, "-C", s.Dir, "fetch", "--quiet", "origin", "--end-of-options", ref)
+		switch {
+		case e == nil:
+			rev = "FETCH_HEAD"
+		case !s.Offline:
+			return nil, e
+		default:
+			log().Warn("gestalt: git fetch failed, resolving ref locally", "repo", s.Repo, "ref", ref, "error", e)
+		}
+	}
+
+	commit, e := s.git(ctx, "-C", s.Dir, "rev-parse", "--verify", "--end-of-options", rev+"^{commit}")
+	if e != nil {
+		return nil, e
+	}
+	content, e := s.git(ctx, "-C", s.Dir, "show", commit+":"+s.Path, "--")
+	if e != nil {
+		return nil, e
+	}
+	p, e := LoadStr(content, s.opts...)
+	if e != nil {
+		return nil, fmt.Errorf("gestalt file <%s> at commit %s - %s", s.Path, commit, e)
+	}
+	s.commit = commit
+	return p, nil
+}
+
+// runs git with args and returns its trimmed stdout
+func (s *GitSource) git(ctx context.Context, args ...string) (string, error) {
+	stdout, stderr, e := runCommand(ctx, "git", args...)
+	if e != nil {
+		return "", fmt.Errorf("git %s - %s: %s", args[len(args)-1], e, strings.TrimSpace(string(stderr)))
+	}
+	return strings.TrimRight(string(stdout), trimset), nil
+}
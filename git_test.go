@@ -0,0 +1,92 @@
+package gestalt
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// creates a git repository with a single commit of app.conf
+func gitRepo(t *testing.T, content string) string {
+	if _, e := exec.LookPath("git"); e != nil {
+		t.Skip("git is not available")
+	}
+	dir := t.TempDir()
+	if e := os.WriteFile(filepath.Join(dir, "app.conf"), []byte(content), 0644); e != nil {
+		t.Fatal(e)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet", "-b", "main"},
+		{"add", "app.conf"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "config"},
+	} {
+		if out, e := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); e != nil {
+			t.Fatalf("git %s - %s: %s", args[0], e, out)
+		}
+	}
+	return dir
+}
+
+func TestGitSource(t *testing.T) {
+	repo := gitRepo(t, "foo = bar\n")
+
+	local := NewGitSource("", repo, "", "app.conf")
+	p, e := local.Load(context.Background())
+	if e != nil || p.GetString("foo") != "bar" {
+		t.Fatalf("TestGitSource - Load(local) - expected: foo=bar, got: %s (%v)", p, e)
+	}
+	if len(local.Commit()) != 40 {
+		t.Errorf("TestGitSource - Commit - expected: commit hash, got: %s", local.Commit())
+	}
+
+	clone := NewGitSource(repo, filepath.Join(t.TempDir(), "clone"), "main", "app.conf")
+	for i := 0; i < 2; i++ {
+		p, e = clone.Load(context.Background())
+		if e != nil || p.GetString("foo") != "bar" {
+			t.Fatalf("TestGitSource - Load(clone) - expected: foo=bar, got: %s (%v)", p, e)
+		}
+	}
+	if clone.Commit() != local.Commit() {
+		t.Errorf("TestGitSource - Commit - expected: %s, got: %s", local.Commit(), clone.Commit())
+	}
+
+	if _, e := NewGitSource("", repo, "", "nosuch.conf").Load(context.Background()); e == nil {
+		t.Errorf("TestGitSource - Load - error expected for missing path")
+	}
+}
+
+func TestGitSourceFetch(t *testing.T) {
+	repo := gitRepo(t, "foo = bar\n")
+	dir := filepath.Join(t.TempDir(), "clone")
+	clone := NewGitSource(repo, dir, "main", "app.conf")
+	if _, e := clone.Load(context.Background()); e != nil {
+		t.Fatalf("TestGitSourceFetch - Load - %s", e)
+	}
+	if prov := clone.Provenance(); prov["commit"] != clone.Commit() || prov["ref"] != "main" {
+		t.Errorf("TestGitSourceFetch - Provenance - expected commit and ref, got: %v", prov)
+	}
+
+	// the remote is gone
+	if e := os.RemoveAll(repo); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := clone.Load(context.Background()); e == nil {
+		t.Errorf("TestGitSourceFetch - Load - error expected for failed fetch")
+	}
+	clone.Offline = true
+	if p, e := clone.Load(context.Background()); e != nil || p.GetString("foo") != "bar" {
+		t.Errorf("TestGitSourceFetch - Load(Offline) - expected: foo=bar, got: %s (%v)", p, e)
+	}
+
+	for _, s := range []*GitSource{
+		NewGitSource("", dir, "--output=/tmp/x", "app.conf"),
+		NewGitSource("", dir, "", "-p"),
+		NewGitSource("--upload-pack=touch /tmp/x", dir, "", "app.conf"),
+	} {
+		if _, e := s.Load(context.Background()); e == nil {
+			t.Errorf("TestGitSourceFetch - Load - error expected for option argument of %+v", s)
+		}
+	}
+}
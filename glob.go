@@ -0,0 +1,164 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ----------------------------------------------------------------------
+// Glob patterns
+//
+// path patterns, e.g. of include/exclude keys, with the syntax:
+//
+//	*        any sequence of chars other than /
+//	?        any single char other than /
+//	[abc]    any of the chars of the class, [!abc] any other char
+//	{a,b}    any of the (comma separated) alternatives
+//	**       any sequence of path segments, as a whole segment only,
+//	         e.g. src/**/*.go
+//
+// the `\` char is reserved (see GetRegexp), and special chars are matched
+// literally with classes, e.g. [*]. Note that alternatives are not available
+// in the elements of array keys, as commas separate the elements - use
+// several elements instead.
+// ----------------------------------------------------------------------
+
+// Glob is a compiled glob pattern. See GetGlob.
+type Glob struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// Compiles the glob pattern. Returns error if pattern is malformed.
+func CompileGlob(pattern string) (*Glob, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	braces := 0
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				if (i > 0 && pattern[i-1] != '/') || (i+2 < len(pattern) && pattern[i+2] != '/') {
+					return nil, fmt.Errorf("malformed glob <%s> - ** must be a whole path segment", pattern)
+				}
+				if i+2 < len(pattern) {
+					b.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					b.WriteString(".*")
+					i++
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			j := strings.IndexByte(pattern[i+1:], ']')
+			if j < 0 {
+				return nil, fmt.Errorf("malformed glob <%s> - unterminated [", pattern)
+			}
+			class := pattern[i+1 : i+1+j]
+			b.WriteString("[")
+			if strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^") {
+				b.WriteString("^/")
+				class = class[1:]
+			}
+			if class == empty {
+				return nil, fmt.Errorf("malformed glob <%s> - empty []", pattern)
+			}
+			for _, cc := range class {
+				if cc == '-' {
+					b.WriteRune(cc)
+				} else {
+					b.WriteString(regexp.QuoteMeta(string(cc)))
+				}
+			}
+			b.WriteString("]")
+			i += j + 1
+		case '{':
+			braces++
+			b.WriteString("(?:")
+		case '}':
+			if braces == 0 {
+				return nil, fmt.Errorf("malformed glob <%s> - unmatched }", pattern)
+			}
+			braces--
+			b.WriteString(")")
+		case ',':
+			if braces > 0 {
+				b.WriteString("|")
+			} else {
+				b.WriteByte(c)
+			}
+		case ']':
+			return nil, fmt.Errorf("malformed glob <%s> - unmatched ]", pattern)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if braces > 0 {
+		return nil, fmt.Errorf("malformed glob <%s> - unterminated {", pattern)
+	}
+	b.WriteString("$")
+	re, e := regexp.Compile(b.String())
+	if e != nil {
+		return nil, fmt.Errorf("malformed glob <%s> - %s", pattern, e)
+	}
+	return &Glob{pattern, re}, nil
+}
+
+// Returns true if the path name (with / or OS specific separators)
+// matches the pattern.
+func (g *Glob) Match(name string) bool {
+	return g.re.MatchString(filepath.ToSlash(name))
+}
+
+// Returns true if name matches any of the patterns.
+func MatchAny(globs []*Glob, name string) bool {
+	for _, g := range globs {
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Glob) String() string {
+	return g.pattern
+}
+
+// Glob value property, e.g. `backup.exclude = **/*.{tmp,log}` - returns
+// error if no such key or value is not a valid glob pattern.
+// Compiled patterns are cached.
+func (p Properties) GetGlob(key string) (*Glob, error) {
+	v, e := p.typed(key, typed_glob)
+	if e != nil {
+		return nil, e
+	}
+	return v.(*Glob), nil
+}
+
+// Glob array value property, e.g. `build.include[] = src/**, docs/*.md` -
+// returns error if no such key or an element is not a valid glob pattern.
+func (p Properties) GetGlobArray(key string) ([]*Glob, error) {
+	arrv, e := p.arrayValue(key)
+	if e != nil {
+		return nil, e
+	}
+	patterns := make([]*Glob, len(arrv))
+	for i, av := range arrv {
+		g, e := converters[typed_glob](av)
+		if e != nil {
+			return nil, wrongType("key <%s> - element %d - %s", key, i, e)
+		}
+		patterns[i] = g.(*Glob)
+	}
+	return patterns, nil
+}
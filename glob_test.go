@@ -0,0 +1,70 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"testing"
+)
+
+func TestCompileGlob(t *testing.T) {
+	for _, c := range []struct {
+		pattern, name string
+		expected      bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/a/b/main.go", true},
+		{"src/**", "src/a/b", true},
+		{"**/*.{tmp,log}", "var/x.log", true},
+		{"**/*.{tmp,log}", "x.tmp", true},
+		{"**/*.{tmp,log}", "x.txt", false},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"[a-c]*", "beta", true},
+		{"[!a-c]*", "beta", false},
+		{"[*].md", "*.md", true},
+		{"[*].md", "a.md", false},
+	} {
+		g, e := CompileGlob(c.pattern)
+		if e != nil {
+			t.Errorf("TestCompileGlob - CompileGlob(%s) - unexpected error: %s", c.pattern, e)
+			continue
+		}
+		if g.Match(c.name) != c.expected {
+			t.Errorf("TestCompileGlob - <%s>.Match(%s) - expected: %t", c.pattern, c.name, c.expected)
+		}
+	}
+	for _, pattern := range []string{"a**", "**b/c", "[abc", "a]", "{a,b", "a}", "[]"} {
+		if _, e := CompileGlob(pattern); e == nil {
+			t.Errorf("TestCompileGlob - CompileGlob(%s) - error expected", pattern)
+		}
+	}
+}
+
+func TestGetGlob(t *testing.T) {
+	p := Properties{"exclude": "**/*.{tmp,log}", "bad": "[abc", "include[]": []string{"src/**", "docs/*.md"}}
+	g, e := p.GetGlob("exclude")
+	if e != nil || g.String() != "**/*.{tmp,log}" || !g.Match("a/b.tmp") {
+		t.Errorf("TestGetGlob - GetGlob(exclude) - got: %v, %v", g, e)
+	}
+	if again, _ := p.GetGlob("exclude"); again != g {
+		t.Errorf("TestGetGlob - GetGlob(exclude) - expected cached glob")
+	}
+	for _, key := range []string{"bad", "nope"} {
+		if _, e := p.GetGlob(key); e == nil {
+			t.Errorf("TestGetGlob - GetGlob(%s) - error expected", key)
+		}
+	}
+	include, e := p.GetGlobArray("include[]")
+	if e != nil || len(include) != 2 || !MatchAny(include, "docs/README.md") || MatchAny(include, "README.md") {
+		t.Errorf("TestGetGlob - GetGlobArray(include[]) - got: %v, %v", include, e)
+	}
+
+	s := &Schema{Keys: []KeySpec{{Key: "exclude", Type: TypeGlob}, {Key: "bad", Type: TypeGlob}, {Key: "include[]", Type: TypeGlob}}}
+	if errs := s.Validate(p); len(errs) != 1 || errs[0].(*KeyError).Key != "bad" {
+		t.Errorf("TestGetGlob - Validate - expected error of key bad, got: %v", errs)
+	}
+}
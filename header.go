@@ -0,0 +1,68 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Header map value property, e.g. of default request or response headers.
+// Header names are canonicalized (see http.CanonicalHeaderKey). Map keys
+// define a single value per header:
+//
+//	client.headers[:] = user-agent:gestalt/1.0, x-client:web
+//
+// Array keys define headers as "<name>: <value>" elements, and support
+// multi-valued headers (and values with `:` chars):
+//
+//	client.headers[] = Accept: text/html, Accept: application/json
+//
+// Returns error if no such key, or a header name or value is malformed.
+func (p Properties) GetHeaderMap(key string) (http.Header, error) {
+	h := make(http.Header)
+	if isArrayKey(key) {
+		arrv, e := p.arrayValue(key)
+		if e != nil {
+			return nil, e
+		}
+		for i, av := range arrv {
+			name, value, ok := strings.Cut(av, kv_delim)
+			if !ok {
+				return nil, wrongType("key <%s> - element %d - malformed header <%s>", key, i, av)
+			}
+			if e := addHeader(h, name, value); e != nil {
+				return nil, wrongType("key <%s> - element %d - %s", key, i, e)
+			}
+		}
+		return h, nil
+	}
+	mapv, e := p.mapValue(key)
+	if e != nil {
+		return nil, e
+	}
+	for _, mk := range sortedKeys(mapv) {
+		if e := addHeader(h, mk, mapv[mk]); e != nil {
+			return nil, wrongType("key <%s> - map key <%s> - %s", key, mk, e)
+		}
+	}
+	return h, nil
+}
+
+// adds the (trimmed) header to h, if well-formed
+func addHeader(h http.Header, name, value string) error {
+	name, value = strings.Trim(name, ws), strings.Trim(value, ws)
+	if name == empty || strings.IndexFunc(name, func(c rune) bool {
+		return c <= ' ' || c >= 0x7f || strings.ContainsRune("\"(),/:;<=>?@[]{}", c)
+	}) >= 0 {
+		return fmt.Errorf("malformed header name <%s>", name)
+	}
+	if strings.ContainsAny(value, "\r\n\x00") {
+		return fmt.Errorf("malformed value of header <%s>", name)
+	}
+	h.Add(name, value)
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetHeaderMap(t *testing.T) {
+	p, e := LoadStr(`
+client.headers[:] = user-agent:gestalt/1.0, x-client:web
+server.headers[] = Accept: text/html, accept: application/json, X-Time: 12:30
+bad.headers[] = no colon
+bad.names[:] = bad name:x
+`)
+	if e != nil {
+		t.Fatalf("TestGetHeaderMap - LoadStr - unexpected error: %s", e)
+	}
+	for key, expected := range map[string]http.Header{
+		"client.headers[:]": {"User-Agent": {"gestalt/1.0"}, "X-Client": {"web"}},
+		"server.headers[]":  {"Accept": {"text/html", "application/json"}, "X-Time": {"12:30"}},
+	} {
+		if h, e := p.GetHeaderMap(key); e != nil || !reflect.DeepEqual(h, expected) {
+			t.Errorf("TestGetHeaderMap - GetHeaderMap(%s) - expected: %v, got: %v, %v", key, expected, h, e)
+		}
+	}
+	for _, key := range []string{"bad.headers[]", "bad.names[:]", "nope[:]", "nope"} {
+		if _, e := p.GetHeaderMap(key); e == nil {
+			t.Errorf("TestGetHeaderMap - GetHeaderMap(%s) - error expected", key)
+		}
+	}
+}
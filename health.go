@@ -0,0 +1,76 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"time"
+)
+
+// Health is the state of the loads of a Watcher, e.g. for readiness
+// endpoints:
+//
+//	http.HandleFunc("/ready", func(rw http.ResponseWriter, r *http.Request) {
+//		h := w.Health()
+//		if !h.Healthy {
+//			rw.WriteHeader(http.StatusServiceUnavailable)
+//		}
+//		json.NewEncoder(rw).Encode(h)
+//	})
+type Health struct {
+	Healthy   bool          `json:"healthy"`              // not stale, and the latest reload succeeded, and was valid
+	Version   Version       `json:"version"`              // of the current Properties
+	LastLoad  time.Time     `json:"last_load"`            // of the latest successful (accepted) reload
+	LastError string        `json:"last_error,omitempty"` // of the latest reload, if it failed (or was rejected)
+	Staleness time.Duration `json:"staleness"`            // since LastLoad
+	Stale     bool          `json:"stale"`                // Staleness exceeds the TTL, if any (see StaleAfter)
+	Invalid   []string      `json:"invalid,omitempty"`    // validation failures of the latest reload, not applied
+}
+
+// StaleAfter sets the TTL of the Properties of a Watcher: if not reloaded
+// successfully within ttl, e.g. of a source that is down, its Health is
+// Stale. Note that reloads of unchanged Properties are successful.
+func StaleAfter(ttl time.Duration) WatchOption {
+	return func(w *Watcher) {
+		w.ttl = ttl
+	}
+}
+
+// Returns the Health of w.
+func (w *Watcher) Health() Health {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	h := Health{
+		Version:   w.version,
+		LastLoad:  w.loaded,
+		Staleness: w.clock.Now().Sub(w.loaded),
+	}
+	h.Stale = w.ttl > 0 && h.Staleness > w.ttl
+	if w.err != nil {
+		h.LastError = w.err.Error()
+	}
+	if w.invalid != nil {
+		var errs []error
+		if !keyErrors(w.invalid, &errs) {
+			errs = []error{w.invalid}
+		}
+		for _, e := range errs {
+			h.Invalid = append(h.Invalid, e.Error())
+		}
+	}
+	h.Healthy = !h.Stale && w.err == nil
+	return h
+}
+
+// records the outcome of a load at time now: ok is true if the load
+// succeeded (and was applied, if changed), e is the error of the load,
+// and invalid the error of its validation, if any.
+func (w *Watcher) loadDone(now time.Time, ok bool, e error, invalid error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if ok {
+		w.loaded = now
+	}
+	w.err, w.invalid = e, invalid
+}
@@ -0,0 +1,66 @@
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatcherHealth(t *testing.T) {
+	var n int32
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		switch atomic.AddInt32(&n, 1) {
+		case 1:
+			return Properties{"port": "8080"}, nil
+		case 2:
+			return nil, errors.New("endpoint down")
+		case 3:
+			return Properties{"port": "http"}, nil
+		}
+		return Properties{"port": "9090"}, nil
+	})
+	schema := &Schema{Keys: []KeySpec{{Key: "port", Type: TypeInt}}}
+	t0 := time.Unix(1000, 0)
+	clock := NewManualClock(t0)
+
+	events := make(chan error, 4)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) {
+		events <- e
+	}, WithValidators(SchemaValidator(schema)), WatchClock(clock), StaleAfter(5*time.Minute))
+	if e != nil {
+		t.Fatalf("TestWatcherHealth - Watch - %s", e)
+	}
+	defer w.Stop()
+	if h := w.Health(); !h.Healthy || !h.LastLoad.Equal(t0) || h.Version.N != 1 || h.LastError != "" {
+		t.Errorf("TestWatcherHealth - Health after load - got: %+v", h)
+	}
+
+	clock.Advance(time.Minute)
+	w.Reload()
+	<-events
+	h := w.Health()
+	if h.Healthy || h.Stale || h.LastError != "endpoint down" || h.Staleness != time.Minute || len(h.Invalid) != 0 {
+		t.Errorf("TestWatcherHealth - Health after failed reload - got: %+v", h)
+	}
+	clock.Advance(5 * time.Minute)
+	if h := w.Health(); !h.Stale || h.Staleness != 6*time.Minute {
+		t.Errorf("TestWatcherHealth - Health - expected stale, got: %+v", h)
+	}
+
+	w.Reload()
+	<-events
+	h = w.Health()
+	if h.Healthy || len(h.Invalid) != 1 || !strings.HasPrefix(h.Invalid[0], "key <port>") || !strings.Contains(h.LastError, "rejected") {
+		t.Errorf("TestWatcherHealth - Health after rejected reload - got: %+v", h)
+	}
+
+	w.Reload()
+	<-events
+	h = w.Health()
+	if !h.Healthy || h.Stale || h.Staleness != 0 || h.Version.N != 2 || h.LastError != "" || h.Invalid != nil {
+		t.Errorf("TestWatcherHealth - Health after reload - got: %+v", h)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"strings"
+)
+
+// Inline type hints
+//
+// with the TypeHints option, keys may carry a type hint, of the schema
+// types (see Schema), as a `:<type>` suffix:
+//
+//	server.port:int = 8080
+//	server.debug:bool = true
+//	retry.backoff[]:duration = 100ms, 1s
+//
+// Values are validated on load, and the hints are stripped from the keys,
+// e.g. the first key above is "server.port". The `secret` hint marks
+// (string) keys secret (see MarkSecret), e.g. `hmac.key:secret = c2VjcmV0`.
+// Extensions (+=) of keys are not hinted. Without the option, the `:` char
+// is part of keys.
+// ----------------------------------------------------------------------
+
+const (
+	hint_sep    = ":"
+	hint_secret = "secret"
+)
+
+// TypeHints enables inline type hints of keys (see above). The hinted
+// types are recorded in schema (if not nil), as KeySpecs of the keys, e.g.
+// for validation of subsequent changes. The fragments of LoadDir are
+// recorded in order, so the latest hint of a key wins.
+func TypeHints(schema *Schema) LoadOption {
+	return func(o *loadOptions) {
+		o.hints = true
+		o.hinted = schema
+	}
+}
+
+// returns key without its type hint, if any, having validated vrep
+// per the type, and whether the hint marks key secret. Returns a
+// *KeyError if vrep is not of the type.
+func (o *loadOptions) typeHint(key string, vrep string) (string, bool, error) {
+	i := strings.LastIndex(key, hint_sep)
+	if i < 0 {
+		return key, false, nil
+	}
+	hint := strings.Trim(key[i+len(hint_sep):], ws)
+	secret := hint == hint_secret
+	if _, ok := schemaTypes[hint]; !ok && hint != TypeString && !secret {
+		return key, false, nil
+	}
+	key = strings.Trim(key[:i], ws)
+	plain, _, _, _ := splitWindow(key)
+	spec := KeySpec{Key: plain, Type: hint, Secret: secret}
+	if secret {
+		spec.Type = TypeString
+	}
+	if vrep != unset {
+		v, e := parseValue(plain, vrep)
+		if e != nil {
+			return key, secret, e
+		}
+		if e := spec.validate(v); e != nil {
+			return key, secret, &KeyError{plain, e}
+		}
+	}
+	if s := o.hinted; s != nil {
+		if prev := s.Spec(plain); prev != nil {
+			prev.Type, prev.Secret = spec.Type, prev.Secret || secret
+		} else {
+			s.Keys = append(s.Keys, spec)
+		}
+	}
+	return key, secret, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTypeHints(t *testing.T) {
+	var s Schema
+	p, e := LoadStr(`
+server.port:int = 8080
+server.debug : bool = true
+retry.backoff[]:duration = 100ms, 1s
+routes[:]:string = a:/a
+label:x = y
+`, TypeHints(&s))
+	if e != nil {
+		t.Fatalf("TestTypeHints - LoadStr - unexpected error: %s", e)
+	}
+	if port, e := p.GetInt("server.port"); e != nil || port != 8080 {
+		t.Errorf("TestTypeHints - GetInt(server.port) - expected: 8080, got: %d, %v", port, e)
+	}
+	if debug, e := p.GetBool("server.debug"); e != nil || !debug {
+		t.Errorf("TestTypeHints - GetBool(server.debug) - expected: true, got: %t, %v", debug, e)
+	}
+	if d, e := p.GetDurationArray("retry.backoff[]"); e != nil || len(d) != 2 || d[1] != time.Second {
+		t.Errorf("TestTypeHints - GetDurationArray(retry.backoff[]) - got: %v, %v", d, e)
+	}
+	if p.GetMap("routes[:]")["a"] != "/a" || p.GetString("label:x") != "y" {
+		t.Errorf("TestTypeHints - expected routes[:] and unhinted label:x, got: %v", p)
+	}
+	if len(s.Keys) != 4 || s.Spec("server.port").Type != TypeInt || s.Spec("retry.backoff[]").Type != TypeDuration {
+		t.Errorf("TestTypeHints - expected recorded types, got: %+v", s.Keys)
+	}
+
+	_, e = LoadStr("server.port:int = http\n", TypeHints(nil))
+	var ke *KeyError
+	if !errors.As(e, &ke) || ke.Key != "server.port" {
+		t.Errorf("TestTypeHints - LoadStr - expected KeyError of server.port, got: %v", e)
+	}
+	if p, _ := LoadStr("server.port:int = http\n"); p.GetString("server.port:int") != "http" {
+		t.Errorf("TestTypeHints - LoadStr without TypeHints - expected key server.port:int, got: %v", p)
+	}
+}
+
+func TestTypeHintsLoadDir(t *testing.T) {
+	fragments := map[string]string{"00-base.conf": "port:string = http\n"}
+	for i := 10; i < 40; i++ {
+		fragments[fmt.Sprintf("%02d-frag.conf", i)] = fmt.Sprintf("key.%d:int = %d\nport:int = %d\n", i, i, i)
+	}
+	dir := writeFragments(t, fragments)
+
+	schema := &Schema{}
+	prop, e := LoadDir(context.Background(), dir, Workers(4), TypeHints(schema))
+	if e != nil {
+		t.Fatalf("TestTypeHintsLoadDir - LoadDir - %s", e)
+	}
+	if v := prop.GetString("port"); v != "39" {
+		t.Errorf("TestTypeHintsLoadDir - GetString(port) - expected: 39, got: %s", v)
+	}
+	if n := len(schema.Keys); n != 31 {
+		t.Errorf("TestTypeHintsLoadDir - hinted keys - expected: 31, got: %d", n)
+	}
+	if spec := schema.Spec("port"); spec == nil || spec.Type != TypeInt {
+		t.Errorf("TestTypeHintsLoadDir - Spec(port) - expected type int of the latest fragment, got: %v", spec)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Version identifies a revision of versioned Properties, e.g. of
+// SafeProperties or a Watcher. Versions are numbered from 1, and stamped
+// with the time of their load, merge, or apply.
+type Version struct {
+	N    uint64
+	Time time.Time
+}
+
+// returns the version succeeding v, at time now
+func (v Version) next(now time.Time) Version {
+	return Version{v.N + 1, now}
+}
+
+// kinds of changes
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change is a change of the value of a key. Values are in plain form.
+type Change struct {
+	Key      string `json:"key"`
+	Kind     string `json:"kind"` // one of the Change constants
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	Redacted bool   `json:"redacted,omitempty"` // values of a sensitive key are omitted
+}
+
+// Sensitive reports whether the value of key is sensitive, e.g. per a
+// Schema (see Schema.Sensitive) or an ACL (see ACL.Sensitive).
+type Sensitive func(key string) bool
+
+// Returns the changes from Properties from to Properties to, by key.
+// @unset keys are not defined, i.e. keys unset in to are removed. The
+// values of keys marked secret are redacted (see RedactedDiff).
+func Diff(from, to Properties) []Change {
+	return RedactedDiff(from, to, nil)
+}
+
+// Returns the changes from Properties from to Properties to, as Diff, with
+// the values of sensitive keys redacted, e.g. for diffs posted to logs:
+// changes of sensitive keys are reported, but their Old and New values are
+// omitted. Keys marked secret by from or to (see MarkSecret) are sensitive,
+// and nil sensitive redacts no other keys.
+func RedactedDiff(from, to Properties, sensitive Sensitive) []Change {
+	var changes []Change
+	change := func(c Change) {
+		if from.IsSecret(c.Key) || to.IsSecret(c.Key) || (sensitive != nil && sensitive(c.Key)) {
+			c.Old, c.New, c.Redacted = empty, empty, true
+		}
+		changes = append(changes, c)
+	}
+	for _, k := range sortedKeys(from) {
+		if !defined(from, k) {
+			continue
+		}
+		old := plainRep(from[k])
+		if !defined(to, k) {
+			change(Change{Key: k, Kind: ChangeRemoved, Old: old})
+		} else if v := plainRep(to[k]); v != old {
+			change(Change{Key: k, Kind: ChangeChanged, Old: old, New: v})
+		}
+	}
+	for _, k := range sortedKeys(to) {
+		if defined(to, k) && !defined(from, k) {
+			change(Change{Key: k, Kind: ChangeAdded, New: plainRep(to[k])})
+		}
+	}
+	return changes
+}
+
+// returns true if key is in p, and not @unset
+func defined(p Properties, key string) bool {
+	v, ok := p[key]
+	return ok && !isUnset(v)
+}
+
+// History is a JSON-lines log of the versions of versioned Properties,
+// and their changes. Each line is an entry of the form
+//
+//	{"version":2,"time":"2015-06-01T12:00:00Z","op":"apply","changes":[{"key":"db.host","kind":"changed","old":"a","new":"b"}]}
+//
+// History is safe for concurrent use.
+type History struct {
+	mu        sync.Mutex
+	w         io.Writer
+	c         io.Closer
+	sensitive Sensitive
+}
+
+type historyEntry struct {
+	Version uint64    `json:"version"`
+	Time    time.Time `json:"time"`
+	Op      string    `json:"op"`
+	Changes []Change  `json:"changes"`
+}
+
+// Instantiates a new History writing to w.
+func NewHistory(w io.Writer) *History {
+	return &History{w: w}
+}
+
+// Instantiates a new History appending to the specified file, which is
+// created if it does not exist.
+func OpenHistory(filename string) (*History, error) {
+	f, e := appendFile(filename)
+	if e != nil {
+		return nil, e
+	}
+	return &History{w: f, c: f}, nil
+}
+
+// Closes the history file, if opened by OpenHistory.
+func (h *History) Close() error {
+	if h.c == nil {
+		return nil
+	}
+	return h.c.Close()
+}
+
+// Redacts the values of sensitive keys of subsequently recorded changes.
+// See RedactedDiff.
+func (h *History) Redact(sensitive Sensitive) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sensitive = sensitive
+}
+
+// records version v of op, changing from to to. nil h is a no-op.
+func (h *History) record(v Version, op string, from, to Properties) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b, e := json.Marshal(historyEntry{v.N, v.Time, op, RedactedDiff(from, to, h.sensitive)})
+	if e != nil {
+		return e
+	}
+	_, e = h.w.Write(append(b, '\n'))
+	return e
+}
@@ -0,0 +1,141 @@
+package gestalt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	from := Properties{"a": "1", "b": "2", "c[]": []string{"x", "y"}}
+	to := Properties{"a": "1", "b": "3", "d": "4"}
+	expected := []Change{
+		{Key: "b", Kind: ChangeChanged, Old: "2", New: "3"},
+		{Key: "c[]", Kind: ChangeRemoved, Old: "x, y"},
+		{Key: "d", Kind: ChangeAdded, New: "4"},
+	}
+	if changes := Diff(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("TestDiff - Diff - expected: %v, got: %v", expected, changes)
+	}
+
+	from = Properties{"a": "1", "b": unsetValue{}}
+	to = Properties{"a": unsetValue{}, "b": unsetValue{}, "c": unsetValue{}}
+	expected = []Change{{Key: "a", Kind: ChangeRemoved, Old: "1"}}
+	if changes := Diff(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("TestDiff - Diff(@unset) - expected: %v, got: %v", expected, changes)
+	}
+}
+
+func TestRedactedDiff(t *testing.T) {
+	from := Properties{"db.host": "a", "db.password": "s3cret", "api.token": "t1"}
+	to := Properties{"db.host": "b", "db.password": "hunter2", "api.key": "k1"}
+	schema := &Schema{Keys: []KeySpec{{Key: "db.password", Secret: true}}}
+	acl := NewACL(AccessPublic)
+	acl.Set("api.*", AccessSecret)
+
+	expected := []Change{
+		{Key: "api.token", Kind: ChangeRemoved, Redacted: true},
+		{Key: "db.host", Kind: ChangeChanged, Old: "a", New: "b"},
+		{Key: "db.password", Kind: ChangeChanged, Redacted: true},
+		{Key: "api.key", Kind: ChangeAdded, Redacted: true},
+	}
+	sensitive := func(key string) bool { return schema.Sensitive(key) || acl.Sensitive(key) }
+	if changes := RedactedDiff(from, to, sensitive); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("TestRedactedDiff - RedactedDiff - expected: %v, got: %v", expected, changes)
+	}
+
+	var buf bytes.Buffer
+	h := NewHistory(&buf)
+	h.Redact(schema.Sensitive)
+	h.record(Version{N: 2}, "reload", from, to)
+	if s := buf.String(); bytes.Contains(buf.Bytes(), []byte("hunter2")) || !bytes.Contains(buf.Bytes(), []byte(`"redacted":true`)) {
+		t.Errorf("TestRedactedDiff - History - expected redacted db.password, got: %s", s)
+	}
+
+	kr := fakeKeyring{"db/app": "hunter2"}
+	from = Properties{"db.host": "a"}
+	to, e := LoadStr("db.host = a\ndb.password = keyring:db/app\n", WithKeyring(kr))
+	if e != nil {
+		t.Fatalf("TestRedactedDiff - LoadStr - %s", e)
+	}
+	expected = []Change{{Key: "db.password", Kind: ChangeAdded, Redacted: true}}
+	if changes := Diff(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("TestRedactedDiff - Diff(keyring) - expected: %v, got: %v", expected, changes)
+	}
+	buf.Reset()
+	NewHistory(&buf).record(Version{N: 2}, "reload", from, to)
+	if s := buf.String(); strings.Contains(s, "hunter2") {
+		t.Errorf("TestRedactedDiff - History(keyring) - expected redacted db.password, got: %s", s)
+	}
+}
+
+func TestSafePropertiesHistory(t *testing.T) {
+	var buf bytes.Buffer
+	sp := NewSafeProperties(Properties{"db.host": "a"})
+	sp.SetHistory(NewHistory(&buf))
+	v1 := sp.Version()
+
+	sp.Apply(func(tx *Txn) error { return tx.Set("db.host", "b") })
+	sp.Merge(Properties{"db.port": "5432"})
+	sp.Replace(Properties{"db.host": "c"})
+
+	if v := sp.Version(); v1.N != 1 || v.N != 4 || v.Time.Before(v1.Time) {
+		t.Errorf("TestSafePropertiesHistory - Version() - expected: 4 after 1, got: %v after %v", v, v1)
+	}
+
+	var entries []historyEntry
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry historyEntry
+		if e := json.Unmarshal(scanner.Bytes(), &entry); e != nil {
+			t.Fatalf("TestSafePropertiesHistory - invalid entry: %s", e)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("TestSafePropertiesHistory - expected: 3 entries, got: %d", len(entries))
+	}
+	for i, op := range []string{"apply", "merge", "load"} {
+		if entries[i].Op != op || entries[i].Version != uint64(i+2) {
+			t.Errorf("TestSafePropertiesHistory - entry %d - expected: %s version %d, got: %v", i, op, i+2, entries[i])
+		}
+	}
+	if c := entries[2].Changes; len(c) != 2 || c[0].Key != "db.host" || c[0].New != "c" || c[1].Kind != ChangeRemoved {
+		t.Errorf("TestSafePropertiesHistory - entry 2 - unexpected changes: %v", c)
+	}
+}
+
+func TestWatcherHistory(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "history.jsonl")
+	h, e := OpenHistory(filename)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer h.Close()
+
+	n := 0
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		n++
+		return Properties{"n": string(rune('0' + n))}, nil
+	})
+	changed := make(chan struct{}, 1)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) { changed <- struct{}{} }, WithHistory(h))
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer w.Stop()
+	w.Reload()
+	<-changed
+	if v := w.Version(); v.N != 2 {
+		t.Errorf("TestWatcherHistory - Version() - expected: 2, got: %d", v.N)
+	}
+	if b, _ := os.ReadFile(filename); bytes.Count(b, []byte("\n")) != 2 || !bytes.Contains(b, []byte(`"op":"reload"`)) {
+		t.Errorf("TestWatcherHistory - expected load and reload entries, got: %s", b)
+	}
+}
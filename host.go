@@ -0,0 +1,100 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !js && !tinygo
+
+package gestalt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Host services
+//
+// the OS dependent services of the package (files, environment, terminal,
+// and commands) are provided by the functions of this file, so that the
+// parser and Properties API compile for targets without an OS, i.e. js/wasm
+// and tinygo, where the services are unavailable (see host_other.go).
+// ----------------------------------------------------------------------
+
+func readHostFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+func openHostFile(name string) (fs.File, error) { return os.Open(name) }
+
+func writeHostFile(name string, b []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, b, perm)
+}
+
+// opens the named file for appending, creating it if it does not exist
+func appendFile(name string) (io.WriteCloser, error) {
+	return os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+}
+
+func globFiles(pattern string) ([]string, error) { return filepath.Glob(pattern) }
+
+func statFile(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+// verifies that files can be created in directory dir
+func checkCreate(dir string) error {
+	f, e := os.CreateTemp(dir, ".gestalt-*")
+	if e != nil {
+		return e
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// verifies that the named (existing) file can be opened for writing
+func checkWrite(name string) error {
+	f, e := os.OpenFile(name, os.O_WRONLY, 0)
+	if e != nil {
+		return e
+	}
+	return f.Close()
+}
+
+func lookupEnv(name string) (string, bool) { return os.LookupEnv(name) }
+
+// replaces $VAR or ${VAR} in s with the values of environment variables.
+// See os.ExpandEnv.
+func expandEnv(s string) string { return os.ExpandEnv(s) }
+
+func userHomeDir() (string, error) { return os.UserHomeDir() }
+
+// returns the input and output of the process's terminal, or false if
+// stdin is not a terminal (TTY).
+func terminal() (*bufio.Reader, io.Writer, bool) {
+	fi, e := os.Stdin.Stat()
+	if e != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return nil, nil, false
+	}
+	return bufio.NewReader(os.Stdin), os.Stderr, true
+}
+
+// sets the echo of terminal input, where supported (via stty)
+func setEcho(on bool) error {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// runs command name with args, and returns its stdout and stderr
+func runCommand(ctx context.Context, name string, args ...string) (stdout, stderr []byte, e error) {
+	var outb, errb bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &outb, &errb
+	e = cmd.Run()
+	return outb.Bytes(), errb.Bytes(), e
+}
@@ -0,0 +1,70 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build js || tinygo
+
+package gestalt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime"
+)
+
+// host services of targets without an OS (see host.go). Files are provided
+// by a FileSystem (see WithFileSystem and FS), e.g. an embed.FS, and there
+// is no environment, i.e. environment variables are not defined, and
+// references to them (e.g. of ExpandPath) are not expanded.
+
+var errNoHost = fmt.Errorf("not supported on %s/%s", runtime.GOOS, runtime.GOARCH)
+
+func readHostFile(name string) ([]byte, error) {
+	return nil, &fs.PathError{Op: "read", Path: name, Err: errNoHost}
+}
+
+func openHostFile(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: errNoHost}
+}
+
+func writeHostFile(name string, b []byte, perm fs.FileMode) error {
+	return &fs.PathError{Op: "write", Path: name, Err: errNoHost}
+}
+
+func appendFile(name string) (io.WriteCloser, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: errNoHost}
+}
+
+func globFiles(pattern string) ([]string, error) {
+	return nil, &fs.PathError{Op: "glob", Path: pattern, Err: errNoHost}
+}
+
+func statFile(name string) (fs.FileInfo, error) {
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: errNoHost}
+}
+
+func checkCreate(dir string) error {
+	return &fs.PathError{Op: "create", Path: dir, Err: errNoHost}
+}
+
+func checkWrite(name string) error {
+	return &fs.PathError{Op: "open", Path: name, Err: errNoHost}
+}
+
+func lookupEnv(name string) (string, bool) { return "", false }
+
+func expandEnv(s string) string { return s }
+
+func userHomeDir() (string, error) { return "", errors.New("home directory " + errNoHost.Error()) }
+
+func terminal() (*bufio.Reader, io.Writer, bool) { return nil, nil, false }
+
+func setEcho(on bool) error { return errNoHost }
+
+func runCommand(ctx context.Context, name string, args ...string) (stdout, stderr []byte, e error) {
+	return nil, nil, fmt.Errorf("%s - %w", name, errNoHost)
+}
@@ -0,0 +1,164 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httpconf binds conventional groups of gestalt properties to
+// http.Server and http.Transport settings:
+//
+//	server.addr                     = :8080
+//	server.read_timeout             = 5s
+//	server.read_header_timeout      = 2s
+//	server.write_timeout            = 10s
+//	server.idle_timeout             = 2m
+//	server.max_header_bytes         = 65536
+//
+//	client.timeout                  = 30s
+//	client.max_idle_conns           = 100
+//	client.max_idle_conns_per_host  = 10
+//	client.idle_conn_timeout        = 90s
+//	client.tls_handshake_timeout    = 10s
+//
+//	proxy.url                       = http://proxy.local:3128   # or direct
+//
+// All keys are optional. Unspecified settings retain their net/http
+// defaults.
+package httpconf
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/alphazero/gestalt"
+)
+
+// conventional keys, relative to the group prefix
+const (
+	KeyAddr                = "addr"
+	KeyReadTimeout         = "read_timeout"
+	KeyReadHeaderTimeout   = "read_header_timeout"
+	KeyWriteTimeout        = "write_timeout"
+	KeyIdleTimeout         = "idle_timeout"
+	KeyMaxHeaderBytes      = "max_header_bytes"
+	KeyTimeout             = "timeout"
+	KeyMaxIdleConns        = "max_idle_conns"
+	KeyMaxIdleConnsPerHost = "max_idle_conns_per_host"
+	KeyIdleConnTimeout     = "idle_conn_timeout"
+	KeyTLSHandshakeTimeout = "tls_handshake_timeout"
+	KeyURL                 = "url"
+)
+
+// ProxyPrefix is the key group of the proxy of Transport.
+const ProxyPrefix = "proxy"
+
+// proxy url value of direct connections
+const direct = "direct"
+
+// binder sets the fields of the key group of prefix of p
+type binder struct {
+	p      gestalt.Properties
+	prefix string
+	e      error
+}
+
+func (b *binder) key(k string) string {
+	return b.prefix + "." + k
+}
+
+func (b *binder) duration(k string, d *time.Duration) {
+	if b.e != nil || b.p.GetString(b.key(k)) == "" {
+		return
+	}
+	*d, b.e = b.p.GetDuration(b.key(k))
+}
+
+func (b *binder) addr(k string, a *string) {
+	if b.e != nil || b.p.GetString(b.key(k)) == "" {
+		return
+	}
+	*a, b.e = b.p.GetListenAddr(b.key(k))
+}
+
+func (b *binder) int(k string, i *int) {
+	if b.e != nil || b.p.GetString(b.key(k)) == "" {
+		return
+	}
+	*i, b.e = b.p.GetInt(b.key(k))
+}
+
+// Returns a new http.Server of the key group of prefix (e.g. "server")
+// of p. The Handler of the server is not set.
+func Server(p gestalt.Properties, prefix string) (*http.Server, error) {
+	b := &binder{p: p, prefix: prefix}
+	s := &http.Server{}
+	b.addr(KeyAddr, &s.Addr)
+	b.duration(KeyReadTimeout, &s.ReadTimeout)
+	b.duration(KeyReadHeaderTimeout, &s.ReadHeaderTimeout)
+	b.duration(KeyWriteTimeout, &s.WriteTimeout)
+	b.duration(KeyIdleTimeout, &s.IdleTimeout)
+	b.int(KeyMaxHeaderBytes, &s.MaxHeaderBytes)
+	if b.e != nil {
+		return nil, b.e
+	}
+	return s, nil
+}
+
+// Returns a new http.Transport of the key group of prefix (e.g. "client")
+// of p, based on http.DefaultTransport. The proxy is set per the
+// ProxyPrefix group (see Proxy).
+func Transport(p gestalt.Properties, prefix string) (*http.Transport, error) {
+	b := &binder{p: p, prefix: prefix}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	b.int(KeyMaxIdleConns, &t.MaxIdleConns)
+	b.int(KeyMaxIdleConnsPerHost, &t.MaxIdleConnsPerHost)
+	b.duration(KeyIdleConnTimeout, &t.IdleConnTimeout)
+	b.duration(KeyTLSHandshakeTimeout, &t.TLSHandshakeTimeout)
+	if b.e != nil {
+		return nil, b.e
+	}
+
+	proxy, e := Proxy(p, ProxyPrefix)
+	if e != nil {
+		return nil, e
+	}
+	t.Proxy = proxy
+	return t, nil
+}
+
+// Returns a new http.Client of the key group of prefix (e.g. "client")
+// of p, with a Transport per Transport.
+func Client(p gestalt.Properties, prefix string) (*http.Client, error) {
+	t, e := Transport(p, prefix)
+	if e != nil {
+		return nil, e
+	}
+	c := &http.Client{Transport: t}
+	b := &binder{p: p, prefix: prefix}
+	if b.duration(KeyTimeout, &c.Timeout); b.e != nil {
+		return nil, b.e
+	}
+	return c, nil
+}
+
+// Returns the proxy function of the url key of the key group of prefix
+// (e.g. "proxy") of p. If not specified, the proxy is per the environment
+// (see http.ProxyFromEnvironment). The url "direct" disables proxying.
+func Proxy(p gestalt.Properties, prefix string) (func(*http.Request) (*url.URL, error), error) {
+	key := prefix + "." + KeyURL
+	switch rawurl := p.GetString(key); rawurl {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case direct:
+		return nil, nil
+	default:
+		u, e := url.Parse(rawurl)
+		if e != nil {
+			return nil, fmt.Errorf("key <%s> - %s", key, e)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("key <%s> - proxy url <%s> requires scheme and host", key, rawurl)
+		}
+		return http.ProxyURL(u), nil
+	}
+}
@@ -0,0 +1,66 @@
+package httpconf
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alphazero/gestalt"
+)
+
+func TestServer(t *testing.T) {
+	p, _ := gestalt.LoadStr(`
+server.addr = :8080
+server.read_timeout = 5s
+server.idle_timeout = 2m
+server.max_header_bytes = 65536
+`)
+	s, e := Server(p, "server")
+	if e != nil {
+		t.Fatalf("TestServer - Server - %s", e)
+	}
+	if s.Addr != ":8080" || s.ReadTimeout != 5*time.Second || s.IdleTimeout != 2*time.Minute || s.MaxHeaderBytes != 65536 || s.WriteTimeout != 0 {
+		t.Errorf("TestServer - Server - unexpected settings: %+v", s)
+	}
+
+	p, _ = gestalt.LoadStr("server.read_timeout = 5\n")
+	if _, e := Server(p, "server"); e == nil {
+		t.Errorf("TestServer - Server - error expected for invalid duration")
+	}
+	p, _ = gestalt.LoadStr("server.addr = 8080\n")
+	if _, e := Server(p, "server"); e == nil {
+		t.Errorf("TestServer - Server - error expected for invalid addr")
+	}
+}
+
+func TestClient(t *testing.T) {
+	p, _ := gestalt.LoadStr(`
+client.timeout = 30s
+client.max_idle_conns = 7
+client.idle_conn_timeout = 1m
+proxy.url = http://proxy.local:3128
+`)
+	c, e := Client(p, "client")
+	if e != nil {
+		t.Fatalf("TestClient - Client - %s", e)
+	}
+	tr := c.Transport.(*http.Transport)
+	if c.Timeout != 30*time.Second || tr.MaxIdleConns != 7 || tr.IdleConnTimeout != time.Minute {
+		t.Errorf("TestClient - Client - unexpected settings: %v %+v", c.Timeout, tr)
+	}
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	if u, e := tr.Proxy(req); e != nil || u.String() != "http://proxy.local:3128" {
+		t.Errorf("TestClient - Proxy - expected: http://proxy.local:3128, got: %v, %v", u, e)
+	}
+
+	p, _ = gestalt.LoadStr("proxy.url = direct\n")
+	if tr, e := Transport(p, "client"); e != nil || tr.Proxy != nil {
+		t.Errorf("TestClient - Transport(direct) - expected no proxy, got: %v", e)
+	}
+	for _, spec := range []string{"proxy.url = proxy.local", "client.max_idle_conns = many", "client.timeout = soon"} {
+		p, _ := gestalt.LoadStr(spec + "\n")
+		if _, e := Client(p, "client"); e == nil {
+			t.Errorf("TestClient - Client(%q) - error expected", spec)
+		}
+	}
+}
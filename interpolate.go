@@ -0,0 +1,153 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Interpolation
+//
+// Values may reference (string) keys via ${key}, e.g.
+//
+//	db.url = postgres://${db.host}:${db.port}/app
+//
+// A literal "${" is written as "$${".
+//
+// References to undefined keys are resolved by a chain of Interpolators,
+// e.g. ${env:HOME} or ${vault:secret/db/password}.
+// ----------------------------------------------------------------------
+
+const (
+	ref_open  = "${"
+	ref_close = "}"
+	ref_esc   = '$'
+)
+
+// ErrUnresolvedRef is the error of references to undefined keys.
+var ErrUnresolvedRef = errors.New("unresolved reference")
+
+// Interpolator resolves references that are not keys, e.g. of a custom
+// scheme. Interpolators are consulted in order; the first to resolve a
+// reference provides its value.
+type Interpolator interface {
+	// Returns the value of reference ref, or ok false if not resolved by
+	// this interpolator.
+	Interpolate(ref string) (v string, ok bool, e error)
+}
+
+// InterpolatorFunc adapts a function to the Interpolator interface.
+type InterpolatorFunc func(ref string) (string, bool, error)
+
+func (f InterpolatorFunc) Interpolate(ref string) (string, bool, error) {
+	return f(ref)
+}
+
+// Returns an Interpolator resolving references of the form ${scheme:arg}
+// via fn(arg).
+func SchemeInterpolator(scheme string, fn func(arg string) (string, error)) Interpolator {
+	prefix := scheme + kv_delim
+	return InterpolatorFunc(func(ref string) (string, bool, error) {
+		if !strings.HasPrefix(ref, prefix) {
+			return "", false, nil
+		}
+		v, e := fn(ref[len(prefix):])
+		return v, e == nil, e
+	})
+}
+
+// EnvInterpolator resolves ${env:NAME} references to the value of the
+// environment variable NAME.
+var EnvInterpolator = SchemeInterpolator("env", func(name string) (string, error) {
+	v, ok := lookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w - environment variable %s is not set", ErrUnresolvedRef, name)
+	}
+	return v, nil
+})
+
+// Returns a copy of p with the ${ref} references of its values expanded.
+// References resolve to the keys of p, or else per the interpolators, in
+// order. Unset keys are copied as is.
+//
+// Returns a *KeyError if a reference is unresolved, cyclic, or to an array
+// or map key.
+func (p Properties) Interpolate(in ...Interpolator) (Properties, error) {
+	s := &OverlayStack{layers: []Properties{p}, chain: in}
+	ip := make(Properties, len(p))
+	for _, k := range sortedKeys(p) {
+		if isUnset(p[k]) {
+			ip[k] = p[k]
+			continue
+		}
+		v, e := s.interpolate(k, nil)
+		if e != nil {
+			return nil, &KeyError{k, e}
+		}
+		ip[k] = v
+	}
+	ip.adopt(p, nil, true)
+	return ip, nil
+}
+
+// expands the ${ref} references of s, per lookup.
+func expand(s string, lookup func(ref string) (string, error)) (string, error) {
+	if !strings.Contains(s, ref_open) {
+		return s, nil
+	}
+	var buf strings.Builder
+	for {
+		i := strings.Index(s, ref_open)
+		if i < 0 {
+			buf.WriteString(s)
+			return buf.String(), nil
+		}
+		if i > 0 && s[i-1] == ref_esc {
+			buf.WriteString(s[:i-1] + ref_open)
+			s = s[i+len(ref_open):]
+			continue
+		}
+		j := strings.Index(s[i:], ref_close)
+		if j < 0 {
+			return "", fmt.Errorf("unterminated reference <%s>", s[i:])
+		}
+		v, e := lookup(s[i+len(ref_open) : i+j])
+		if e != nil {
+			return "", e
+		}
+		buf.WriteString(s[:i] + v)
+		s = s[i+j+len(ref_close):]
+	}
+}
+
+// expands the references of (resolved) value v, per lookup.
+// array and map values are expanded per element.
+func expandValue(v interface{}, lookup func(ref string) (string, error)) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return expand(v, lookup)
+	case []string:
+		arrv := make([]string, len(v))
+		for i, av := range v {
+			var e error
+			if arrv[i], e = expand(av, lookup); e != nil {
+				return nil, e
+			}
+		}
+		return arrv, nil
+	case map[string]string:
+		mapv := make(map[string]string, len(v))
+		for mk, mv := range v {
+			var e error
+			if mapv[mk], e = expand(mv, lookup); e != nil {
+				return nil, e
+			}
+		}
+		return mapv, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,69 @@
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	lookup := func(ref string) (string, error) { return "<" + ref + ">", nil }
+	for s, expected := range map[string]string{
+		"plain":          "plain",
+		"${a}":           "<a>",
+		"x${a}y${b}z":    "x<a>y<b>z",
+		"$${a} and ${b}": "${a} and <b>",
+	} {
+		if v, e := expand(s, lookup); e != nil || v != expected {
+			t.Errorf("TestExpand - expand(%s) - expected: %s, got: %s, %v", s, expected, v, e)
+		}
+	}
+	if _, e := expand("${a", lookup); e == nil {
+		t.Errorf("TestExpand - expand(${a) - error expected for unterminated reference")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	os.Setenv("GESTALT_TEST_HOME", "/home/gestalt")
+	defer os.Unsetenv("GESTALT_TEST_HOME")
+
+	vault := SchemeInterpolator("vault", func(path string) (string, error) {
+		if path == "secret/db/password" {
+			return "s3cret", nil
+		}
+		return "", fmt.Errorf("no such secret <%s>", path)
+	})
+
+	p, _ := LoadStr("home = ${env:GESTALT_TEST_HOME}\ndb.password = ${vault:secret/db/password}\ndata = ${home}/data\nold = @unset\n")
+	ip, e := p.Interpolate(EnvInterpolator, vault)
+	if e != nil {
+		t.Fatalf("TestInterpolate - Interpolate - %s", e)
+	}
+	for k, expected := range map[string]string{"home": "/home/gestalt", "db.password": "s3cret", "data": "/home/gestalt/data"} {
+		if v := ip.GetString(k); v != expected {
+			t.Errorf("TestInterpolate - GetString(%s) - expected: %s, got: %s", k, expected, v)
+		}
+	}
+	if !isUnset(ip["old"]) {
+		t.Errorf("TestInterpolate - expected old to remain unset, got: %v", ip["old"])
+	}
+
+	p, _ = LoadStr("a = ${vault:secret/none}\n")
+	if _, e := p.Interpolate(vault); e == nil {
+		t.Errorf("TestInterpolate - Interpolate - error expected for unknown secret")
+	}
+	p, _ = LoadStr("a = ${dns:example.com}\n")
+	if _, e := p.Interpolate(EnvInterpolator, vault); !errors.Is(e, ErrUnresolvedRef) {
+		t.Errorf("TestInterpolate - Interpolate - expected: ErrUnresolvedRef, got: %v", e)
+	}
+
+	// first interpolator to resolve wins
+	first := InterpolatorFunc(func(ref string) (string, bool, error) { return "first", true, nil })
+	second := InterpolatorFunc(func(ref string) (string, bool, error) { return "second", true, nil })
+	s := NewOverlayStack(p)
+	s.Use(first, second)
+	if v, _ := s.ResolveString("a"); v != "first" {
+		t.Errorf("TestInterpolate - ResolveString(a) - expected: first, got: %s", v)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package keyrefs finds the gestalt keys referenced in Go source, i.e.
+// the literal keys of getter call sites, e.g.
+//
+//	host := p.GetString("db.host")
+//	port, e := gestalt.As[int](p, "db.port")
+//
+// and cross-checks them against the keys of a configuration (or schema),
+// reporting keys that are read but never defined, and keys that are defined
+// but never read.
+//
+// Call sites are matched syntactically, without type information: methods
+// by getter name, and functions by name qualified by the gestalt import.
+// Only files that import gestalt (or of package gestalt) are searched, and
+// generic names (e.g. Has) are not matched. Keys given by non-literal
+// expressions are not found.
+package keyrefs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// index of the key argument of getters, by name. All getters of
+// gestalt.Properties are listed, but for generic names such as Has (see
+// TestGetters).
+var getters = map[string]int{
+	"GetString":          0,
+	"GetStringOrDefault": 0,
+	"GetStringOrError":   0,
+	"GetStringsSplit":    0,
+	"MustGetString":      0,
+	"GetArray":           0,
+	"GetArrayOrDefault":  0,
+	"GetArrayOrError":    0,
+	"GetMap":             0,
+	"GetMapOrDefault":    0,
+	"GetMapOrError":      0,
+	"GetIndexed":         0,
+	"GetMapValue":        0,
+	"GetInt":             0,
+	"GetBool":            0,
+	"GetFloat":           0,
+	"GetPercent":         0,
+	"GetQuantity":        0,
+	"GetDuration":        0,
+	"GetDurationArray":   0,
+	"GetDurationMap":     0,
+	"GetRegexp":          0,
+	"GetGlob":            0,
+	"GetGlobArray":       0,
+	"GetPath":            0,
+	"GetBytesBase64":     0,
+	"GetBytesHex":        0,
+	"GetWeightedMap":     0,
+	"GetMediaType":       0,
+	"GetCharset":         0,
+	"GetMediaTypeMap":    0,
+	"GetHeaderMap":       0,
+	"GetHostPort":        0,
+	"GetListenAddr":      0,
+	"GetLocation":        0,
+	"GetSemver":          0,
+	"GetEnum":            0,
+}
+
+// names of gestalt.Properties methods too generic to be matched by name
+var generic = map[string]bool{
+	"Has": true,
+}
+
+// index of the key argument of (generic) functions, by name
+var funcs = map[string]int{
+	"As":      1,
+	"GetEnum": 1,
+}
+
+const import_path = "github.com/alphazero/gestalt"
+
+// Returns the name gestalt is imported as by file, "." if dot imported,
+// "" if file is of package gestalt, and ok false if gestalt is not imported.
+func importName(file *ast.File) (name string, ok bool) {
+	if file.Name.Name == "gestalt" {
+		return "", true
+	}
+	for _, spec := range file.Imports {
+		if p, e := strconv.Unquote(spec.Path.Value); e != nil || p != import_path {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name, true
+		}
+		return path.Base(import_path), true
+	}
+	return "", false
+}
+
+// Ref is a key referenced at a call site.
+type Ref struct {
+	Key string
+	Pos token.Position
+}
+
+// Returns the key references of file, sorted by position.
+func FileRefs(fset *token.FileSet, file *ast.File) (refs []Ref) {
+	pkg, ok := importName(file)
+	if !ok {
+		return nil
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fun := call.Fun
+		if ix, ok := fun.(*ast.IndexExpr); ok { // e.g. As[int]
+			fun = ix.X
+		}
+		var i int
+		switch f := fun.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := f.X.(*ast.Ident); ok && x.Name == pkg && pkg != "" {
+				i, ok = funcs[f.Sel.Name] // e.g. gestalt.As
+				if !ok {
+					return true
+				}
+			} else if i, ok = getters[f.Sel.Name]; !ok || generic[f.Sel.Name] {
+				return true
+			}
+		case *ast.Ident:
+			if pkg != "" && pkg != "." {
+				return true
+			}
+			if i, ok = funcs[f.Name]; !ok {
+				return true
+			}
+		default:
+			return true
+		}
+		if i >= len(call.Args) {
+			return true
+		}
+		lit, ok := call.Args[i].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		if key, e := strconv.Unquote(lit.Value); e == nil {
+			refs = append(refs, Ref{key, fset.Position(lit.Pos())})
+		}
+		return true
+	})
+	return refs
+}
+
+// Returns the key references of the Go files of the tree of dir, sorted
+// by position. Test files are included.
+func DirRefs(dir string) ([]Ref, error) {
+	var refs []Ref
+	fset := token.NewFileSet()
+	e := filepath.WalkDir(dir, func(path string, d fs.DirEntry, e error) error {
+		if e != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return e
+		}
+		file, e := parser.ParseFile(fset, path, nil, 0)
+		if e != nil {
+			return e
+		}
+		refs = append(refs, FileRefs(fset, file)...)
+		return nil
+	})
+	if e != nil {
+		return nil, e
+	}
+	return refs, nil
+}
+
+// Report is the result of a cross-check of references and defined keys.
+type Report struct {
+	Undefined []Ref    // references of keys that are not defined
+	Unread    []string // defined keys that are not referenced, sorted
+}
+
+// Cross-checks refs against the defined keys (e.g. gestalt.Properties#Keys).
+func Check(refs []Ref, defined []string) Report {
+	var r Report
+	isDefined := make(map[string]bool, len(defined))
+	for _, k := range defined {
+		isDefined[k] = true
+	}
+	read := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		read[ref.Key] = true
+		if !isDefined[ref.Key] {
+			r.Undefined = append(r.Undefined, ref)
+		}
+	}
+	for k := range isDefined {
+		if !read[k] {
+			r.Unread = append(r.Unread, k)
+		}
+	}
+	sort.Strings(r.Unread)
+	return r
+}
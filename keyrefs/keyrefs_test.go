@@ -0,0 +1,99 @@
+package keyrefs
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alphazero/gestalt"
+)
+
+const src = `package app
+
+import "github.com/alphazero/gestalt"
+
+func configure(p gestalt.Properties, key string) {
+	host := p.GetString("db.host")
+	port, _ := gestalt.As[int](p, "db.port")
+	level, _ := p.GetEnum("log.level", nil)
+	format, _ := gestalt.GetEnum(p, "log.format", formats)
+	hosts := p.GetArrayOrDefault("db.hosts[]", nil)
+	dynamic := p.GetString(key)
+	other := fmt.Sprint("not.a.key")
+	seen := visited.Has("not.a.key")
+	_, _ = As[int](p, "not.a.key")
+}
+`
+
+func TestFileRefs(t *testing.T) {
+	fset := token.NewFileSet()
+	file, e := parser.ParseFile(fset, "app.go", src, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+	var keys []string
+	for _, ref := range FileRefs(fset, file) {
+		keys = append(keys, ref.Key)
+	}
+	expected := []string{"db.host", "db.port", "log.level", "log.format", "db.hosts[]"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("TestFileRefs - expected: %v, got: %v", expected, keys)
+	}
+	if refs := FileRefs(fset, file); refs[0].Pos.Line != 6 {
+		t.Errorf("TestFileRefs - Pos - expected line 6, got: %s", refs[0].Pos)
+	}
+}
+
+func TestFileRefsNoImport(t *testing.T) {
+	fset := token.NewFileSet()
+	src := strings.Replace(src, `"github.com/alphazero/gestalt"`, `gestalt "example.com/other"`, 1)
+	file, e := parser.ParseFile(fset, "app.go", src, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if refs := FileRefs(fset, file); len(refs) != 0 {
+		t.Errorf("TestFileRefsNoImport - expected: no refs, got: %v", refs)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	refs := []Ref{{Key: "db.host"}, {Key: "db.port"}, {Key: "db.host"}}
+	r := Check(refs, []string{"db.host", "db.user", "app.name"})
+	if len(r.Undefined) != 1 || r.Undefined[0].Key != "db.port" {
+		t.Errorf("TestCheck - Undefined - expected: [db.port], got: %v", r.Undefined)
+	}
+	if expected := []string{"app.name", "db.user"}; !reflect.DeepEqual(r.Unread, expected) {
+		t.Errorf("TestCheck - Unread - expected: %v, got: %v", expected, r.Unread)
+	}
+}
+
+func TestDirRefs(t *testing.T) {
+	dir := t.TempDir()
+	if e := os.WriteFile(filepath.Join(dir, "app.go"), []byte(src), 0644); e != nil {
+		t.Fatal(e)
+	}
+	refs, e := DirRefs(dir)
+	if e != nil {
+		t.Fatalf("TestDirRefs - %s", e)
+	}
+	if len(refs) != 5 || refs[0].Pos.Filename != filepath.Join(dir, "app.go") {
+		t.Errorf("TestDirRefs - expected 5 refs of app.go, got: %v", refs)
+	}
+}
+
+func TestGetters(t *testing.T) {
+	typ := reflect.TypeOf(gestalt.Properties{})
+	for i := 0; i < typ.NumMethod(); i++ {
+		name := typ.Method(i).Name
+		if !strings.HasPrefix(name, "Get") && !strings.HasPrefix(name, "MustGet") && name != "Has" {
+			continue
+		}
+		if _, ok := getters[name]; !ok && !generic[name] {
+			t.Errorf("TestGetters - getter %s - expected listed", name)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// prefix of string values that reference a keyring secret,
+// e.g. keyring:smtp.example.com/mailer
+const keyring_prefix = "keyring:"
+
+// Keyring is a store of secrets, identified by service and account.
+type Keyring interface {
+	Get(service, account string) (string, error)
+}
+
+// OSKeyring is the Keyring of the OS credential store: the macOS keychain
+// (via the security command), Secret Service (via the secret-tool command),
+// or the Windows credential manager (generic credentials, with target name
+// "<service>:<account>", and blobs of UTF-16LE text, as stored by the
+// credential manager, or else of UTF-8 text).
+var OSKeyring Keyring = osKeyring{}
+
+// WithKeyring resolves string values of form `keyring:<service>/<account>`
+// to the secret of service and account in kr at load time, so that secrets
+// need not be stored in plaintext files. If kr is nil, OSKeyring is used.
+func WithKeyring(kr Keyring) LoadOption {
+	if kr == nil {
+		kr = OSKeyring
+	}
+	return func(o *loadOptions) {
+		o.keyring = kr
+	}
+}
+
+// resolves all keyring references of p
+func (p Properties) resolveKeyring(kr Keyring) error {
+	for k, v := range p {
+		if isArrayKey(k) || isMapKey(k) {
+			continue
+		}
+		s, _ := v.(string)
+		if !strings.HasPrefix(s, keyring_prefix) {
+			continue
+		}
+		ref := s[len(keyring_prefix):]
+		i := strings.LastIndex(ref, "/")
+		if i < 1 || i == len(ref)-1 {
+			return &KeyError{k, fmt.Errorf("keyring reference <%s> must be of form %s<service>/<account>", s, keyring_prefix)}
+		}
+		secret, e := kr.Get(ref[:i], ref[i+1:])
+		if e != nil {
+			return &KeyError{k, fmt.Errorf("keyring reference <%s> - %s", s, e)}
+		}
+		p[k] = secret
+		p.MarkSecret(k)
+	}
+	return nil
+}
+
+// returns the text of the blob of a Windows generic credential: UTF-16LE
+// text, as stored by the credential manager (e.g. cmdkey), or else UTF-8
+// text, as stored by some tools. Blobs of valid UTF-8 without NUL bytes
+// (which the UTF-16LE encoding of ASCII text has) are taken as UTF-8.
+func credentialText(blob []byte) string {
+	if len(blob)%2 != 0 || utf8.Valid(blob) && bytes.IndexByte(blob, 0) < 0 {
+		return string(blob)
+	}
+	units := make([]uint16, len(blob)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(blob[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
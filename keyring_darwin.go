@@ -0,0 +1,22 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !tinygo
+
+package gestalt
+
+import (
+	"os/exec"
+	"strings"
+)
+
+type osKeyring struct{}
+
+func (osKeyring) Get(service, account string) (string, error) {
+	out, e := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if e != nil {
+		return "", e
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
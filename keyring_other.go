@@ -0,0 +1,18 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows && (!unix || tinygo)
+
+package gestalt
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type osKeyring struct{}
+
+func (osKeyring) Get(service, account string) (string, error) {
+	return "", fmt.Errorf("no OS keyring on %s", runtime.GOOS)
+}
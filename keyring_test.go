@@ -0,0 +1,63 @@
+package gestalt
+
+import (
+	"fmt"
+	"testing"
+)
+
+type fakeKeyring map[string]string
+
+func (kr fakeKeyring) Get(service, account string) (string, error) {
+	if secret, ok := kr[service+"/"+account]; ok {
+		return secret, nil
+	}
+	return "", fmt.Errorf("no such secret")
+}
+
+func TestWithKeyring(t *testing.T) {
+	kr := fakeKeyring{"smtp.example.com/mailer": "s3cret"}
+	spec := `
+smtp.user = mailer
+smtp.password = keyring:smtp.example.com/mailer
+`
+	for _, opts := range [][]LoadOption{{WithKeyring(kr)}, {WithKeyring(kr), Lazy()}} {
+		p, e := LoadStr(spec, opts...)
+		if e != nil {
+			t.Fatalf("TestWithKeyring - LoadStr - %s", e)
+		}
+		if v := p.GetString("smtp.password"); v != "s3cret" {
+			t.Errorf("TestWithKeyring - GetString(smtp.password) - expected: s3cret, got: %s", v)
+		}
+		if v := p.GetString("smtp.user"); v != "mailer" {
+			t.Errorf("TestWithKeyring - GetString(smtp.user) - expected: mailer, got: %s", v)
+		}
+	}
+
+	if _, e := LoadStr("pw = keyring:nosuch/account", WithKeyring(kr)); e == nil {
+		t.Errorf("TestWithKeyring - LoadStr - error expected for missing secret")
+	}
+	if _, e := LoadStr("pw = keyring:malformed", WithKeyring(kr)); e == nil {
+		t.Errorf("TestWithKeyring - LoadStr - error expected for malformed reference")
+	}
+	if p, _ := LoadStr("pw = keyring:a/b"); p.GetString("pw") != "keyring:a/b" {
+		t.Errorf("TestWithKeyring - LoadStr - references must not be resolved without WithKeyring")
+	}
+}
+
+func TestCredentialText(t *testing.T) {
+	for _, c := range []struct {
+		blob     []byte
+		expected string
+	}{
+		{[]byte("s\x00e\x00c\x00r\x00e\x00t\x00"), "secret"},
+		{[]byte("p\x00\xe4\x00s\x00s\x00"), "päss"},
+		{[]byte("\x3d\xd8\x11\xdc"), "\U0001f411"},
+		{[]byte("secret"), "secret"},
+		{[]byte("päss"), "päss"},
+		{[]byte{}, ""},
+	} {
+		if v := credentialText(c.blob); v != c.expected {
+			t.Errorf("TestCredentialText - credentialText(%q) - expected: %q, got: %q", c.blob, c.expected, v)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix && !darwin && !tinygo
+
+package gestalt
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+type osKeyring struct{}
+
+func (osKeyring) Get(service, account string) (string, error) {
+	out, e := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output()
+	if e != nil {
+		return "", e
+	}
+	if len(out) == 0 {
+		return "", fmt.Errorf("no secret for service <%s> account <%s>", service, account)
+	}
+	return string(out), nil
+}
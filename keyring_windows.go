@@ -0,0 +1,50 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modadvapi32   = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW = modadvapi32.NewProc("CredReadW")
+	procCredFree  = modadvapi32.NewProc("CredFree")
+)
+
+const cred_type_generic = 1
+
+// CREDENTIALW
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+type osKeyring struct{}
+
+func (osKeyring) Get(service, account string) (string, error) {
+	target, e := syscall.UTF16PtrFromString(service + ":" + account)
+	if e != nil {
+		return "", e
+	}
+	var cred *credential
+	r, _, e := procCredReadW.Call(uintptr(unsafe.Pointer(target)), cred_type_generic, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		return "", e
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	return credentialText(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"container/list"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// typed conversion tags
+const (
+	typed_int byte = iota
+	typed_bool
+	typed_duration
+	typed_regexp
+	typed_glob
+	typed_mediatype
+	typed_charset
+	typed_hostport
+	typed_location
+	typed_semver
+)
+
+// maximum number of compiled regexps and globs retained, each
+const pattern_cache_size = 256
+
+// compiled regexps and globs, by pattern. The caches are bounded, so that
+// e.g. services loading configurations of third parties with many distinct
+// patterns do not retain them all.
+var (
+	regexps = newLRU(pattern_cache_size)
+	globs   = newLRU(pattern_cache_size)
+)
+
+// lru is a (concurrency safe) least recently used cache of bounded size.
+type lru struct {
+	mu      sync.Mutex
+	max     int
+	entries *list.List // of *lruEntry, most recently used first
+	index   map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+func newLRU(max int) *lru {
+	return &lru{max: max, entries: list.New(), index: make(map[string]*list.Element)}
+}
+
+// returns the value of key, if cached
+func (c *lru) Load(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.index[key]; ok {
+		c.entries.MoveToFront(el)
+		return el.Value.(*lruEntry).value, true
+	}
+	return nil, false
+}
+
+// caches value of key, evicting the least recently used entry if full
+func (c *lru) Store(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.index[key]; ok {
+		el.Value.(*lruEntry).value = value
+		c.entries.MoveToFront(el)
+		return
+	}
+	c.index[key] = c.entries.PushFront(&lruEntry{key, value})
+	if c.entries.Len() > c.max {
+		el := c.entries.Back()
+		c.entries.Remove(el)
+		delete(c.index, el.Value.(*lruEntry).key)
+	}
+}
+
+// returns the number of cached entries
+func (c *lru) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// typed conversions of string values, by tag
+var converters = [...]func(string) (interface{}, error){
+	typed_int: func(s string) (interface{}, error) {
+		return strconv.Atoi(s)
+	},
+	typed_bool: func(s string) (interface{}, error) {
+		return strconv.ParseBool(s)
+	},
+	typed_duration: func(s string) (interface{}, error) {
+		return time.ParseDuration(s)
+	},
+	typed_regexp: func(s string) (interface{}, error) {
+		if re, ok := regexps.Load(s); ok {
+			return re, nil
+		}
+		re, e := regexp.Compile(s)
+		if e != nil {
+			return nil, e
+		}
+		regexps.Store(s, re)
+		return re, nil
+	},
+	typed_glob: func(s string) (interface{}, error) {
+		if g, ok := globs.Load(s); ok {
+			return g, nil
+		}
+		g, e := CompileGlob(s)
+		if e != nil {
+			return nil, e
+		}
+		globs.Store(s, g)
+		return g, nil
+	},
+	typed_mediatype: func(s string) (interface{}, error) {
+		return parseMediaType(s)
+	},
+	typed_charset: func(s string) (interface{}, error) {
+		return parseCharset(s)
+	},
+	typed_hostport: func(s string) (interface{}, error) {
+		if _, e := parseHostPort(s, false); e != nil {
+			return nil, e
+		}
+		return s, nil
+	},
+	typed_location: func(s string) (interface{}, error) {
+		return loadLocation(s)
+	},
+	typed_semver: func(s string) (interface{}, error) {
+		return ParseSemver(s)
+	},
+}
+
+// returns the conversion of the string value s of key, memoized per key if
+// p memoizes conversions (see Lazy and PreResolve).
+func (p Properties) convert(key string, tag byte, s string) (interface{}, error) {
+	if sd := sideOf(p); sd != nil && sd.memoize {
+		return sd.convert(key, tag, s)
+	}
+	return converters[tag](s)
+}
+
+// memoizes the conversions of p from then on. Returns the side table of p.
+func (p Properties) memoize() *side {
+	s := p.side()
+	s.mu.Lock()
+	s.memoize = true
+	s.mu.Unlock()
+	return s
+}
+
+// returns the memoized conversion of the value src of key. Memos of prior
+// values of key (e.g. of windowed keys, or keys set directly) are replaced.
+func (s *side) convert(key string, tag byte, src string) (interface{}, error) {
+	s.mu.RLock()
+	if m := s.memos[key]; m != nil && m.src == src {
+		if tv, ok := m.conv[tag]; ok {
+			s.mu.RUnlock()
+			return tv.v, tv.e
+		}
+	}
+	s.mu.RUnlock()
+
+	v, e := converters[tag](src)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.memos == nil {
+		s.memos = make(map[string]*memo)
+	}
+	m := s.memos[key]
+	if m == nil || m.src != src {
+		m = &memo{src: src, conv: make(map[byte]typedValue)}
+		s.memos[key] = m
+	}
+	m.conv[tag] = typedValue{v, e}
+	return v, e
+}
+
+// PreResolve warms the memoized conversions of the specified keys, or of
+// all keys if none are specified, so that subsequent reads in hot paths
+// neither parse nor allocate: string values are converted to all supported
+// types (int, bool, duration) where possible, and conversions of p are
+// memoized from then on (see Lazy). Values of p are not modified.
+func (p Properties) PreResolve(keys ...string) {
+	if len(keys) == 0 {
+		keys = append(p.Keys(), p.DefaultKeys()...)
+	}
+	s := p.memoize()
+	for _, k := range keys {
+		k = plainKey(k)
+		if v, ok := p.get(k).(string); ok && !isArrayKey(k) && !isMapKey(k) {
+			for _, tag := range []byte{typed_int, typed_bool, typed_duration} {
+				s.convert(k, tag, v)
+			}
+		}
+	}
+}
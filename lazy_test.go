@@ -0,0 +1,120 @@
+package gestalt
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLazy(t *testing.T) {
+	spec := `
+port = 8080
+timeout = 5s
+hosts[] = a, b, c
+table[:] = a:1, b:2
+`
+	prop, e := LoadStr(spec, Lazy())
+	if e != nil {
+		t.Errorf("TestLazy - LoadStr - %s", e)
+	}
+	if _, ok := prop["hosts[]"].([]string); !ok {
+		t.Errorf("TestLazy - LoadStr - expected plain value for hosts[], got: %T", prop["hosts[]"])
+	}
+
+	if v := prop.GetArray("hosts[]"); len(v) != 3 || v[2] != "c" {
+		t.Errorf("TestLazy - GetArray(hosts[]) - expected: [a b c], got: %s", v)
+	}
+	if v := prop.GetMap("table[:]"); v["b"] != "2" {
+		t.Errorf("TestLazy - GetMap(table[:]) - expected: map[a:1 b:2], got: %s", v)
+	}
+
+	if v, e := prop.GetInt("port"); e != nil || v != 8080 {
+		t.Errorf("TestLazy - GetInt(port) - expected: 8080, got: %d (%v)", v, e)
+	}
+	if _, ok := sideOf(prop).memos["port"].conv[typed_int]; !ok {
+		t.Errorf("TestLazy - GetInt(port) - expected memoized conversion")
+	}
+	if v, e := prop.GetDuration("timeout"); e != nil || v != 5*time.Second {
+		t.Errorf("TestLazy - GetDuration(timeout) - expected: 5s, got: %s (%v)", v, e)
+	}
+	if _, e := prop.GetInt("timeout"); e == nil {
+		t.Errorf("TestLazy - GetInt(timeout) - error expected")
+	}
+
+	prop["port"] = "9090"
+	if v, e := prop.GetInt("port"); e != nil || v != 9090 {
+		t.Errorf("TestLazy - GetInt(port) - expected memo of changed value replaced, got: %d (%v)", v, e)
+	}
+}
+
+func TestPreResolve(t *testing.T) {
+	prop, e := LoadStr(`
+port = 8080
+timeout = 5s
+hosts[] = a, b, c
+`)
+	if e != nil {
+		t.Errorf("TestPreResolve - LoadStr - %s", e)
+	}
+	before := prop.Clone()
+	prop.PreResolve()
+	if !reflect.DeepEqual(map[string]interface{}(prop), map[string]interface{}(before)) {
+		t.Errorf("TestPreResolve - PreResolve - expected values unchanged, got: %#v", prop)
+	}
+
+	if v, e := prop.GetInt("port"); e != nil || v != 8080 {
+		t.Errorf("TestPreResolve - GetInt(port) - expected: 8080, got: %d (%v)", v, e)
+	}
+	if v := prop.GetArray("hosts[]"); len(v) != 3 {
+		t.Errorf("TestPreResolve - GetArray(hosts[]) - expected: [a b c], got: %s", v)
+	}
+
+	reads := map[string]func(){
+		"GetString":   func() { prop.GetString("port") },
+		"GetArray":    func() { prop.GetArray("hosts[]") },
+		"GetInt":      func() { prop.GetInt("port") },
+		"GetDuration": func() { prop.GetDuration("timeout") },
+	}
+	for name, read := range reads {
+		if n := testing.AllocsPerRun(100, read); n != 0 {
+			t.Errorf("TestPreResolve - %s - expected: 0 allocs, got: %f", name, n)
+		}
+	}
+}
+
+func BenchmarkGetIntPreResolved(b *testing.B) {
+	prop, _ := LoadStr("foo = 8080")
+	prop.PreResolve("foo")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		prop.GetInt("foo")
+	}
+}
+
+func TestPatternCache(t *testing.T) {
+	c := newLRU(2)
+	c.Store("a", 1)
+	c.Store("b", 2)
+	c.Load("a")
+	c.Store("c", 3)
+	if _, ok := c.Load("b"); ok {
+		t.Errorf("TestPatternCache - Load(b) - expected: evicted")
+	}
+	if v, ok := c.Load("a"); !ok || v != 1 || c.Len() != 2 {
+		t.Errorf("TestPatternCache - Load(a) - expected: 1 of 2 entries, got: %v of %d", v, c.Len())
+	}
+
+	for i := 0; i < pattern_cache_size+10; i++ {
+		p := Properties{"re": fmt.Sprintf("^x%d$", i), "glob": fmt.Sprintf("x%d/*", i)}
+		if _, e := p.GetRegexp("re"); e != nil {
+			t.Fatalf("TestPatternCache - GetRegexp - %s", e)
+		}
+		if _, e := p.GetGlob("glob"); e != nil {
+			t.Fatalf("TestPatternCache - GetGlob - %s", e)
+		}
+	}
+	if regexps.Len() > pattern_cache_size || globs.Len() > pattern_cache_size {
+		t.Errorf("TestPatternCache - expected at most %d patterns, got: %d regexps, %d globs", pattern_cache_size, regexps.Len(), globs.Len())
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrContentTooLarge is the error of content exceeding the maximum size of
+// MaxContentSize.
+var ErrContentTooLarge = errors.New("content exceeds maximum size")
+
+// ErrValueTooLong is the error of values exceeding the maximum length of
+// MaxValueLength.
+var ErrValueTooLong = errors.New("value exceeds maximum length")
+
+// LengthPolicy is the handling of values exceeding the maximum length of
+// MaxValueLength.
+type LengthPolicy int
+
+const (
+	LengthError    LengthPolicy = iota // the load fails with ErrValueTooLong
+	LengthTruncate                     // the value is truncated, and a warning logged
+	LengthAllow                        // the value is loaded as is, and a warning logged
+)
+
+var length_policy_names = [...]string{
+	LengthError:    "error",
+	LengthTruncate: "truncate",
+	LengthAllow:    "allow",
+}
+
+func (lp LengthPolicy) String() string {
+	if lp < 0 || int(lp) >= len(length_policy_names) {
+		return fmt.Sprintf("LengthPolicy(%d)", int(lp))
+	}
+	return length_policy_names[lp]
+}
+
+// MaxValueLength limits the length, in bytes, of the value representations
+// of loaded keys (e.g. of pathological multi-MB continuation chains) to n,
+// per policy, protecting services loading files supplied by third parties.
+// Truncated values are cut at a char boundary (and trailing whitespace
+// trimmed), and truncated array and map values may end with a partial
+// element. Warnings are logged (see WithLogger) at level warn. n <= 0 is
+// no limit, which is the default.
+func MaxValueLength(n int, policy LengthPolicy) LoadOption {
+	return func(o *loadOptions) {
+		o.maxValueLen = n
+		o.lengthPolicy = policy
+	}
+}
+
+// returns the value representation vrep of key k, per the maximum length
+// of values
+func (o *loadOptions) limitValue(k, vrep string) (string, error) {
+	if o.maxValueLen <= 0 || len(vrep) <= o.maxValueLen {
+		return vrep, nil
+	}
+	switch o.lengthPolicy {
+	case LengthTruncate:
+		o.logger.Warn("gestalt: value truncated", "key", k, "length", len(vrep), "max", o.maxValueLen)
+		n := o.maxValueLen
+		for n > 0 && !utf8.RuneStart(vrep[n]) {
+			n--
+		}
+		return strings.TrimRight(vrep[:n], ws), nil
+	case LengthAllow:
+		o.logger.Warn("gestalt: value exceeds maximum length", "key", k, "length", len(vrep), "max", o.maxValueLen)
+		return vrep, nil
+	}
+	return "", &KeyError{k, fmt.Errorf("%w - %d bytes (maximum is %d)", ErrValueTooLong, len(vrep), o.maxValueLen)}
+}
+
+// MaxContentSize limits the size, in bytes, of loaded content to n, e.g. of
+// misconfigured remote endpoints (see BlobSource) returning huge or garbage
+// payloads. Larger content fails to load with ErrContentTooLarge, and is
+// not parsed. Files, readers (see Decode), and objects of BlobGetters that
+// are LimitedBlobGetters are read up to the limit, i.e. larger content is
+// never read into memory. n <= 0 is no limit, which is the default.
+func MaxContentSize(n int) LoadOption {
+	return func(o *loadOptions) {
+		o.maxSize = n
+	}
+}
+
+// returns error if s exceeds the maximum content size
+func (o *loadOptions) checkSize(s string) error {
+	if o.maxSize > 0 && len(s) > o.maxSize {
+		return fmt.Errorf("%w - %d bytes (maximum: %d)", ErrContentTooLarge, len(s), o.maxSize)
+	}
+	return nil
+}
+
+// reads r up to the maximum content size, returning ErrContentTooLarge
+// (without reading the remainder) if r has more
+func (o *loadOptions) readLimited(r io.Reader) ([]byte, error) {
+	if o.maxSize <= 0 {
+		return io.ReadAll(r)
+	}
+	b, e := io.ReadAll(io.LimitReader(r, int64(o.maxSize)+1))
+	if e != nil {
+		return nil, e
+	}
+	if len(b) > o.maxSize {
+		return nil, fmt.Errorf("%w - more than %d bytes", ErrContentTooLarge, o.maxSize)
+	}
+	return b, nil
+}
+
+// reads the named file of o's FileSystem, up to the maximum content size if
+// the FileSystem can open files (see fs.FS)
+func (o *loadOptions) readFile(name string) ([]byte, error) {
+	opener, ok := o.fsys.(interface{ Open(string) (fs.File, error) })
+	if o.maxSize <= 0 || !ok {
+		return o.fsys.ReadFile(name)
+	}
+	f, e := opener.Open(name)
+	if e != nil {
+		return nil, e
+	}
+	defer f.Close()
+	return o.readLimited(f)
+}
@@ -0,0 +1,121 @@
+package gestalt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMaxValueLength(t *testing.T) {
+	long := "a = " + strings.Repeat("x", 6) + "\\\n" + strings.Repeat("é", 4) + "\nb[] = 1, 2, 3\nc = ok"
+
+	if p, e := LoadStr(long); e != nil || len(p.GetString("a")) != 14 {
+		t.Errorf("TestMaxValueLength - no limit - got: %v, %v", p, e)
+	}
+
+	_, e := LoadStr(long, MaxValueLength(8, LengthError))
+	var ke *KeyError
+	if !errors.Is(e, ErrValueTooLong) || !errors.As(e, &ke) || ke.Key != "a" {
+		t.Errorf("TestMaxValueLength - LengthError - expected: KeyError of a wrapping ErrValueTooLong, got: %v", e)
+	}
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, nil))
+	p, e := LoadStr(long, MaxValueLength(7, LengthTruncate), WithLogger(l))
+	if e != nil {
+		t.Fatalf("TestMaxValueLength - LengthTruncate - %s", e)
+	}
+	// truncated at the char boundary of the first é (bytes 6-7)
+	if v := p.GetString("a"); v != "xxxxxx" {
+		t.Errorf("TestMaxValueLength - LengthTruncate - GetString(a) - expected: xxxxxx, got: %q", v)
+	}
+	if v := p.GetArray("b[]"); len(v) != 3 {
+		t.Errorf("TestMaxValueLength - LengthTruncate - GetArray(b[]) - expected: [1 2 3], got: %v", v)
+	}
+	if out := buf.String(); !strings.Contains(out, `level=WARN msg="gestalt: value truncated" key=a length=14 max=7`) {
+		t.Errorf("TestMaxValueLength - LengthTruncate - expected warning, got: %s", out)
+	}
+
+	buf.Reset()
+	p, e = LoadStr(long, MaxValueLength(8, LengthAllow), WithLogger(l))
+	if e != nil || len(p.GetString("a")) != 14 || !strings.Contains(buf.String(), "level=WARN") {
+		t.Errorf("TestMaxValueLength - LengthAllow - got: %v, %v, %s", p, e, buf.String())
+	}
+
+	if s := LengthTruncate.String(); s != "truncate" {
+		t.Errorf("TestMaxValueLength - LengthTruncate.String() - expected: truncate, got: %s", s)
+	}
+}
+
+func TestMaxContentSize(t *testing.T) {
+	spec := "a = 1\nb = 2\n"
+	if _, e := LoadStr(spec, MaxContentSize(len(spec))); e != nil {
+		t.Errorf("TestMaxContentSize - LoadStr - unexpected error: %s", e)
+	}
+	if _, e := LoadStr(spec, MaxContentSize(len(spec)-1)); !errors.Is(e, ErrContentTooLarge) {
+		t.Errorf("TestMaxContentSize - LoadStr - expected: ErrContentTooLarge, got: %v", e)
+	}
+
+	blobs := &fakeBlobs{objects: map[string]string{"configs/huge.conf": strings.Repeat("k = v\n", 1000)}}
+	RegisterBlobGetter("mem", blobs)
+	if _, e := LoadBlob(context.Background(), "mem://configs/huge.conf", MaxContentSize(1024)); !errors.Is(e, ErrContentTooLarge) {
+		t.Errorf("TestMaxContentSize - LoadBlob - expected: ErrContentTooLarge, got: %v", e)
+	}
+
+	limited := &limitedBlobs{fakeBlobs: blobs}
+	RegisterBlobGetter("lmem", limited)
+	if _, e := LoadBlob(context.Background(), "lmem://configs/huge.conf", MaxContentSize(1024)); !errors.Is(e, ErrContentTooLarge) {
+		t.Errorf("TestMaxContentSize - LoadBlob - expected: ErrContentTooLarge, got: %v", e)
+	}
+	if limited.limit != 1025 {
+		t.Errorf("TestMaxContentSize - GetBlobLimit - expected: limit 1025, got: %d", limited.limit)
+	}
+
+	fsys := FS(fstest.MapFS{"huge.conf": {Data: []byte(strings.Repeat("k = v\n", 1000))}})
+	if _, e := Load("huge.conf", WithFileSystem(fsys), MaxContentSize(1024)); !errors.Is(e, ErrContentTooLarge) {
+		t.Errorf("TestMaxContentSize - Load - expected: ErrContentTooLarge, got: %v", e)
+	}
+	if p, e := Load("huge.conf", WithFileSystem(fsys), MaxContentSize(6000)); e != nil || p.GetString("k") != "v" {
+		t.Errorf("TestMaxContentSize - Load - expected: k=v, got: %v (%v)", p, e)
+	}
+
+	r := &countingReader{r: strings.NewReader(strings.Repeat("k = v\n", 1000))}
+	if _, e := Decode(r, FormatGestalt, MaxContentSize(1024)); !errors.Is(e, ErrContentTooLarge) {
+		t.Errorf("TestMaxContentSize - Decode - expected: ErrContentTooLarge, got: %v", e)
+	}
+	if r.n > 1025 {
+		t.Errorf("TestMaxContentSize - Decode - expected: at most 1025 bytes read, got: %d", r.n)
+	}
+}
+
+// a LimitedBlobGetter recording the limit of its reads
+type limitedBlobs struct {
+	*fakeBlobs
+	limit int64
+}
+
+func (b *limitedBlobs) GetBlobLimit(ctx context.Context, bucket, key, etag string, limit int64) ([]byte, string, error) {
+	b.limit = limit
+	content, newEtag, e := b.GetBlob(ctx, bucket, key, etag)
+	if int64(len(content)) > limit {
+		content = content[:limit]
+	}
+	return content, newEtag, e
+}
+
+// a reader counting the bytes read
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, e := c.r.Read(b)
+	c.n += n
+	return n, e
+}
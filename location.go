@@ -0,0 +1,36 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"time"
+)
+
+// Location value property, e.g. `report.tz = Europe/Berlin` - returns error
+// if no such key, or value is not a known time zone. See time.LoadLocation
+// for accepted values ("UTC", "Local", and IANA time zone names).
+func (p Properties) GetLocation(key string) (*time.Location, error) {
+	v, e := p.typed(key, typed_location)
+	if e != nil {
+		return nil, e
+	}
+	return v.(*time.Location), nil
+}
+
+// returns the location of name, with the common causes of failure
+func loadLocation(name string) (*time.Location, error) {
+	if name == empty {
+		// LoadLocation("") is UTC
+		return nil, fmt.Errorf("empty time zone name - use UTC or Local")
+	}
+	loc, e := time.LoadLocation(name)
+	if e != nil {
+		return nil, fmt.Errorf("%s - check the name (e.g. Europe/Berlin, which is case sensitive) "+
+			"and that the time zone database is installed (e.g. the tzdata package), "+
+			"or embedded with import _ \"time/tzdata\", or set with $ZONEINFO", e)
+	}
+	return loc, nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetLocation(t *testing.T) {
+	p := Properties{"utc": "UTC", "local": "Local", "bad": "Mars/Olympus", "empty": ""}
+	if loc, e := p.GetLocation("utc"); e != nil || loc != time.UTC {
+		t.Errorf("TestGetLocation - GetLocation(utc) - expected: UTC, got: %v, %v", loc, e)
+	}
+	if loc, e := p.GetLocation("local"); e != nil || loc != time.Local {
+		t.Errorf("TestGetLocation - GetLocation(local) - expected: Local, got: %v, %v", loc, e)
+	}
+	if _, e := p.GetLocation("bad"); e == nil || !strings.Contains(e.Error(), "tzdata") {
+		t.Errorf("TestGetLocation - GetLocation(bad) - expected error with causes, got: %v", e)
+	}
+	for _, key := range []string{"empty", "nope"} {
+		if _, e := p.GetLocation(key); e == nil {
+			t.Errorf("TestGetLocation - GetLocation(%s) - error expected", key)
+		}
+	}
+}
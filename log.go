@@ -0,0 +1,40 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// logger of the package. Logging is disabled by default.
+var logger atomic.Pointer[slog.Logger]
+
+func init() {
+	SetLogger(nil)
+}
+
+// SetLogger sets the logger of library events (e.g. file loaded, duplicate
+// key overwritten, watch fired, validation failed), logged at level debug.
+// nil l disables logging, which is the default.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = slog.New(slog.DiscardHandler)
+	}
+	logger.Store(l)
+}
+
+// WithLogger sets the logger of the events of a load, overriding the
+// logger of the package (see SetLogger).
+func WithLogger(l *slog.Logger) LoadOption {
+	return func(o *loadOptions) {
+		o.logger = l
+	}
+}
+
+// returns the logger of the package
+func log() *slog.Logger {
+	return logger.Load()
+}
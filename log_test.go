@@ -0,0 +1,58 @@
+package gestalt
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	fsys := FS(fstest.MapFS{
+		"app.conf":    {Data: []byte("foo = bar\nfoo = baz\n")},
+		"d/10-a.conf": {Data: []byte("x = 1\n")},
+		"d/20-b.conf": {Data: []byte("x = 2\n")},
+	})
+	if _, e := Load("app.conf", WithFileSystem(fsys), WithLogger(l)); e != nil {
+		t.Fatalf("TestWithLogger - Load - %s", e)
+	}
+	if _, e := LoadDir(context.Background(), "d", WithFileSystem(fsys), WithLogger(l)); e != nil {
+		t.Fatalf("TestWithLogger - LoadDir - %s", e)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		`msg="gestalt: duplicate key overwritten" key=foo`,
+		`msg="gestalt: file loaded" file=app.conf keys=1`,
+		`msg="gestalt: duplicate key overwritten" file=d/20-b.conf key=x`,
+		`msg="gestalt: keys merged" file=d/20-b.conf keys=1`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("TestWithLogger - expected event: %s, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	if _, e := Load("test/test.conf"); e != nil {
+		t.Fatalf("TestSetLogger - Load - %s", e)
+	}
+	if !strings.Contains(buf.String(), "file=test/test.conf") {
+		t.Errorf("TestSetLogger - expected file loaded event, got: %s", buf.String())
+	}
+
+	SetLogger(nil)
+	buf.Reset()
+	if _, e := Load("test/test.conf"); e != nil || buf.Len() != 0 {
+		t.Errorf("TestSetLogger - SetLogger(nil) - expected no events, got: %s", buf.String())
+	}
+}
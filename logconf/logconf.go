@@ -0,0 +1,150 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package logconf configures slog (and standard log) loggers from a
+// conventional group of gestalt properties:
+//
+//	log.level  = info     # debug, info, warn, or error
+//	log.format = json     # text or json
+//	log.output = stderr   # stderr, stdout, or a file path (appended)
+//
+// All keys are optional, and default to the values above (text format).
+// Output files are opened by New, and closed by Config.Close.
+// The level of a Config can be changed at runtime, e.g. by watching its
+// source (see Config.OnChange).
+package logconf
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/alphazero/gestalt"
+)
+
+// conventional keys, relative to the group prefix
+const (
+	KeyLevel  = "level"
+	KeyFormat = "format"
+	KeyOutput = "output"
+)
+
+// log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Levels maps level values to slog levels.
+var Levels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+var formats = map[string]string{FormatText: FormatText, FormatJSON: FormatJSON}
+
+// Config is the logger configuration of a key group.
+type Config struct {
+	Prefix string
+	Level  *slog.LevelVar // dynamic level of loggers of the config
+	Format string
+	Output io.Writer
+
+	file *os.File // output file, if any
+}
+
+// Returns the Config of the key group of prefix (e.g. "log") of p.
+// Returns error if a value is not valid, or the output file can not
+// be opened.
+func New(p gestalt.Properties, prefix string) (*Config, error) {
+	c := &Config{Prefix: prefix, Level: new(slog.LevelVar), Format: FormatText, Output: os.Stderr}
+
+	level, e := c.level(p)
+	if e != nil {
+		return nil, e
+	}
+	c.Level.Set(level)
+
+	if p.GetString(c.key(KeyFormat)) != "" {
+		if c.Format, e = gestalt.GetEnum(p, c.key(KeyFormat), formats); e != nil {
+			return nil, e
+		}
+	}
+
+	switch output := p.GetString(c.key(KeyOutput)); output {
+	case "", "stderr":
+	case "stdout":
+		c.Output = os.Stdout
+	default:
+		path, e := p.GetPath(c.key(KeyOutput))
+		if e != nil {
+			return nil, e
+		}
+		f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if e != nil {
+			return nil, fmt.Errorf("logconf - key <%s> - %s", c.key(KeyOutput), e)
+		}
+		c.Output, c.file = f, f
+	}
+	return c, nil
+}
+
+// Closes the output file of the config, if any. Loggers of the config
+// must not be used after Close.
+func (c *Config) Close() error {
+	if c.file == nil {
+		return nil
+	}
+	return c.file.Close()
+}
+
+func (c *Config) key(k string) string {
+	return c.Prefix + "." + k
+}
+
+// returns the level of p, or info if not specified
+func (c *Config) level(p gestalt.Properties) (slog.Level, error) {
+	if p.GetString(c.key(KeyLevel)) == "" {
+		return slog.LevelInfo, nil
+	}
+	return gestalt.GetEnum(p, c.key(KeyLevel), Levels)
+}
+
+// Returns a new slog handler of the config.
+func (c *Config) Handler() slog.Handler {
+	opts := &slog.HandlerOptions{Level: c.Level}
+	if c.Format == FormatJSON {
+		return slog.NewJSONHandler(c.Output, opts)
+	}
+	return slog.NewTextHandler(c.Output, opts)
+}
+
+// Returns a new slog logger of the config.
+func (c *Config) Logger() *slog.Logger {
+	return slog.New(c.Handler())
+}
+
+// Returns a new standard logger of the config, logging at level info.
+func (c *Config) StdLogger() *log.Logger {
+	return slog.NewLogLogger(c.Handler(), slog.LevelInfo)
+}
+
+// OnChange updates the level of the config per p. It is a gestalt.ChangeFunc,
+// e.g.
+//
+//	w, e := gestalt.Watch(ctx, src, time.Minute, conf.OnChange)
+//
+// Reload errors and invalid levels are ignored, and the level is retained.
+func (c *Config) OnChange(p gestalt.Properties, e error) {
+	if e != nil {
+		return
+	}
+	if level, e := c.level(p); e == nil {
+		c.Level.Set(level)
+	}
+}
@@ -0,0 +1,75 @@
+package logconf
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alphazero/gestalt"
+)
+
+func TestNew(t *testing.T) {
+	p, _ := gestalt.LoadStr("log.level = warn\nlog.format = json\n")
+	c, e := New(p, "log")
+	if e != nil {
+		t.Fatalf("TestNew - New - %s", e)
+	}
+	if c.Level.Level() != slog.LevelWarn || c.Format != FormatJSON || c.Output != os.Stderr {
+		t.Errorf("TestNew - New - expected: warn json stderr, got: %s %s %v", c.Level, c.Format, c.Output)
+	}
+
+	var buf bytes.Buffer
+	c.Output = &buf
+	logger := c.Logger()
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if out := buf.String(); strings.Contains(out, "hidden") || !strings.Contains(out, `"msg":"shown"`) {
+		t.Errorf("TestNew - Logger - unexpected output: %s", out)
+	}
+
+	// runtime level change
+	debug, _ := gestalt.LoadStr("log.level = debug\n")
+	c.OnChange(debug, nil)
+	if c.Level.Level() != slog.LevelDebug {
+		t.Errorf("TestNew - OnChange - expected: debug, got: %s", c.Level)
+	}
+	bad, _ := gestalt.LoadStr("log.level = verbose\n")
+	c.OnChange(bad, nil)
+	if e := c.Close(); e != nil {
+		t.Errorf("TestNew - Close - expected no-op for stderr, got: %s", e)
+	}
+	c.OnChange(nil, errors.New("reload failed"))
+	if c.Level.Level() != slog.LevelDebug {
+		t.Errorf("TestNew - OnChange - expected level to be retained, got: %s", c.Level)
+	}
+
+	for _, spec := range []string{"log.level = verbose", "log.format = xml"} {
+		p, _ := gestalt.LoadStr(spec + "\n")
+		if _, e := New(p, "log"); e == nil || !strings.Contains(e.Error(), "allowed values are") {
+			t.Errorf("TestNew - New(%q) - expected allowed values error, got: %v", spec, e)
+		}
+	}
+}
+
+func TestNewFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	p, _ := gestalt.LoadStr("log.output = " + path + "\n")
+	c, e := New(p, "log")
+	if e != nil {
+		t.Fatalf("TestNewFileOutput - New - %s", e)
+	}
+	c.StdLogger().Print("hello")
+	if e := c.Close(); e != nil {
+		t.Errorf("TestNewFileOutput - Close - %s", e)
+	}
+	if _, e := c.Output.Write([]byte("x")); e == nil {
+		t.Errorf("TestNewFileOutput - Close - expected output file closed")
+	}
+	if b, _ := os.ReadFile(path); !strings.Contains(string(b), "msg=hello") {
+		t.Errorf("TestNewFileOutput - expected: msg=hello, got: %s", b)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeOption is an option of the merge of array values of Inherit and
+// SafeProperties.Merge.
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	ordered bool
+	unique  bool
+}
+
+func newMergeOptions(opts []MergeOption) *mergeOptions {
+	o := &mergeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// OrderedArrays merges array values predictably: the elements of the parent
+// (or existing) value, in order, followed by the elements only of the child
+// (or merged) value, in order. If unique, duplicate elements are dropped,
+// keeping first occurrences, e.g. of parent [a, b, a] and child [c, b, c]:
+//
+//	[a, b, a, c, c]     OrderedArrays(false)
+//	[a, b, c]           OrderedArrays(true)
+func OrderedArrays(unique bool) MergeOption {
+	return func(o *mergeOptions) {
+		o.ordered = true
+		o.unique = unique
+	}
+}
+
+// returns the ordered merge of array values parent and child.
+func mergeArrays(parent, child []string, unique bool) []string {
+	inParent := make(map[string]bool, len(parent))
+	for _, av := range parent {
+		inParent[av] = true
+	}
+	merged := make([]string, 0, len(parent)+len(child))
+	seen := make(map[string]bool, len(parent)+len(child))
+	add := func(av string) {
+		if unique && seen[av] {
+			return
+		}
+		seen[av] = true
+		merged = append(merged, av)
+	}
+	for _, av := range parent {
+		add(av)
+	}
+	for _, av := range child {
+		if !inParent[av] {
+			add(av)
+		}
+	}
+	return merged
+}
+
+// ----------------------------------------------------------------------
+// Three-way merge
+// ----------------------------------------------------------------------
+
+// Conflict is a key (or map entry) changed differently by both sides of a
+// three-way merge. See Merge3.
+type Conflict struct {
+	Key    string
+	MapKey string      // of conflicting map entries, or ""
+	Base   interface{} // nil if not defined
+	Mine   interface{} // nil if not defined (or @unset)
+	Theirs interface{} // nil if not defined (or @unset)
+}
+
+func (c Conflict) String() string {
+	key := c.Key
+	if c.MapKey != empty {
+		key += " <" + c.MapKey + ">"
+	}
+	return fmt.Sprintf("conflict %s - base: %v, mine: %v, theirs: %v", key, c.Base, c.Mine, c.Theirs)
+}
+
+// Merges the changes of mine and theirs from their common ancestor base,
+// e.g. of an operator's edits and an automated update of a file, as version
+// control does: keys changed (or defined, or removed) by one side only
+// are taken from that side, and keys changed identically by both are
+// taken as is. Map values are merged per map key.
+//
+// Keys (and map entries) changed differently by both sides are conflicts,
+// which are returned, in order of key, with the value of mine retained in
+// the merged Properties, for resolution by the caller.
+func Merge3(base, mine, theirs Properties) (Properties, []Conflict) {
+	keys := make(map[string]bool)
+	for _, p := range []Properties{base, mine, theirs} {
+		for k := range p {
+			keys[k] = true
+		}
+	}
+	merged := make(Properties, len(keys))
+	var conflicts []Conflict
+	for _, k := range sortedKeys(keys) {
+		b, m, t := base.get(k), mine.get(k), theirs.get(k)
+		switch {
+		case reflect.DeepEqual(m, t), reflect.DeepEqual(b, t):
+			if m != nil {
+				merged[k] = mine[k]
+			}
+		case reflect.DeepEqual(b, m):
+			if t != nil {
+				merged[k] = theirs[k]
+			}
+		case isMapKey(k) && m != nil && t != nil:
+			bm, _ := b.(map[string]string)
+			mapv, mconflicts := mergeMaps3(k, bm, m.(map[string]string), t.(map[string]string))
+			if len(mapv) > 0 {
+				merged[k] = mapv
+			}
+			conflicts = append(conflicts, mconflicts...)
+		default:
+			if m != nil {
+				merged[k] = mine[k]
+			}
+			conflicts = append(conflicts, Conflict{Key: k, Base: b, Mine: m, Theirs: t})
+		}
+	}
+	return merged, conflicts
+}
+
+// returns the three-way merge of the map values of key, per Merge3.
+func mergeMaps3(key string, base, mine, theirs map[string]string) (map[string]string, []Conflict) {
+	mks := make(map[string]bool)
+	for _, m := range []map[string]string{base, mine, theirs} {
+		for mk := range m {
+			mks[mk] = true
+		}
+	}
+	mapv := make(map[string]string, len(mks))
+	var conflicts []Conflict
+	for _, mk := range sortedKeys(mks) {
+		b, bok := base[mk]
+		m, mok := mine[mk]
+		t, tok := theirs[mk]
+		switch {
+		case m == t && mok == tok, b == t && bok == tok:
+			if mok {
+				mapv[mk] = m
+			}
+		case b == m && bok == mok:
+			if tok {
+				mapv[mk] = t
+			}
+		default:
+			if mok {
+				mapv[mk] = m
+			}
+			c := Conflict{Key: key, MapKey: mk}
+			if bok {
+				c.Base = b
+			}
+			if mok {
+				c.Mine = m
+			}
+			if tok {
+				c.Theirs = t
+			}
+			conflicts = append(conflicts, c)
+		}
+	}
+	return mapv, conflicts
+}
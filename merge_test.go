@@ -0,0 +1,96 @@
+package gestalt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderedArrays(t *testing.T) {
+	parent := Properties{"a[]": []string{"a", "b", "a"}, "s": "parent"}
+	for _, c := range []struct {
+		opts     []MergeOption
+		expected []string
+	}{
+		{nil, []string{"a", "a", "c", "b", "c"}}, // parent-only elements, then child's
+		{[]MergeOption{OrderedArrays(false)}, []string{"a", "b", "a", "c", "c"}},
+		{[]MergeOption{OrderedArrays(true)}, []string{"a", "b", "c"}},
+	} {
+		child := Properties{"a[]": []string{"c", "b", "c"}, "s": "child"}
+		child.Inherit(parent, c.opts...)
+		if v := child.GetArray("a[]"); !reflect.DeepEqual(v, c.expected) {
+			t.Errorf("TestOrderedArrays - Inherit(%d opts) - expected: %v, got: %v", len(c.opts), c.expected, v)
+		}
+		if v := child.GetString("s"); v != "child" {
+			t.Errorf("TestOrderedArrays - Inherit - GetString(s) - expected: child, got: %s", v)
+		}
+	}
+
+	sp := NewSafeProperties(Properties{"a[]": []string{"x", "y"}, "b[]": []string{"p"}})
+	if e := sp.Merge(Properties{"a[]": []string{"z", "x"}, "c[]": []string{"q"}}, OrderedArrays(true)); e != nil {
+		t.Fatalf("TestOrderedArrays - Merge - %s", e)
+	}
+	p := sp.Properties()
+	for k, expected := range map[string][]string{"a[]": {"x", "y", "z"}, "b[]": {"p"}, "c[]": {"q"}} {
+		if v := p.GetArray(k); !reflect.DeepEqual(v, expected) {
+			t.Errorf("TestOrderedArrays - Merge(OrderedArrays(true)) - GetArray(%s) - expected: %v, got: %v", k, expected, v)
+		}
+	}
+	if e := sp.Merge(Properties{"a[]": []string{"w"}}); e != nil || !reflect.DeepEqual(sp.Properties().GetArray("a[]"), []string{"w"}) {
+		t.Errorf("TestOrderedArrays - Merge - expected replaced array, got: %v, %v", sp.Properties().GetArray("a[]"), e)
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	base, _ := LoadStr(`
+same = 1
+mine.only = 1
+theirs.only = 1
+both.same = 1
+both.diff = 1
+removed.mine = 1
+removed.theirs = 1
+m[:] = a:1, b:1, c:1
+`)
+	mine, _ := LoadStr(`
+same = 1
+mine.only = 2
+theirs.only = 1
+both.same = 2
+both.diff = 2
+removed.theirs = 1
+added.mine = x
+m[:] = a:2, b:1, c:2
+`)
+	theirs, _ := LoadStr(`
+same = 1
+mine.only = 1
+theirs.only = 3
+both.same = 2
+both.diff = 3
+removed.mine = 1
+removed.theirs = @unset
+m[:] = a:1, b:3, c:3
+`)
+	merged, conflicts := Merge3(base, mine, theirs)
+	for k, expected := range map[string]string{
+		"same": "1", "mine.only": "2", "theirs.only": "3", "both.same": "2", "both.diff": "2",
+		"removed.mine": "", "removed.theirs": "", "added.mine": "x",
+	} {
+		if v := merged.GetString(k); v != expected {
+			t.Errorf("TestMerge3 - GetString(%s) - expected: %q, got: %q", k, expected, v)
+		}
+	}
+	if v := merged.GetMap("m[:]"); !reflect.DeepEqual(v, map[string]string{"a": "2", "b": "3", "c": "2"}) {
+		t.Errorf("TestMerge3 - GetMap(m[:]) - expected: map[a:2 b:3 c:2], got: %v", v)
+	}
+	expected := []Conflict{
+		{Key: "both.diff", Base: "1", Mine: "2", Theirs: "3"},
+		{Key: "m[:]", MapKey: "c", Base: "1", Mine: "2", Theirs: "3"},
+	}
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("TestMerge3 - conflicts - expected: %v, got: %v", expected, conflicts)
+	}
+	if s := conflicts[1].String(); s != "conflict m[:] <c> - base: 1, mine: 2, theirs: 3" {
+		t.Errorf("TestMerge3 - Conflict.String() - got: %s", s)
+	}
+}
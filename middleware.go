@@ -0,0 +1,159 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// Load middleware
+//
+// features of loads (e.g. decryption, interpolation, validation) can be
+// composed explicitly, per call site, as a chain of middleware around a
+// base Loader, e.g.
+//
+//	loader := gestalt.Chain(gestalt.FileLoader(),
+//		gestalt.Validating(schema),
+//		gestalt.Interpolating(gestalt.EnvInterpolator),
+//		gestalt.Decrypting(gestalt.PassphraseFromEnv("APP_KEY")),
+//	)
+//	p, e := loader.Load(ctx, "app.conf.enc")
+//
+// Middleware may process the loaded Properties (e.g. Interpolating), or
+// the content of the loaded file, before it is parsed (e.g. Decrypting).
+// The first middleware of a chain is the outermost, i.e. the last to
+// process loaded Properties, so validation is listed before interpolation.
+// ----------------------------------------------------------------------
+
+// Loader loads the Properties of a named source, e.g. a file.
+type Loader interface {
+	Load(ctx context.Context, name string) (Properties, error)
+}
+
+// LoaderFunc adapts a function to the Loader interface.
+type LoaderFunc func(ctx context.Context, name string) (Properties, error)
+
+func (f LoaderFunc) Load(ctx context.Context, name string) (Properties, error) {
+	return f(ctx, name)
+}
+
+// LoadMiddleware wraps a Loader with a feature of loads.
+type LoadMiddleware func(next Loader) Loader
+
+// Returns base wrapped by middleware mw, with mw[0] the outermost.
+func Chain(base Loader, mw ...LoadMiddleware) Loader {
+	l := base
+	for i := len(mw) - 1; i >= 0; i-- {
+		l = mw[i](l)
+	}
+	return l
+}
+
+// Returns a Source of the Properties of name loaded by l, e.g. for Watch.
+func LoaderSource(l Loader, name string) Source {
+	return SourceFunc(func(ctx context.Context) (Properties, error) {
+		return l.Load(ctx, name)
+	})
+}
+
+// transforms the content of a file before it is parsed
+type contentTransform func(b []byte) ([]byte, error)
+
+type transformsKey struct{}
+
+// returns ctx with t appended to the content transforms of base loaders
+func withTransform(ctx context.Context, t contentTransform) context.Context {
+	ts, _ := ctx.Value(transformsKey{}).([]contentTransform)
+	return context.WithValue(ctx, transformsKey{}, append(ts[:len(ts):len(ts)], t))
+}
+
+// Returns the base Loader of files, per opts (see Load). The content of
+// files is transformed per the middleware of the chain (e.g. Decrypting)
+// before it is parsed.
+func FileLoader(opts ...LoadOption) Loader {
+	return LoaderFunc(func(ctx context.Context, name string) (Properties, error) {
+		o := newLoadOptions(opts)
+		b, e := o.readFile(name)
+		if e != nil {
+			return nil, fmt.Errorf("Error reading gestalt file <%s> : %w", name, e)
+		}
+		ts, _ := ctx.Value(transformsKey{}).([]contentTransform)
+		for i := len(ts) - 1; i >= 0; i-- {
+			if b, e = ts[i](b); e != nil {
+				return nil, fmt.Errorf("gestalt file <%s> : %w", name, e)
+			}
+		}
+		o.dir = filepath.Dir(name)
+		return loadBuffer(string(b), o)
+	})
+}
+
+// Decrypting decrypts the content of loaded files (see EncryptFile) with
+// the passphrase of ks.
+func Decrypting(ks KeySource) LoadMiddleware {
+	return func(next Loader) Loader {
+		return LoaderFunc(func(ctx context.Context, name string) (Properties, error) {
+			return next.Load(withTransform(ctx, func(b []byte) ([]byte, error) {
+				return Decrypt(b, ks)
+			}), name)
+		})
+	}
+}
+
+// Interpolating expands the references of loaded Properties, per the
+// interpolators in. See Properties.Interpolate.
+func Interpolating(in ...Interpolator) LoadMiddleware {
+	return Processing(func(p Properties) (Properties, error) {
+		return p.Interpolate(in...)
+	})
+}
+
+// Validating rejects loaded Properties that are not valid per schema,
+// having applied the defaults of the schema. See Schema.Validate.
+func Validating(schema *Schema) LoadMiddleware {
+	return Processing(func(p Properties) (Properties, error) {
+		p, e := schema.ApplyDefaults(p)
+		if e != nil {
+			return nil, e
+		}
+		if errs := schema.Validate(p); len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		return p, nil
+	})
+}
+
+// Normalizing renames the keys of loaded Properties per fn, e.g.
+// strings.ToLower. Returns error if fn maps keys to the same key.
+func Normalizing(fn func(key string) string) LoadMiddleware {
+	return Processing(func(p Properties) (Properties, error) {
+		np := make(Properties, len(p))
+		for _, k := range sortedKeys(p) {
+			nk := fn(k)
+			if _, dup := np[nk]; dup {
+				return nil, &KeyError{k, fmt.Errorf("normalized key <%s> is not unique", nk)}
+			}
+			np[nk] = p[k]
+		}
+		return np, nil
+	})
+}
+
+// Processing processes loaded Properties with fn, e.g. for features not
+// provided as middleware.
+func Processing(fn func(p Properties) (Properties, error)) LoadMiddleware {
+	return func(next Loader) Loader {
+		return LoaderFunc(func(ctx context.Context, name string) (Properties, error) {
+			p, e := next.Load(ctx, name)
+			if e != nil {
+				return nil, e
+			}
+			return fn(p)
+		})
+	}
+}
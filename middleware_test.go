@@ -0,0 +1,71 @@
+package gestalt
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestChain(t *testing.T) {
+	ks := Passphrase("correct horse battery staple")
+	ciphertext, e := Encrypt([]byte("db.host = localhost\ndb.url = pg://${db.host}\nDB.Port = 5432\n"), ks)
+	if e != nil {
+		t.Fatalf("TestChain - Encrypt - %s", e)
+	}
+	fsys := FS(fstest.MapFS{
+		"app.conf.enc": {Data: ciphertext},
+		"dup.conf":     {Data: []byte("a = 1\nA = 2\n")},
+	})
+	schema := &Schema{Keys: []KeySpec{
+		{Key: "db.port", Type: TypeInt, Required: true},
+		{Key: "db.pool", Type: TypeInt, Default: "10"},
+	}}
+
+	var order []string
+	trace := func(name string) LoadMiddleware {
+		return Processing(func(p Properties) (Properties, error) {
+			order = append(order, name)
+			return p, nil
+		})
+	}
+	loader := Chain(FileLoader(WithFileSystem(fsys)),
+		trace("outer"),
+		Decrypting(ks),
+		Interpolating(),
+		Validating(schema),
+		Normalizing(strings.ToLower),
+		trace("inner"),
+	)
+	ctx := context.Background()
+	p, e := loader.Load(ctx, "app.conf.enc")
+	if e != nil {
+		t.Fatalf("TestChain - Load(app.conf.enc) - %s", e)
+	}
+	for k, want := range map[string]string{"db.url": "pg://localhost", "db.port": "5432", "db.pool": "10"} {
+		if v := p.GetString(k); v != want {
+			t.Errorf("TestChain - GetString(%s) - expected: %s, got: %s", k, want, v)
+		}
+	}
+	if strings.Join(order, ",") != "inner,outer" {
+		t.Errorf("TestChain - middleware order - expected: inner,outer, got: %v", order)
+	}
+
+	if _, e := Chain(FileLoader(WithFileSystem(fsys)), Decrypting(Passphrase("wrong"))).Load(ctx, "app.conf.enc"); e == nil {
+		t.Errorf("TestChain - Decrypting(wrong) - error expected")
+	}
+	if _, e := Chain(FileLoader(WithFileSystem(fsys)), Decrypting(ks), Validating(&Schema{Keys: []KeySpec{{Key: "nope", Required: true}}})).Load(ctx, "app.conf.enc"); e == nil {
+		t.Errorf("TestChain - Validating - error expected for missing required key")
+	}
+	if _, e := Chain(FileLoader(WithFileSystem(fsys)), Normalizing(strings.ToLower)).Load(ctx, "dup.conf"); e == nil {
+		t.Errorf("TestChain - Normalizing - error expected for duplicate keys")
+	}
+	if _, e := FileLoader(WithFileSystem(fsys)).Load(ctx, "nope.conf"); e == nil {
+		t.Errorf("TestChain - Load(nope.conf) - error expected")
+	}
+
+	p, e = LoaderSource(Chain(FileLoader(WithFileSystem(fsys)), Decrypting(ks)), "app.conf.enc").Load(ctx)
+	if e != nil || p.GetString("db.host") != "localhost" {
+		t.Errorf("TestChain - LoaderSource - got: %v, %v", p, e)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Migration migrates the values of keys From to keys To, e.g. between
+// versions of a configuration layout. See Rename, Transform, Split, and
+// Merge for common migrations.
+//
+// Values are converted as plain strings (see Convert), and the results
+// are parsed per the type (suffix) of the To keys, so that, for example,
+// a migration from "hosts" to "hosts[]" changes the type of the key.
+type Migration struct {
+	From []string
+	To   []string
+	// converts the values of From (with "" for undefined keys) to those of
+	// To. Empty results are not defined. nil Convert passes values as is,
+	// and requires as many To as From keys.
+	Convert func(from []string) (to []string, e error)
+}
+
+// Returns a Migration renaming key from to key to.
+func Rename(from, to string) Migration {
+	return Migration{From: []string{from}, To: []string{to}}
+}
+
+// Returns a Migration converting the value of key with fn, e.g. from
+// seconds ("30") to a duration ("30s").
+func Transform(key string, fn func(v string) (string, error)) Migration {
+	return Migration{From: []string{key}, To: []string{key}, Convert: func(from []string) ([]string, error) {
+		v, e := fn(from[0])
+		return []string{v}, e
+	}}
+}
+
+// Returns a Migration splitting the value of key from into keys to, per fn,
+// e.g. "db.addr" into "db.host" and "db.port".
+func Split(from string, to []string, fn func(v string) ([]string, error)) Migration {
+	return Migration{From: []string{from}, To: to, Convert: func(from []string) ([]string, error) {
+		return fn(from[0])
+	}}
+}
+
+// Returns a Migration merging the values of keys from into key to, per fn.
+func Merge(from []string, to string, fn func(v []string) (string, error)) Migration {
+	return Migration{From: from, To: []string{to}, Convert: func(from []string) ([]string, error) {
+		v, e := fn(from)
+		return []string{v}, e
+	}}
+}
+
+// Returns a copy of p with migrations applied, in order. Migrations of keys
+// that are not defined in p are skipped, so applying migrations is
+// idempotent. @unset keys are retained.
+//
+// Returns a *KeyError if a migration fails, or its To key is already
+// defined (other than by its From keys).
+func ApplyMigrations(p Properties, migrations ...Migration) (Properties, error) {
+	p = p.Clone()
+	for _, m := range migrations {
+		if _, e := m.apply(p, nil); e != nil {
+			return nil, e
+		}
+	}
+	return p, nil
+}
+
+// applies the migration to p. If anchors is not nil, the anchor of each To
+// key is set to that of the first defined From key. Returns false if
+// no From key is defined.
+func (m Migration) apply(p Properties, anchors map[string]string) (bool, error) {
+	var anchor string
+	from := make([]string, len(m.From))
+	defined := false
+	for i, k := range m.From {
+		if v := p.get(k); v != nil {
+			from[i] = plainRep(v)
+			if !defined {
+				anchor, defined = anchors[k], true
+			}
+		}
+	}
+	if !defined {
+		return false, nil
+	}
+	key := strings.Join(m.From, ", ")
+
+	to := from
+	if m.Convert != nil {
+		var e error
+		if to, e = m.Convert(from); e != nil {
+			return false, &KeyError{key, fmt.Errorf("migration failed - %w", e)}
+		}
+	}
+	if len(to) != len(m.To) {
+		return false, &KeyError{key, fmt.Errorf("migration failed - %d values for %d keys", len(to), len(m.To))}
+	}
+
+	isFrom := make(map[string]bool, len(m.From))
+	for _, k := range m.From {
+		isFrom[k] = true
+	}
+	values := make([]interface{}, len(m.To))
+	for i, k := range m.To {
+		if !isFrom[k] && p.get(k) != nil {
+			return false, &KeyError{k, fmt.Errorf("migration target is already defined")}
+		}
+		if to[i] == empty {
+			continue
+		}
+		v, e := parseValue(k, to[i])
+		if e != nil {
+			return false, &KeyError{k, fmt.Errorf("migration failed - %w", e)}
+		}
+		values[i] = v
+	}
+
+	for _, k := range m.From {
+		delete(p, k)
+		delete(anchors, k)
+	}
+	for i, k := range m.To {
+		if values[i] != nil {
+			p[k] = values[i]
+			if anchors != nil {
+				anchors[k] = anchor
+			}
+		}
+	}
+	return true, nil
+}
+
+// Migrates the properties of s (in gestalt file syntax) and returns the
+// rewritten content. Comment lines, blank lines, and the definitions of
+// keys that are not migrated are retained as is. Migrated keys are defined
+// at the position of the (first) definition of their (first) From key,
+// replacing it (including its trailing comment, if any).
+//
+// Returns error if s is malformed, or a migration fails (see
+// ApplyMigrations).
+func MigrateStr(s string, migrations ...Migration) (string, error) {
+	p, e := LoadStr(s)
+	if e != nil {
+		return "", e
+	}
+	anchors := make(map[string]string, len(p))
+	for k := range p {
+		anchors[k] = k
+	}
+	np := p.Clone()
+	for _, m := range migrations {
+		if _, e := m.apply(np, anchors); e != nil {
+			return "", e
+		}
+	}
+
+	// keys (re)defined at the position of each anchor, in key order
+	defs := make(map[string][]string)
+	for _, k := range sortedKeys(np) {
+		if a := anchors[k]; a != k || !equal(Properties{k: p[k]}, Properties{k: np[k]}) {
+			defs[a] = append(defs[a], k)
+		}
+	}
+
+	var b strings.Builder
+	done := make(map[string]bool)
+	for _, entry := range entries(s) {
+		k := entry.key
+		if k == empty {
+			b.WriteString(entry.text)
+			continue
+		}
+		if np[k] != nil && !slices.Contains(defs[k], k) {
+			b.WriteString(entry.text) // not migrated
+		}
+		if done[k] {
+			continue
+		}
+		done[k] = true
+		for _, dk := range defs[k] {
+			vrep, e := valueRep(np[dk])
+			if e != nil {
+				return "", &KeyError{dk, e}
+			}
+			fmt.Fprintf(&b, "%s = %s\n", dk, vrep)
+		}
+	}
+	return b.String(), nil
+}
+
+// an entry of a file: a property definition (with continuation lines),
+// or a comment or blank line (with no key).
+type entry struct {
+	key  string
+	text string
+}
+
+// returns the entries of s
+func entries(s string) (es []entry) {
+	lines := strings.SplitAfter(s, "\n")
+	for i := 0; i < len(lines); i++ {
+		text := lines[i]
+		for cont := i; strings.ContainsRune(stripComment(lines[cont]), continuation) && cont+1 < len(lines); {
+			cont++
+			text += lines[cont]
+			i = cont
+		}
+		var key string
+		if specs := splitCleanPropSpecs(text); len(specs) > 0 {
+			if k, _, _, e := parseProperty(specs[0]); e == nil {
+				key, _, _, _ = splitWindow(k)
+			}
+		}
+		if text != empty {
+			es = append(es, entry{key, text})
+		}
+	}
+	return
+}
+
+// returns line sans its trailing comment, if any
+func stripComment(line string) string {
+	if i := strings.IndexByte(line, comment); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
@@ -0,0 +1,84 @@
+package gestalt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var migrations = []Migration{
+	Rename("db.hostname", "db.host"),
+	Rename("db.replicas", "db.replicas[]"),
+	Transform("db.timeout", func(v string) (string, error) { return v + "s", nil }),
+	Split("db.addr", []string{"db.backup.host", "db.backup.port"}, func(v string) ([]string, error) {
+		host, port, ok := strings.Cut(v, ":")
+		if !ok {
+			return nil, errors.New("expected host:port")
+		}
+		return []string{host, port}, nil
+	}),
+}
+
+func TestApplyMigrations(t *testing.T) {
+	p := Properties{"db.hostname": "localhost", "db.replicas": "a, b", "db.addr": "backup:5432", "db.timeout": "30", "app": "gestalt"}
+	mp, e := ApplyMigrations(p, migrations...)
+	if e != nil {
+		t.Fatalf("TestApplyMigrations - %s", e)
+	}
+	expected := Properties{
+		"db.host":        "localhost",
+		"db.replicas[]":  []string{"a", "b"},
+		"db.timeout":     "30s",
+		"db.backup.host": "backup",
+		"db.backup.port": "5432",
+		"app":            "gestalt",
+	}
+	if !equal(mp, expected) {
+		t.Errorf("TestApplyMigrations - expected: %v, got: %v", expected, mp)
+	}
+	if p.GetString("db.hostname") != "localhost" {
+		t.Errorf("TestApplyMigrations - expected p unmodified, got: %v", p)
+	}
+
+	// idempotent, except for transforms
+	if mp2, e := ApplyMigrations(mp, migrations[:2]...); e != nil || !equal(mp, mp2) {
+		t.Errorf("TestApplyMigrations - reapplied - expected: %v, got: %v, %v", mp, mp2, e)
+	}
+
+	if _, e := ApplyMigrations(Properties{"a": "1", "b": "2"}, Rename("a", "b")); e == nil {
+		t.Errorf("TestApplyMigrations - defined target - error expected")
+	}
+	if _, e := ApplyMigrations(Properties{"db.addr": "backup"}, migrations[3]); e == nil || !strings.Contains(e.Error(), "expected host:port") {
+		t.Errorf("TestApplyMigrations - failed split - expected error, got: %v", e)
+	}
+}
+
+func TestMigrateStr(t *testing.T) {
+	s := `# database
+db.hostname = localhost   # primary
+db.replicas = a, \
+  b
+db.addr = backup:5432
+
+# timeouts
+db.timeout = 30
+app = gestalt
+`
+	expected := `# database
+db.host = localhost
+db.replicas[] = a, b
+db.backup.host = backup
+db.backup.port = 5432
+
+# timeouts
+db.timeout = 30s
+app = gestalt
+`
+	ms, e := MigrateStr(s, migrations...)
+	if e != nil {
+		t.Fatalf("TestMigrateStr - %s", e)
+	}
+	if ms != expected {
+		t.Errorf("TestMigrateStr - expected:\n%s\ngot:\n%s", expected, ms)
+	}
+}
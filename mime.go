@@ -0,0 +1,84 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"unicode"
+)
+
+// returns the canonical form of media type s, e.g. "text/html; charset=utf-8"
+// of "Text/HTML;charset=UTF-8". See mime.ParseMediaType.
+func parseMediaType(s string) (string, error) {
+	mt, params, e := mime.ParseMediaType(s)
+	if e != nil {
+		return "", fmt.Errorf("malformed media type <%s> - %s", s, e)
+	}
+	if !strings.Contains(mt, "/") {
+		return "", fmt.Errorf("malformed media type <%s> - missing subtype", s)
+	}
+	if cs, ok := params["charset"]; ok {
+		params["charset"] = strings.ToLower(cs)
+	}
+	return mime.FormatMediaType(mt, params), nil
+}
+
+// Media type value property, e.g. `http.default.type = text/html` - returns
+// the canonical (lower case) media type, or error if no such key or value is
+// not a valid media type. Note that the `=` char is reserved, and parameters
+// of media types (e.g. the charset) are set by separate keys, see GetCharset.
+func (p Properties) GetMediaType(key string) (string, error) {
+	v, e := p.typed(key, typed_mediatype)
+	if e != nil {
+		return "", e
+	}
+	return v.(string), nil
+}
+
+// Charset value property, e.g. `http.default.charset = UTF-8` - returns the
+// (lower case) charset name, or error if no such key or value is not a valid
+// charset name (per RFC 2978).
+func (p Properties) GetCharset(key string) (string, error) {
+	v, e := p.typed(key, typed_charset)
+	if e != nil {
+		return "", e
+	}
+	return v.(string), nil
+}
+
+// returns the lower case charset name s
+func parseCharset(s string) (string, error) {
+	if s == empty || strings.IndexFunc(s, func(c rune) bool {
+		return c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("!#$%&'+-^_`{}~", c))
+	}) >= 0 {
+		return "", fmt.Errorf("malformed charset <%s>", s)
+	}
+	return strings.ToLower(s), nil
+}
+
+// Content type map value property, of file extensions to media types, e.g.
+//
+//	web.resource.types[:] = js:text/javascript, css:text/css, svg:image/svg+xml
+//
+// Returns the canonical media types by (lower case) extension with a leading
+// dot, as used by mime.TypeByExtension. Returns error if no such key or a map
+// value is not a valid media type.
+func (p Properties) GetMediaTypeMap(key string) (map[string]string, error) {
+	mapv, e := p.mapValue(key)
+	if e != nil {
+		return nil, e
+	}
+	types := make(map[string]string, len(mapv))
+	for _, mk := range sortedKeys(mapv) {
+		mt, e := parseMediaType(mapv[mk])
+		if e != nil {
+			return nil, wrongType("key <%s> - map key <%s> - %s", key, mk, e)
+		}
+		types["."+strings.ToLower(strings.TrimPrefix(mk, "."))] = mt
+	}
+	return types, nil
+}
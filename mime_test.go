@@ -0,0 +1,58 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetMediaType(t *testing.T) {
+	p := Properties{"html": "Text/HTML", "params": "text/html; charset=UTF-8", "bad": "text/html; charset", "nosub": "text"}
+	for key, expected := range map[string]string{"html": "text/html", "params": "text/html; charset=utf-8"} {
+		if mt, e := p.GetMediaType(key); e != nil || mt != expected {
+			t.Errorf("TestGetMediaType - GetMediaType(%s) - expected: %s, got: %s, %v", key, expected, mt, e)
+		}
+	}
+	for _, key := range []string{"bad", "nosub", "nope"} {
+		if _, e := p.GetMediaType(key); e == nil {
+			t.Errorf("TestGetMediaType - GetMediaType(%s) - error expected", key)
+		}
+	}
+}
+
+func TestGetCharset(t *testing.T) {
+	p := Properties{"cs": "UTF-8", "bad": "utf 8", "empty": ""}
+	if cs, e := p.GetCharset("cs"); e != nil || cs != "utf-8" {
+		t.Errorf("TestGetCharset - GetCharset(cs) - expected: utf-8, got: %s, %v", cs, e)
+	}
+	for _, key := range []string{"bad", "empty", "nope"} {
+		if _, e := p.GetCharset(key); e == nil {
+			t.Errorf("TestGetCharset - GetCharset(%s) - error expected", key)
+		}
+	}
+}
+
+func TestGetMediaTypeMap(t *testing.T) {
+	p, e := LoadStr(`
+web.resource.types[:] = js:text/javascript, .CSS:Text/CSS, svg:image/svg+xml
+bad.types[:] = js:javascript
+`)
+	if e != nil {
+		t.Fatalf("TestGetMediaTypeMap - LoadStr - unexpected error: %s", e)
+	}
+	expected := map[string]string{".js": "text/javascript", ".css": "text/css", ".svg": "image/svg+xml"}
+	if types, e := p.GetMediaTypeMap("web.resource.types[:]"); e != nil || !reflect.DeepEqual(types, expected) {
+		t.Errorf("TestGetMediaTypeMap - expected: %v, got: %v, %v", expected, types, e)
+	}
+	if _, e := p.GetMediaTypeMap("bad.types[:]"); e == nil {
+		t.Errorf("TestGetMediaTypeMap - GetMediaTypeMap(bad.types[:]) - error expected")
+	}
+
+	s := &Schema{Keys: []KeySpec{{Key: "web.resource.types[:]", Type: TypeMediaType}, {Key: "bad.types[:]", Type: TypeMediaType}}}
+	if errs := s.Validate(p); len(errs) != 1 || errs[0].(*KeyError).Key != "bad.types[:]" {
+		t.Errorf("TestGetMediaTypeMap - Validate - expected error of key bad.types[:], got: %v", errs)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NumberLocale describes the number format of a locale, e.g. of values
+// authored by non-engineering staff. See the Locale option.
+type NumberLocale struct {
+	Group   string // thousands separators
+	Decimal rune   // decimal mark
+}
+
+// number formats of common locales
+var (
+	LocaleEN = NumberLocale{Group: ",", Decimal: '.'}             // 1,000.5
+	LocaleDE = NumberLocale{Group: ".", Decimal: ','}             // 1.000,5
+	LocaleFR = NumberLocale{Group: " \u00a0\u202f", Decimal: ','} // 1 000,5
+	LocaleCH = NumberLocale{Group: "'", Decimal: '.'}             // 1'000.5
+)
+
+// Locale sets the number format of the values of the numeric getters
+// GetFloat, GetPercent, GetQuantity, GetWeightedMap, and As with Coerce
+// (for int and float64). GetInt (of the Config interface) takes no options,
+// i.e. int values are read per locale with As[int], e.g.
+//
+//	n, e := As[int](p, "max.orders", Coerce(), Locale(LocaleDE))  // 1000 of "1.000"
+//
+// Thousands separators must separate groups of 3 digits, so that, for
+// example, "3.5" is not taken for 35 with LocaleDE.
+func Locale(l NumberLocale) GetOption {
+	return func(o *getOptions) {
+		o.locale = &l
+	}
+}
+
+// Float value property - returns error if no such key or value is not a
+// number (per the Locale option, if any).
+func (p Properties) GetFloat(key string, opts ...GetOption) (float64, error) {
+	s, e := p.stringValue(key)
+	if e != nil {
+		return 0, e
+	}
+	f, e := newGetOptions(opts).parseFloat(s)
+	if e != nil {
+		return 0, &KeyError{key, e}
+	}
+	return f, nil
+}
+
+// parses s per the locale of the options, if any
+func (o *getOptions) parseFloat(s string) (float64, error) {
+	if o.locale != nil {
+		var e error
+		if s, e = o.locale.normalize(s); e != nil {
+			return 0, e
+		}
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// returns s in the (Go) number syntax
+func (l *NumberLocale) normalize(s string) (string, error) {
+	s = strings.Trim(s, ws)
+	sign := ""
+	if s != empty && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+	ip, fp, decimal := strings.Cut(s, string(l.Decimal))
+	if strings.ContainsAny(ip, l.Group) {
+		groups := strings.FieldsFunc(ip, func(c rune) bool { return strings.ContainsRune(l.Group, c) })
+		for i, g := range groups {
+			if len(g) > 3 || (i > 0 && len(g) != 3) || strings.IndexFunc(g, func(c rune) bool { return !unicode.IsDigit(c) }) >= 0 {
+				return "", fmt.Errorf("malformed number <%s%s> - digit groups", sign, s)
+			}
+		}
+		ip = strings.Join(groups, empty)
+	}
+	if decimal {
+		return sign + ip + "." + fp, nil
+	}
+	return sign + ip, nil
+}
+
+// Fractions sets the interpretation of values of GetPercent without a
+// percent sign to fractions of 1, e.g. "0.25" for 25%. By default, such
+// values are percents, e.g. "25".
+func Fractions() GetOption {
+	return func(o *getOptions) {
+		o.fractions = true
+	}
+}
+
+// Percent value property, e.g. of sampling rates or rollout fractions -
+// returns the percent (0..100) of values such as "25%", or (per the
+// Fractions option) "25" or "0.25". Returns error if no such key, or the
+// value is not a number (per the Locale option, if any), or is not in
+// the range 0%..100%.
+func (p Properties) GetPercent(key string, opts ...GetOption) (float64, error) {
+	s, e := p.stringValue(key)
+	if e != nil {
+		return 0, e
+	}
+	o := newGetOptions(opts)
+	s = strings.Trim(s, ws)
+	num, percent := strings.CutSuffix(s, "%")
+	f, e := o.parseFloat(strings.Trim(num, ws))
+	if e != nil {
+		return 0, &KeyError{key, e}
+	}
+	if !percent && o.fractions {
+		f *= 100
+	}
+	if !(f >= 0 && f <= 100) {
+		return 0, &KeyError{key, fmt.Errorf("percent <%s> out of range 0%%..100%%", s)}
+	}
+	return f, nil
+}
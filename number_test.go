@@ -0,0 +1,104 @@
+package gestalt
+
+import (
+	"testing"
+)
+
+func TestGetFloat(t *testing.T) {
+	p := Properties{"a": "1 000", "b": "3,5", "c": "1.234.567,25", "d": "-1'000.5", "e": "3.5", "f": "2.5"}
+	for _, c := range []struct {
+		key      string
+		locale   NumberLocale
+		expected float64
+	}{
+		{"a", LocaleFR, 1000},
+		{"b", LocaleFR, 3.5},
+		{"b", LocaleDE, 3.5},
+		{"c", LocaleDE, 1234567.25},
+		{"d", LocaleCH, -1000.5},
+		{"e", LocaleEN, 3.5},
+	} {
+		if v, e := p.GetFloat(c.key, Locale(c.locale)); e != nil || v != c.expected {
+			t.Errorf("TestGetFloat - GetFloat(%s, %v) - expected: %g, got: %g, %v", c.key, c.locale, c.expected, v, e)
+		}
+	}
+	if v, e := p.GetFloat("f"); e != nil || v != 2.5 {
+		t.Errorf("TestGetFloat - GetFloat(f) - expected: 2.5, got: %g, %v", v, e)
+	}
+	for _, key := range []string{"a", "b"} {
+		if _, e := p.GetFloat(key); e == nil {
+			t.Errorf("TestGetFloat - GetFloat(%s) without locale - error expected", key)
+		}
+	}
+	if _, e := p.GetFloat("e", Locale(LocaleDE)); e == nil {
+		t.Errorf("TestGetFloat - GetFloat(e, LocaleDE) - expected malformed digit groups")
+	}
+}
+
+func TestLocaleAs(t *testing.T) {
+	p := Properties{"n": "12.000", "f": "0,25", "w[:]": map[string]string{"a": "0,75", "b": "0,25"}}
+	if v, e := As[int](p, "n", Coerce(), Locale(LocaleDE)); e != nil || v != 12000 {
+		t.Errorf("TestLocaleAs - As[int] - expected: 12000, got: %d, %v", v, e)
+	}
+	if v, e := As[float64](p, "f", Coerce(), Locale(LocaleDE)); e != nil || v != 0.25 {
+		t.Errorf("TestLocaleAs - As[float64] - expected: 0.25, got: %g, %v", v, e)
+	}
+	if _, e := As[int](p, "n", Locale(LocaleDE)); e == nil {
+		t.Errorf("TestLocaleAs - As[int] without Coerce - error expected")
+	}
+	if w, e := p.GetWeightedMap("w[:]", Locale(LocaleDE)); e != nil || w["a"] != 0.75 {
+		t.Errorf("TestLocaleAs - GetWeightedMap - expected a: 0.75, got: %v, %v", w, e)
+	}
+}
+
+func TestLocaleQuantity(t *testing.T) {
+	p := Properties{"timeout": "1,5s", "quota": "1 000 MB", "plain": "2.5KB"}
+	if v, e := p.GetQuantity("timeout", "ms", Locale(LocaleDE)); e != nil || v != 1500 {
+		t.Errorf("TestLocaleQuantity - GetQuantity(timeout) - expected: 1500, got: %g, %v", v, e)
+	}
+	if v, e := p.GetQuantity("quota", "GB", Locale(LocaleFR)); e != nil || v != 1 {
+		t.Errorf("TestLocaleQuantity - GetQuantity(quota) - expected: 1, got: %g, %v", v, e)
+	}
+	if v, e := p.GetQuantity("plain", "B"); e != nil || v != 2500 {
+		t.Errorf("TestLocaleQuantity - GetQuantity(plain) - expected: 2500, got: %g, %v", v, e)
+	}
+	if _, e := p.GetQuantity("timeout", "ms"); e == nil {
+		t.Errorf("TestLocaleQuantity - GetQuantity(timeout) without Locale - error expected")
+	}
+}
+
+func TestGetPercent(t *testing.T) {
+	p := Properties{"a": "25%", "b": "25", "c": "0.25", "d": " 12,5 % ", "i": "5 %", "e": "101", "f": "-1%", "g": "1.5", "h": "x%"}
+	for _, c := range []struct {
+		key      string
+		opts     []GetOption
+		expected float64
+	}{
+		{"a", nil, 25},
+		{"a", []GetOption{Fractions()}, 25},
+		{"b", nil, 25},
+		{"c", nil, 0.25},
+		{"c", []GetOption{Fractions()}, 25},
+		{"d", []GetOption{Locale(LocaleDE)}, 12.5},
+		{"g", nil, 1.5},
+		{"i", nil, 5},
+	} {
+		if v, e := p.GetPercent(c.key, c.opts...); e != nil || v != c.expected {
+			t.Errorf("TestGetPercent - GetPercent(%s) - expected: %g, got: %g, %v", c.key, c.expected, v, e)
+		}
+	}
+	for _, c := range []struct {
+		key  string
+		opts []GetOption
+	}{
+		{"e", nil},
+		{"f", nil},
+		{"g", []GetOption{Fractions()}},
+		{"h", nil},
+		{"z", nil},
+	} {
+		if v, e := p.GetPercent(c.key, c.opts...); e == nil {
+			t.Errorf("TestGetPercent - GetPercent(%s) - error expected, got: %g", c.key, v)
+		}
+	}
+}
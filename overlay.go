@@ -0,0 +1,297 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// OverlayStack is a stack of Properties layers, e.g. a base configuration
+// with per-test or per-tenant overrides pushed on top of it.
+//
+// Lookups resolve top-down: the top-most layer that defines a key provides
+// its value, and a key that is @unset in a layer masks all layers below it.
+// Unlike Inherit, array and map values of layers are not merged.
+//
+// OverlayStack is safe for concurrent use.
+type OverlayStack struct {
+	mu     sync.RWMutex
+	layers []Properties
+	names  []string // of the layers, "" if unnamed (see PushLayer)
+	chain  []Interpolator
+	schema *Schema // see Restrict
+}
+
+// Instantiates a new OverlayStack with the specified base layer.
+// nil base is treated as empty Properties.
+func NewOverlayStack(base Properties) *OverlayStack {
+	if base == nil {
+		base = make(Properties)
+	}
+	return &OverlayStack{layers: []Properties{base}}
+}
+
+// Pushes p as the new top layer. nil input is silently ignored.
+func (s *OverlayStack) Push(p Properties) {
+	s.PushLayer(empty, p)
+}
+
+// Pushes p as the new top layer named name, e.g. "env", for the override
+// restrictions of a schema (see Restrict). nil input is silently ignored.
+func (s *OverlayStack) PushLayer(name string, p Properties) {
+	if p == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.names) < len(s.layers) {
+		s.names = append(s.names, empty)
+	}
+	s.layers = append(s.layers, p)
+	s.names = append(s.names, name)
+}
+
+// Pops and returns the top layer. The base layer is never popped and
+// nil is returned if it is the only layer in the stack.
+func (s *OverlayStack) Pop() Properties {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.layers)
+	if n == 1 {
+		return nil
+	}
+	p := s.layers[n-1]
+	s.layers[n-1] = nil
+	s.layers = s.layers[:n-1]
+	if len(s.names) == n {
+		s.names = s.names[:n-1]
+	}
+	return p
+}
+
+// Returns the number of layers, including the base layer.
+func (s *OverlayStack) Depth() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.layers)
+}
+
+// Returns the value of key from the top-most layer defining it,
+// or nil if no such key or key is @unset.
+func (s *OverlayStack) Get(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lookup(key)
+}
+
+func (s *OverlayStack) lookup(key string) interface{} {
+	for i := len(s.layers) - 1; i >= 0; i-- {
+		if v := s.layers[i].value(key); v != nil {
+			if isUnset(v) {
+				return nil
+			}
+			return v
+		}
+	}
+	return nil
+}
+
+// returns nil/zero-value if no such key or key type is not array
+func (s *OverlayStack) GetArray(key string) []string {
+	if isArrayKey(key) {
+		if v := s.Get(key); v != nil {
+			return v.([]string)
+		}
+	}
+	return nil
+}
+
+// returns nil/zero-value if no such key or key type is not map
+func (s *OverlayStack) GetMap(key string) map[string]string {
+	if isMapKey(key) {
+		if v := s.Get(key); v != nil {
+			return v.(map[string]string)
+		}
+	}
+	return nil
+}
+
+// String value property - returns nil/zero-value if no such key or not a string key
+func (s *OverlayStack) GetString(key string) string {
+	if !(isMapKey(key) || isArrayKey(key)) {
+		if v := s.Get(key); v != nil {
+			return v.(string)
+		}
+	}
+	return ""
+}
+
+// Returns the resolved view of all layers as a new Properties object.
+// Keys that are @unset in the resolved view are omitted.
+func (s *OverlayStack) Flatten() Properties {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.flatten()
+}
+
+func (s *OverlayStack) flatten() Properties {
+	flat := make(Properties)
+	for _, layer := range s.layers {
+		flat.Copy(layer, true)
+	}
+	for k, v := range flat {
+		if isUnset(v) {
+			delete(flat, k)
+		}
+	}
+	return flat
+}
+
+// Appends in to the chain of interpolators of references that are not
+// keys of the stack. See Resolve.
+func (s *OverlayStack) Use(in ...Interpolator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chain = append(s.chain, in...)
+}
+
+// Returns the value of key, as Get, with its ${key} references expanded.
+// References resolve against all layers at call time, so a value of one
+// layer may reference a key defined in another (e.g. higher) layer.
+// References that are not keys resolve per the interpolators of the stack,
+// in order (see Use).
+//
+// Returns a *KeyError if a reference is unresolved (see ErrUnresolvedRef),
+// cyclic, or to an array or map key.
+func (s *OverlayStack) Resolve(key string) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, e := s.interpolate(key, nil)
+	if e != nil {
+		return nil, &KeyError{key, e}
+	}
+	return v, nil
+}
+
+// String value property, as Resolve.
+// Returns "" if no such key or not a string key.
+func (s *OverlayStack) ResolveString(key string) (string, error) {
+	if isMapKey(key) || isArrayKey(key) {
+		return "", nil
+	}
+	v, e := s.Resolve(key)
+	if v == nil {
+		return "", e
+	}
+	return v.(string), e
+}
+
+// Returns the resolved view of all layers, as Flatten, with all
+// references expanded. See Resolve.
+func (s *OverlayStack) ResolveAll() (Properties, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	flat := s.flatten()
+	for _, k := range sortedKeys(flat) {
+		v, e := s.interpolate(k, nil)
+		if e != nil {
+			return nil, &KeyError{k, e}
+		}
+		flat[k] = v
+	}
+	return flat, nil
+}
+
+// Restricts the layers that may override the keys of schema to the Layers
+// of their specs, e.g. to allow a layer of environment variables named "env"
+// to override log.level, but not db.host. The base layer may define any key.
+//
+// Keys defined by other layers are errors of ErrOverride on resolution (see
+// Resolve and ResolveAll), including keys referenced by resolved values.
+// Get (and Flatten) are not restricted. nil schema lifts restrictions.
+func (s *OverlayStack) Restrict(schema *Schema) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schema = schema
+}
+
+// ErrOverride is the error of a key defined by a layer that may not
+// override it. See OverlayStack.Restrict.
+var ErrOverride = errors.New("override not allowed")
+
+// returns the error of key if defined by a layer that may not override it
+func (s *OverlayStack) checkOverride(key string) error {
+	spec := schemaSpec(s.schema, key)
+	if spec == nil || spec.Layers == nil {
+		return nil
+	}
+	for i := len(s.layers) - 1; i > 0; i-- {
+		if s.layers[i].value(key) == nil {
+			continue
+		}
+		var name string
+		if i < len(s.names) {
+			name = s.names[i]
+		}
+		if !slices.Contains(spec.Layers, name) {
+			return fmt.Errorf("%w by layer <%s>", ErrOverride, name)
+		}
+	}
+	return nil
+}
+
+// returns the value of key with its references expanded. path is the
+// chain of keys referencing key.
+func (s *OverlayStack) interpolate(key string, path []string) (interface{}, error) {
+	if e := s.checkOverride(key); e != nil {
+		if len(path) > 0 {
+			return nil, fmt.Errorf("reference ${%s} - %w", key, e)
+		}
+		return nil, e
+	}
+	v := s.lookup(key)
+	if v == nil {
+		return nil, nil
+	}
+	path = append(path, key)
+	return expandValue(v, func(ref string) (string, error) {
+		return s.deref(ref, path)
+	})
+}
+
+// returns the value of reference ref, per the keys of the stack, or else
+// its interpolators.
+func (s *OverlayStack) deref(ref string, path []string) (string, error) {
+	if s.lookup(ref) == nil {
+		for _, in := range s.chain {
+			v, ok, e := in.Interpolate(ref)
+			if e != nil {
+				return "", fmt.Errorf("reference ${%s} - %w", ref, e)
+			}
+			if ok {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("%w ${%s}", ErrUnresolvedRef, ref)
+	}
+
+	for _, k := range path {
+		if k == ref {
+			return "", fmt.Errorf("cyclic reference ${%s} - %s", ref, strings.Join(append(path, ref), " -> "))
+		}
+	}
+	if isMapKey(ref) || isArrayKey(ref) {
+		return "", fmt.Errorf("reference ${%s} is not a string key", ref)
+	}
+	v, e := s.interpolate(ref, path)
+	if e != nil {
+		return "", e
+	}
+	return v.(string), nil
+}
@@ -0,0 +1,147 @@
+package gestalt
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOverlayStack(t *testing.T) {
+	base, e := LoadStr(`
+foo = bar
+woof = meow
+hosts[] = a, b
+`)
+	if e != nil {
+		t.Errorf("TestOverlayStack - LoadStr(base) - %s", e)
+	}
+	overlay, e := LoadStr(`
+foo = baz
+hosts[] = @unset
+`)
+	if e != nil {
+		t.Errorf("TestOverlayStack - LoadStr(overlay) - %s", e)
+	}
+
+	stack := NewOverlayStack(base)
+	stack.Push(overlay)
+	if n := stack.Depth(); n != 2 {
+		t.Errorf("TestOverlayStack - Depth - expected: 2, got: %d", n)
+	}
+	if v := stack.GetString("foo"); v != "baz" {
+		t.Errorf("TestOverlayStack - GetString(foo) - expected: baz, got: %s", v)
+	}
+	if v := stack.GetString("woof"); v != "meow" {
+		t.Errorf("TestOverlayStack - GetString(woof) - expected: meow, got: %s", v)
+	}
+	if v := stack.GetArray("hosts[]"); v != nil {
+		t.Errorf("TestOverlayStack - GetArray(hosts[]) - expected: nil, got: %s", v)
+	}
+	flat := stack.Flatten()
+	if _, ok := flat["hosts[]"]; ok || flat.GetString("foo") != "baz" {
+		t.Errorf("TestOverlayStack - Flatten - got: %s", flat)
+	}
+
+	if p := stack.Pop(); p == nil {
+		t.Errorf("TestOverlayStack - Pop - expected: overlay, got: nil")
+	}
+	if v := stack.GetString("foo"); v != "bar" {
+		t.Errorf("TestOverlayStack - GetString(foo) after Pop - expected: bar, got: %s", v)
+	}
+	if p := stack.Pop(); p != nil {
+		t.Errorf("TestOverlayStack - Pop - base layer must not be popped")
+	}
+}
+
+func TestOverlayStackResolve(t *testing.T) {
+	base, _ := LoadStr("db.url = postgres://${db.host}:${db.port}/app\ndb.port = 5432\nhosts[] = ${db.host}, backup\n")
+	s := NewOverlayStack(base)
+
+	_, e := s.ResolveString("db.url")
+	if !errors.Is(e, ErrUnresolvedRef) {
+		t.Errorf("TestOverlayStackResolve - ResolveString(db.url) - expected: ErrUnresolvedRef, got: %v", e)
+	}
+
+	env, _ := LoadStr("db.host = db.local\n")
+	s.Push(env)
+	if v, e := s.ResolveString("db.url"); e != nil || v != "postgres://db.local:5432/app" {
+		t.Errorf("TestOverlayStackResolve - ResolveString(db.url) - got: %s, %v", v, e)
+	}
+	if v, _ := s.Resolve("hosts[]"); !reflect.DeepEqual(v, []string{"db.local", "backup"}) {
+		t.Errorf("TestOverlayStackResolve - Resolve(hosts[]) - got: %v", v)
+	}
+	if v := s.GetString("db.url"); v != "postgres://${db.host}:${db.port}/app" {
+		t.Errorf("TestOverlayStackResolve - GetString(db.url) - expected unexpanded value, got: %s", v)
+	}
+
+	flat, e := s.ResolveAll()
+	if e != nil || flat.GetString("db.url") != "postgres://db.local:5432/app" {
+		t.Errorf("TestOverlayStackResolve - ResolveAll() - got: %v, %v", flat, e)
+	}
+
+	cyc, _ := LoadStr("a = ${b}\nb = ${a}\nlit = $${a}\n")
+	s.Push(cyc)
+	if _, e := s.ResolveString("a"); e == nil || !strings.Contains(e.Error(), "cyclic") {
+		t.Errorf("TestOverlayStackResolve - ResolveString(a) - expected cyclic reference error, got: %v", e)
+	}
+	if v, e := s.ResolveString("lit"); e != nil || v != "${a}" {
+		t.Errorf("TestOverlayStackResolve - ResolveString(lit) - expected: ${a}, got: %s, %v", v, e)
+	}
+}
+
+func TestOverlayStackRestrict(t *testing.T) {
+	schema, e := LoadSchemaStr(`
+[document:db.host]
+layers[] = file
+
+[document:log.level]
+layers[] = file, env
+`)
+	if e != nil {
+		t.Fatalf("TestOverlayStackRestrict - LoadSchemaStr - %s", e)
+	}
+	base, _ := LoadStr("db.host = localhost\nlog.level = info\ndb.url = postgres://${db.host}\n")
+	file, _ := LoadStr("db.host = db.local\n")
+	env, _ := LoadStr("db.host = evil\nlog.level = debug\n")
+
+	s := NewOverlayStack(base)
+	s.PushLayer("file", file)
+	s.Restrict(schema)
+	if v, e := s.ResolveString("db.url"); e != nil || v != "postgres://db.local" {
+		t.Errorf("TestOverlayStackRestrict - ResolveString(db.url) - got: %s, %v", v, e)
+	}
+
+	s.PushLayer("env", env)
+	if v, e := s.ResolveString("log.level"); e != nil || v != "debug" {
+		t.Errorf("TestOverlayStackRestrict - ResolveString(log.level) - got: %s, %v", v, e)
+	}
+	for _, key := range []string{"db.host", "db.url"} {
+		var ke *KeyError
+		_, e := s.Resolve(key)
+		if !errors.Is(e, ErrOverride) || !errors.As(e, &ke) || ke.Key != key || !strings.Contains(e.Error(), "layer <env>") {
+			t.Errorf("TestOverlayStackRestrict - Resolve(%s) - expected: ErrOverride, got: %v", key, e)
+		}
+	}
+	if _, e := s.ResolveAll(); !errors.Is(e, ErrOverride) {
+		t.Errorf("TestOverlayStackRestrict - ResolveAll - expected: ErrOverride, got: %v", e)
+	}
+	if v := s.GetString("db.host"); v != "evil" {
+		t.Errorf("TestOverlayStackRestrict - GetString(db.host) - expected unrestricted: evil, got: %s", v)
+	}
+
+	s.Push(Properties{"log.level": "warn"})
+	if _, e := s.Resolve("log.level"); !errors.Is(e, ErrOverride) || !strings.Contains(e.Error(), "layer <>") {
+		t.Errorf("TestOverlayStackRestrict - Resolve(log.level) - expected ErrOverride of unnamed layer, got: %v", e)
+	}
+	s.Pop()
+	s.Pop()
+	if _, e := s.Resolve("db.host"); e != nil {
+		t.Errorf("TestOverlayStackRestrict - Resolve(db.host) after Pop - %s", e)
+	}
+
+	layers := []Layer{{"file", base}, {"env", env}}
+	if _, e := DumpResolved(layers, schema); !errors.Is(e, ErrOverride) {
+		t.Errorf("TestOverlayStackRestrict - DumpResolved - expected: ErrOverride, got: %v", e)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+// reusable buffers of parsing
+type parseBuffers struct {
+	b     []byte   // of the cleaned content
+	specs []string // of the property specs of the cleaned content
+}
+
+// Parser parses property sets, as LoadStr, reusing its internal buffers
+// across parses, so that hot paths parsing many small, short-lived blobs
+// (e.g. per request) amortize the allocations of parsing, e.g.
+//
+//	var parsers = sync.Pool{New: func() any { return gestalt.NewParser() }}
+//	...
+//	ps := parsers.Get().(*gestalt.Parser)
+//	p, e := ps.Parse(blob)
+//	parsers.Put(ps)
+//
+// Parsed Properties do not share memory with the Parser, and remain valid
+// after subsequent parses. The zero value is a Parser of the default
+// options. A Parser is not safe for concurrent use.
+type Parser struct {
+	o    *loadOptions
+	bufs parseBuffers
+}
+
+// Instantiates a new Parser, parsing per opts (see LoadStr).
+func NewParser(opts ...LoadOption) *Parser {
+	return &Parser{o: newLoadOptions(opts)}
+}
+
+// Instantiates a new Properties object from spec, as LoadStr.
+func (ps *Parser) Parse(spec string) (Properties, error) {
+	if ps.o == nil {
+		ps.o = newLoadOptions(nil)
+	}
+	return parseBuffer(spec, ps.o, &ps.bufs)
+}
+
+// Releases the internal buffers of the parser, e.g. after parsing an
+// unusually large blob, so that they are not retained by pooled parsers.
+func (ps *Parser) Reset() {
+	ps.bufs = parseBuffers{}
+}
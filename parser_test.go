@@ -0,0 +1,72 @@
+package gestalt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParser(t *testing.T) {
+	ps := NewParser()
+	p1, e := ps.Parse("a = 1\nb[] = x, y\nc[:] = k:v")
+	if e != nil {
+		t.Fatalf("TestParser - Parse - %s", e)
+	}
+	p2, e := ps.Parse("a = 2 # two")
+	if e != nil {
+		t.Fatalf("TestParser - Parse - %s", e)
+	}
+	// earlier results are unaffected by subsequent parses
+	if v := p1.GetString("a"); v != "1" {
+		t.Errorf("TestParser - Parse - p1.GetString(a) - expected: 1, got: %s", v)
+	}
+	if v := p1.GetArray("b[]"); len(v) != 2 || v[1] != "y" {
+		t.Errorf("TestParser - Parse - p1.GetArray(b[]) - expected: [x y], got: %v", v)
+	}
+	if v := p2.GetString("a"); v != "2" || len(p2) != 1 {
+		t.Errorf("TestParser - Parse - p2 - expected: map[a:2], got: %v", p2)
+	}
+	if _, e := ps.Parse("a = b = c"); e == nil {
+		t.Errorf("TestParser - Parse(a = b = c) - error expected")
+	}
+
+	ps.Reset()
+	if p, e := ps.Parse("a = 3"); e != nil || p.GetString("a") != "3" {
+		t.Errorf("TestParser - Parse after Reset - got: %v, %v", p, e)
+	}
+	var zero Parser
+	if p, e := zero.Parse("a = 4"); e != nil || p.GetString("a") != "4" {
+		t.Errorf("TestParser - Parse of zero Parser - got: %v, %v", p, e)
+	}
+	p, e := NewParser(Lazy()).Parse("n = 42")
+	if e != nil {
+		t.Fatalf("TestParser - NewParser(Lazy()).Parse - %s", e)
+	}
+	if v, e := p.GetInt("n"); e != nil || v != 42 {
+		t.Errorf("TestParser - NewParser(Lazy()).Parse - GetInt(n) - expected: 42, got: %d, %v", v, e)
+	}
+}
+
+// a small per-request blob
+var benchBlob = func() string {
+	var sb strings.Builder
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&sb, "request.key.%d = value %d # comment\n", i, i)
+	}
+	return sb.String() + "request.tags[] = a, b, c\n"
+}()
+
+func BenchmarkLoadStr(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LoadStr(benchBlob)
+	}
+}
+
+func BenchmarkParser(b *testing.B) {
+	ps := NewParser()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ps.Parse(benchBlob)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+// an opaque line, per PassThrough
+type opaqueLine struct {
+	text  string
+	after string // the key preceding the line in its file, or ""
+}
+
+// PassThrough preserves the lines of loaded files that can not be parsed
+// (e.g. of vendor extensions, or malformed array and map values) as opaque
+// lines, rather than failing the load, so that tools can process (and
+// Store) files with syntax they do not understand. See Opaque.
+//
+// Lines are preserved as cleaned of comments, and joined with their
+// continuations.
+func PassThrough() LoadOption {
+	return func(o *loadOptions) {
+		o.passThrough = true
+	}
+}
+
+// Returns the opaque lines of p (see PassThrough), in order of occurrence,
+// or nil if none. Opaque lines are not keys, and are written by Store
+// after the key preceding them in their file, if any, or else before the
+// keys.
+func (p Properties) Opaque() []string {
+	var lines []string
+	for _, ol := range p.opaque() {
+		lines = append(lines, ol.text)
+	}
+	return lines
+}
+
+// returns the opaque lines of p. The lines are appended (not modified),
+// and so may be read without locking.
+func (p Properties) opaque() []opaqueLine {
+	if s := sideOf(p); s != nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return s.opaque[:len(s.opaque):len(s.opaque)]
+	}
+	return nil
+}
+
+// appends line, following key after, to the opaque lines of p
+func (p Properties) addOpaque(line, after string) {
+	s := p.side()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opaque = append(s.opaque, opaqueLine{text: line, after: after})
+}
@@ -0,0 +1,90 @@
+package gestalt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPassThrough(t *testing.T) {
+	spec := `
+[vendor:section]
+a = 1
+%include extra.conf  # vendor include
+m[:] = x:1, y
+ab
+b[] = p, q
+`
+	if _, e := LoadStr(spec); e == nil {
+		t.Errorf("TestPassThrough - LoadStr - error expected without PassThrough")
+	}
+
+	p, e := LoadStr(spec, PassThrough())
+	if e != nil {
+		t.Fatalf("TestPassThrough - LoadStr(PassThrough()) - %s", e)
+	}
+	expected := []string{"[vendor:section]", "%include extra.conf", "m[:] = x:1, y", "ab"}
+	if v := p.Opaque(); fmt.Sprintf("%q", v) != fmt.Sprintf("%q", expected) {
+		t.Errorf("TestPassThrough - Opaque() - expected: %q, got: %q", expected, v)
+	}
+	if v := p.GetString("a"); v != "1" {
+		t.Errorf("TestPassThrough - GetString(a) - expected: 1, got: %s", v)
+	}
+	if v := p.Keys(); fmt.Sprint(v) != "[a b[]]" {
+		t.Errorf("TestPassThrough - Keys() - expected: [a b[]], got: %v", v)
+	}
+	if len(p) != 2 || strings.Contains(p.String(), "vendor") {
+		t.Errorf("TestPassThrough - opaque lines are not keys - got: %s", p)
+	}
+
+	var buf bytes.Buffer
+	if e := p.Store(&buf); e != nil {
+		t.Fatalf("TestPassThrough - Store - %s", e)
+	}
+	stored := "[vendor:section]\na = 1\n%include extra.conf\nm[:] = x:1, y\nab\nb[] = p, q\n"
+	if buf.String() != stored {
+		t.Errorf("TestPassThrough - Store - expected:\n%s\ngot:\n%s", stored, buf.String())
+	}
+
+	// round trip
+	rp, e := LoadStr(buf.String(), PassThrough())
+	if e != nil || fmt.Sprintf("%q", rp.Opaque()) != fmt.Sprintf("%q", expected) || rp.GetString("a") != "1" {
+		t.Errorf("TestPassThrough - round trip - got: %v, %v", rp, e)
+	}
+	ip, e := p.Interpolate()
+	if e != nil || len(ip.Opaque()) != len(expected) {
+		t.Errorf("TestPassThrough - Interpolate - expected opaque lines preserved, got: %v, %v", ip, e)
+	}
+	p.PreResolve()
+	if len(p.Opaque()) != len(expected) || len(p) != 2 {
+		t.Errorf("TestPassThrough - PreResolve - expected opaque lines preserved")
+	}
+}
+
+func TestPassThroughMerged(t *testing.T) {
+	p, _ := LoadStr("a = 1\n[one]\n", PassThrough())
+	q, _ := LoadStr("b = 2\n[two]\n", PassThrough())
+	p.Copy(q, true)
+	if v := p.Opaque(); fmt.Sprint(v) != "[[one] [two]]" {
+		t.Errorf("TestPassThroughMerged - Copy - expected: [[one] [two]], got: %q", v)
+	}
+
+	dir := writeFragments(t, map[string]string{
+		"00a.conf": "a = 1\n[one]\n",
+		"99z.conf": "[two]\nb = 2\n",
+	})
+	dp, e := LoadDir(context.Background(), dir, PassThrough())
+	if e != nil {
+		t.Fatalf("TestPassThroughMerged - LoadDir - %s", e)
+	}
+	if v := dp.Opaque(); fmt.Sprint(v) != "[[one] [two]]" {
+		t.Errorf("TestPassThroughMerged - LoadDir - expected: [[one] [two]], got: %q", v)
+	}
+	var buf bytes.Buffer
+	dp.Store(&buf)
+	if stored := "[two]\na = 1\n[one]\nb = 2\n"; buf.String() != stored {
+		t.Errorf("TestPassThroughMerged - Store - expected:\n%s\ngot:\n%s", stored, buf.String())
+	}
+}
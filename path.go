@@ -0,0 +1,82 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PathCheck verifies a (cleaned) path of GetPath.
+type PathCheck func(path string) error
+
+// PathExists verifies that the path exists.
+var PathExists PathCheck = func(path string) error {
+	_, e := statFile(path)
+	return e
+}
+
+// PathIsDir verifies that the path is an existing directory.
+var PathIsDir PathCheck = func(path string) error {
+	fi, e := statFile(path)
+	if e != nil {
+		return e
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	return nil
+}
+
+// PathWritable verifies that the path is an existing, writable file, or a
+// directory in which files can be created.
+var PathWritable PathCheck = func(path string) error {
+	fi, e := statFile(path)
+	if e != nil {
+		return e
+	}
+	if fi.IsDir() {
+		return checkCreate(path)
+	}
+	return checkWrite(path)
+}
+
+// File path value property - returns the value of key with a leading ~
+// expanded to the user's home directory, and $VAR or ${VAR} expanded to
+// the value of environment variable VAR, cleaned (see filepath.Clean).
+//
+// Returns error if no such key, or the path fails any of the checks, e.g.
+//
+//	dir, e := p.GetPath("data.dir", PathIsDir, PathWritable)
+func (p Properties) GetPath(key string, checks ...PathCheck) (string, error) {
+	path, e := p.stringValue(key)
+	if e != nil {
+		return "", e
+	}
+	if path, e = ExpandPath(path, checks...); e != nil {
+		return "", fmt.Errorf("key <%s> - %s", key, e)
+	}
+	return path, nil
+}
+
+// Expands and checks path, per GetPath.
+func ExpandPath(path string, checks ...PathCheck) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, e := userHomeDir()
+		if e != nil {
+			return "", e
+		}
+		path = home + path[1:]
+	}
+	path = filepath.Clean(expandEnv(path))
+
+	for _, check := range checks {
+		if e := check(path); e != nil {
+			return "", e
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,43 @@
+package gestalt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.log")
+	if e := os.WriteFile(file, nil, 0644); e != nil {
+		t.Fatal(e)
+	}
+	os.Setenv("GESTALT_TEST_DIR", dir)
+	defer os.Unsetenv("GESTALT_TEST_DIR")
+	home, _ := os.UserHomeDir()
+
+	p, _ := LoadStr(`
+data.dir = ${GESTALT_TEST_DIR}/./sub/..
+log.file = $GESTALT_TEST_DIR/app.log
+home.dir = ~/gestalt
+missing = ${GESTALT_TEST_DIR}/nope
+`)
+	if v, e := p.GetPath("data.dir", PathIsDir, PathWritable); e != nil || v != dir {
+		t.Errorf("TestGetPath - GetPath(data.dir) - expected: %s, got: %s, %v", dir, v, e)
+	}
+	if v, e := p.GetPath("log.file", PathExists, PathWritable); e != nil || v != file {
+		t.Errorf("TestGetPath - GetPath(log.file) - expected: %s, got: %s, %v", file, v, e)
+	}
+	if _, e := p.GetPath("log.file", PathIsDir); e == nil {
+		t.Errorf("TestGetPath - GetPath(log.file, PathIsDir) - error expected")
+	}
+	if v, e := p.GetPath("home.dir"); e != nil || v != filepath.Join(home, "gestalt") {
+		t.Errorf("TestGetPath - GetPath(home.dir) - got: %s, %v", v, e)
+	}
+	if _, e := p.GetPath("missing"); e != nil {
+		t.Errorf("TestGetPath - GetPath(missing) - unexpected error: %s", e)
+	}
+	if _, e := p.GetPath("missing", PathExists); e == nil {
+		t.Errorf("TestGetPath - GetPath(missing, PathExists) - error expected")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ----------------------------------------------------------------------
+// Policies
+//
+// org-wide rules, e.g. "tls must not be disabled in prod", are enforced
+// by a Policy, which is evaluated over the (merged) Properties of loads
+// (see EnforcePolicy), of each load and reload of a Watcher, in addition
+// to its Validators (see WithPolicy), and of loaders of the Enforcing
+// middleware.
+//
+// Rule engines (e.g. OPA/rego) are adapted by implementing Policy, e.g.
+// evaluating a query with the nested map of the Properties (see ToMap) as
+// input, and returning a Violation per result.
+// ----------------------------------------------------------------------
+
+// ErrPolicyViolation is the error of Properties violating a Policy.
+var ErrPolicyViolation = errors.New("policy violation")
+
+// Violation is a violation of a rule of a Policy, optionally of a key.
+type Violation struct {
+	Rule string
+	Key  string // of the violating value, or ""
+	Msg  string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s <%s> - %s", ErrPolicyViolation, v.Rule, v.Msg)
+}
+
+func (v Violation) Unwrap() error {
+	return ErrPolicyViolation
+}
+
+// Policy evaluates Properties, returning their violations of its rules,
+// or error if the evaluation fails.
+type Policy interface {
+	Evaluate(ctx context.Context, p Properties) ([]Violation, error)
+}
+
+// PolicyFunc is a Policy function.
+type PolicyFunc func(ctx context.Context, p Properties) ([]Violation, error)
+
+func (f PolicyFunc) Evaluate(ctx context.Context, p Properties) ([]Violation, error) {
+	return f(ctx, p)
+}
+
+// Returns a Policy of the named rule, violated by keys for which check
+// returns a non-empty message, e.g.
+//
+//	gestalt.Rule("tls-in-prod", func(p gestalt.Properties) map[string]string {
+//		if p.GetString("env") == "prod" && p.GetString("tls.enabled") == "false" {
+//			return map[string]string{"tls.enabled": "tls must not be disabled in prod"}
+//		}
+//		return nil
+//	})
+func Rule(name string, check func(p Properties) map[string]string) Policy {
+	return PolicyFunc(func(ctx context.Context, p Properties) ([]Violation, error) {
+		var violations []Violation
+		msgs := check(p)
+		for _, k := range sortedKeys(msgs) {
+			if msgs[k] != empty {
+				violations = append(violations, Violation{Rule: name, Key: k, Msg: msgs[k]})
+			}
+		}
+		return violations, nil
+	})
+}
+
+// Returns a Policy of all of policies, evaluated in order.
+func Policies(policies ...Policy) Policy {
+	return PolicyFunc(func(ctx context.Context, p Properties) ([]Violation, error) {
+		var violations []Violation
+		for _, policy := range policies {
+			vs, e := policy.Evaluate(ctx, p)
+			if e != nil {
+				return nil, e
+			}
+			violations = append(violations, vs...)
+		}
+		return violations, nil
+	})
+}
+
+// EnforcePolicy rejects loaded Properties violating policy, e.g. of Load
+// or LoadDir, with the error of CheckPolicy. The policy is evaluated over
+// the complete Properties, i.e. of all fragments of LoadDir, and after
+// their keys are derived (see Derive).
+func EnforcePolicy(policy Policy) LoadOption {
+	return func(o *loadOptions) {
+		o.policy = policy
+	}
+}
+
+// Evaluates p per policy (nil policy has no rules). Returns the
+// violations, as errors of ErrPolicyViolation joined, with violations of
+// keys as *KeyErrors, or the error of the evaluation.
+func CheckPolicy(ctx context.Context, policy Policy, p Properties) error {
+	if policy == nil {
+		return nil
+	}
+	violations, e := policy.Evaluate(ctx, p)
+	if e != nil {
+		return fmt.Errorf("policy evaluation failed - %w", e)
+	}
+	var errs []error
+	for _, v := range violations {
+		if v.Key != empty {
+			errs = append(errs, &KeyError{v.Key, v})
+		} else {
+			errs = append(errs, v)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Enforcing rejects loaded Properties violating policy. See CheckPolicy.
+func Enforcing(policy Policy) LoadMiddleware {
+	return func(next Loader) Loader {
+		return LoaderFunc(func(ctx context.Context, name string) (Properties, error) {
+			p, e := next.Load(ctx, name)
+			if e != nil {
+				return nil, e
+			}
+			if e := CheckPolicy(ctx, policy, p); e != nil {
+				return nil, e
+			}
+			return p, nil
+		})
+	}
+}
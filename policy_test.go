@@ -0,0 +1,89 @@
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var tlsInProd = Rule("tls-in-prod", func(p Properties) map[string]string {
+	if p.GetString("env") == "prod" && p.GetString("tls.enabled") == "false" {
+		return map[string]string{"tls.enabled": "tls must not be disabled in prod"}
+	}
+	return nil
+})
+
+func TestCheckPolicy(t *testing.T) {
+	ctx := context.Background()
+	p := Properties{"env": "prod", "tls.enabled": "false"}
+	e := CheckPolicy(ctx, tlsInProd, p)
+	var ke *KeyError
+	if !errors.Is(e, ErrPolicyViolation) || !errors.As(e, &ke) || ke.Key != "tls.enabled" {
+		t.Errorf("TestCheckPolicy - CheckPolicy - expected KeyError of tls.enabled, got: %v", e)
+	}
+	if e.Error() != "key <tls.enabled> - policy violation <tls-in-prod> - tls must not be disabled in prod" {
+		t.Errorf("TestCheckPolicy - CheckPolicy - unexpected message: %s", e)
+	}
+	if e := CheckPolicy(ctx, tlsInProd, Properties{"env": "dev", "tls.enabled": "false"}); e != nil {
+		t.Errorf("TestCheckPolicy - CheckPolicy(dev) - unexpected error: %s", e)
+	}
+
+	failing := PolicyFunc(func(ctx context.Context, p Properties) ([]Violation, error) {
+		return nil, errors.New("engine down")
+	})
+	if e := CheckPolicy(ctx, Policies(tlsInProd, failing), p); e == nil || errors.Is(e, ErrPolicyViolation) {
+		t.Errorf("TestCheckPolicy - CheckPolicy - expected evaluation error, got: %v", e)
+	}
+	if e := CheckPolicy(ctx, nil, p); e != nil {
+		t.Errorf("TestCheckPolicy - CheckPolicy(nil) - expected no policy, got: %v", e)
+	}
+
+	l := Chain(LoaderFunc(func(ctx context.Context, name string) (Properties, error) {
+		return p.Clone(), nil
+	}), Enforcing(tlsInProd))
+	if _, e := l.Load(ctx, "app"); !errors.Is(e, ErrPolicyViolation) {
+		t.Errorf("TestCheckPolicy - Enforcing - expected violation, got: %v", e)
+	}
+}
+
+func TestWatchPolicy(t *testing.T) {
+	var n int32
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		if atomic.AddInt32(&n, 1) == 1 {
+			return Properties{"env": "prod", "tls.enabled": "true"}, nil
+		}
+		return Properties{"env": "prod", "tls.enabled": "false"}, nil
+	})
+	events := make(chan error, 1)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) {
+		events <- e
+	}, WithPolicy(tlsInProd))
+	if e != nil {
+		t.Fatalf("TestWatchPolicy - Watch - %s", e)
+	}
+	defer w.Stop()
+	w.Reload()
+	if e := <-events; !errors.Is(e, ErrRejected) || !errors.Is(e, ErrPolicyViolation) || !strings.Contains(e.Error(), "by policy") {
+		t.Errorf("TestWatchPolicy - Reload - expected policy rejection, got: %v", e)
+	}
+	if v := w.Properties().GetString("tls.enabled"); v != "true" {
+		t.Errorf("TestWatchPolicy - Properties after rejection - expected: true, got: %s", v)
+	}
+}
+
+func TestEnforcePolicy(t *testing.T) {
+	spec := "env = prod\ntls.enabled = false\n"
+	if _, e := LoadStr(spec, EnforcePolicy(tlsInProd)); !errors.Is(e, ErrPolicyViolation) {
+		t.Errorf("TestEnforcePolicy - LoadStr - expected violation, got: %v", e)
+	}
+	if _, e := LoadStr(spec); e != nil {
+		t.Errorf("TestEnforcePolicy - LoadStr without policy - unexpected error: %s", e)
+	}
+
+	dir := writeFragments(t, map[string]string{"10-env.conf": "env = prod\n", "20-tls.conf": "tls.enabled = false\n"})
+	if _, e := LoadDir(context.Background(), dir, EnforcePolicy(tlsInProd)); !errors.Is(e, ErrPolicyViolation) {
+		t.Errorf("TestEnforcePolicy - LoadDir - expected violation of the merged fragments, got: %v", e)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+// Returns the Properties of the given keys of p, e.g. of the keys a third
+// party library is entitled to see. Keys not defined by p are ignored.
+// Values are copies of those of p (so the projection may be handed out, and
+// modified, without affecting p), and retain their windows (see splitWindow).
+func (p Properties) Project(keys ...string) Properties {
+	want := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		want[k] = p.defined(k)
+	}
+	return p.project(func(k string) bool { return want[k] })
+}
+
+// returns the Properties of the (plain) keys of p selected by fn
+func (p Properties) project(fn func(key string) bool) Properties {
+	proj := make(Properties)
+	for k, v := range p {
+		if fn(plainKey(k)) {
+			proj[k] = copyValue(v)
+		}
+	}
+	proj.adopt(p, func(k string) (string, bool) { return k, fn(plainKey(k)) }, false)
+	return proj
+}
+
+// Returns the Properties of the keys of p matching any of the glob patterns
+// (see CompileGlob), e.g. "smtp.*", as Project. Note that `*` matches `.`,
+// and that the suffixes of array and map keys are matched with classes,
+// e.g. "hosts[[]?" or "hosts*". Returns error if a pattern is malformed.
+func (p Properties) ProjectGlob(patterns ...string) (Properties, error) {
+	globs := make([]*Glob, len(patterns))
+	for i, pattern := range patterns {
+		g, e := CompileGlob(pattern)
+		if e != nil {
+			return nil, e
+		}
+		globs[i] = g
+	}
+	return p.project(func(k string) bool { return MatchAny(globs, k) && p.defined(k) }), nil
+}
+
+// returns a copy of v, of array and map values, or v
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []string:
+		return append([]string(nil), v...)
+	case map[string]string:
+		mapv := make(map[string]string, len(v))
+		for mk, mv := range v {
+			mapv[mk] = mv
+		}
+		return mapv
+	}
+	return v
+}
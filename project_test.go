@@ -0,0 +1,57 @@
+package gestalt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	p, _ := LoadStr(`
+smtp.host = mx
+smtp.tls.cert = cert.pem
+smtp.hosts[] = a, b
+db.password = secret
+gone = @unset
+`)
+	proj := p.Project("smtp.host", "smtp.hosts[]", "gone", "nope")
+	if v := proj.Keys(); !reflect.DeepEqual(v, []string{"smtp.host", "smtp.hosts[]"}) {
+		t.Errorf("TestProject - Project - expected: [smtp.host smtp.hosts[]], got: %v", v)
+	}
+	if v := proj.GetArray("smtp.hosts[]"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("TestProject - Project - GetArray(smtp.hosts[]) - expected: [a b], got: %v", v)
+	}
+
+	for _, c := range []struct {
+		patterns []string
+		expected []string
+	}{
+		{[]string{"smtp.*"}, []string{"smtp.host", "smtp.hosts[]", "smtp.tls.cert"}},
+		{[]string{"smtp.host", "db.*"}, []string{"db.password", "smtp.host"}},
+		{[]string{"smtp.hosts[[]?"}, []string{"smtp.hosts[]"}},
+		{[]string{"gone", "imap.*"}, []string{}},
+	} {
+		proj, e := p.ProjectGlob(c.patterns...)
+		if e != nil {
+			t.Fatalf("TestProject - ProjectGlob(%v) - %s", c.patterns, e)
+		}
+		if v := proj.Keys(); !reflect.DeepEqual(v, c.expected) {
+			t.Errorf("TestProject - ProjectGlob(%v) - expected: %v, got: %v", c.patterns, c.expected, v)
+		}
+	}
+	if _, e := p.ProjectGlob("smtp.[a"); e == nil {
+		t.Errorf("TestProject - ProjectGlob(smtp.[a) - error expected")
+	}
+
+	mp, _ := LoadStr("hosts[] = a, b\nm[:] = k:v\n")
+	proj = mp.Project("hosts[]", "m[:]")
+	proj.GetArray("hosts[]")[0] = "x"
+	proj.GetMap("m[:]")["k"] = "x"
+	if mp.GetArray("hosts[]")[0] != "a" || mp.GetMap("m[:]")["k"] != "v" {
+		t.Errorf("TestProject - Project - expected values copied, got: %v", mp)
+	}
+	gp, _ := mp.ProjectGlob("*")
+	gp.GetArray("hosts[]")[0] = "x"
+	if mp.GetArray("hosts[]")[0] != "a" {
+		t.Errorf("TestProject - ProjectGlob - expected values copied, got: %v", mp)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"fmt"
+)
+
+// RedisClient is the subset of a redis client used by the redis provider.
+// Clients of choice (e.g. go-redis, redigo) are easily adapted to it.
+type RedisClient interface {
+	// returns all fields of the hash at key (HGETALL)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	// subscribes to channel (SUBSCRIBE). The returned channel delivers the
+	// published messages and must be closed when ctx is done.
+	Subscribe(ctx context.Context, channel string) (<-chan string, error)
+}
+
+// Returns the keyspace notification channel of key in database db.
+// Note that keyspace notifications must be enabled on the server
+// (e.g. notify-keyspace-events "Kh").
+func KeyspaceChannel(db int, key string) string {
+	return fmt.Sprintf("__keyspace@%d__:%s", db, key)
+}
+
+// Instantiates a new Properties object from the fields of the redis hash
+// at hashKey. Field names are keys, and field values are parsed per key
+// type and gestalt file syntax.
+func LoadRedis(client RedisClient, hashKey string) (Properties, error) {
+	return RedisSource(client, hashKey).Load(context.Background())
+}
+
+// Returns a Source loading the redis hash at hashKey. See LoadRedis.
+func RedisSource(client RedisClient, hashKey string) Source {
+	return SourceFunc(func(ctx context.Context) (Properties, error) {
+		fields, e := client.HGetAll(ctx, hashKey)
+		if e != nil {
+			return nil, e
+		}
+		p := make(Properties, len(fields))
+		for k, vrep := range fields {
+			v, e := parseValue(k, vrep)
+			if e != nil {
+				return nil, &KeyError{k, e}
+			}
+			p[k] = v
+		}
+		return p, nil
+	})
+}
+
+// Watches the redis hash at hashKey, reloading it on every message published
+// on channel, e.g. the hash's KeyspaceChannel or an application channel.
+// See Watch for the semantics of fn.
+func WatchRedis(ctx context.Context, client RedisClient, hashKey string, channel string, fn ChangeFunc) (*Watcher, error) {
+	w, e := Watch(ctx, RedisSource(client, hashKey), 0, fn)
+	if e != nil {
+		return nil, e
+	}
+
+	subctx, cancel := context.WithCancel(ctx)
+	msgs, e := client.Subscribe(subctx, channel)
+	if e != nil {
+		cancel()
+		w.Stop()
+		return nil, e
+	}
+	go func() {
+		<-w.done
+		cancel()
+	}()
+	go func() {
+		for range msgs {
+			w.Reload()
+		}
+	}()
+	return w, nil
+}
@@ -0,0 +1,80 @@
+package gestalt
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// an in-memory RedisClient
+type fakeRedis struct {
+	mu     sync.Mutex
+	hashes map[string]map[string]string
+	subs   []chan string
+}
+
+func (r *fakeRedis) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fields := make(map[string]string)
+	for k, v := range r.hashes[key] {
+		fields[k] = v
+	}
+	return fields, nil
+}
+
+func (r *fakeRedis) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch := make(chan string, 1)
+	r.subs = append(r.subs, ch)
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		close(ch)
+		r.subs = nil
+	}()
+	return ch, nil
+}
+
+func (r *fakeRedis) hset(key, field, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hashes[key][field] = value
+	for _, ch := range r.subs {
+		ch <- "hset"
+	}
+}
+
+func TestLoadRedis(t *testing.T) {
+	client := &fakeRedis{hashes: map[string]map[string]string{
+		"app:config": {"debug": "false", "hosts[]": "a, b"},
+	}}
+	p, e := LoadRedis(client, "app:config")
+	if e != nil {
+		t.Fatalf("TestLoadRedis - LoadRedis - %s", e)
+	}
+	if v := p.GetArray("hosts[]"); len(v) != 2 {
+		t.Errorf("TestLoadRedis - GetArray(hosts[]) - expected: [a b], got: %s", v)
+	}
+
+	changes := make(chan Properties, 1)
+	w, e := WatchRedis(context.Background(), client, "app:config", KeyspaceChannel(0, "app:config"),
+		func(p Properties, e error) { changes <- p })
+	if e != nil {
+		t.Fatalf("TestLoadRedis - WatchRedis - %s", e)
+	}
+	defer w.Stop()
+
+	client.hset("app:config", "debug", "true")
+	select {
+	case p := <-changes:
+		if v := p.GetString("debug"); v != "true" {
+			t.Errorf("TestLoadRedis - change - expected: debug=true, got: %s", v)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("TestLoadRedis - change notification expected")
+	}
+}
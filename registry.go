@@ -0,0 +1,135 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Holder holds current Properties, e.g. a SafeProperties or a Watcher.
+type Holder interface {
+	Properties() Properties
+}
+
+// Registry is a set of named stores (Holders) of Properties, e.g. "app",
+// "logging", and "secrets", so that components retrieve the stores of
+// their concern by name (see Store) instead of being passed a single
+// all-encompassing Properties object.
+//
+// Registry is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	stores map[string]Holder
+}
+
+// Instantiates a new, empty, Registry.
+func NewRegistry() *Registry {
+	return &Registry{stores: make(map[string]Holder)}
+}
+
+// Registers store h as name. Returns error if name is already registered.
+func (r *Registry) Register(name string, h Holder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.stores[name]; ok {
+		return fmt.Errorf("store <%s> is already registered", name)
+	}
+	r.stores[name] = h
+	return nil
+}
+
+// Returns the store registered as name, if any.
+func (r *Registry) Lookup(name string) (Holder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.stores[name]
+	return h, ok
+}
+
+// Returns the current Properties of the store registered as name, or
+// nil (i.e. empty Properties) if none. The returned object must not be
+// modified.
+func (r *Registry) Store(name string) Properties {
+	if h, ok := r.Lookup(name); ok {
+		return h.Properties()
+	}
+	return nil
+}
+
+// Returns the names of the registered stores, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sortedKeys(r.stores)
+}
+
+// Unregisters the store registered as name (if any) and releases it, i.e.
+// stops it if it is a Watcher (or any store with a Stop method), or closes
+// it if it has a Close method.
+func (r *Registry) Unregister(name string) error {
+	r.mu.Lock()
+	h, ok := r.stores[name]
+	delete(r.stores, name)
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return release(h)
+}
+
+// Unregisters and releases all stores. See Unregister.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	stores := r.stores
+	r.stores = make(map[string]Holder)
+	r.mu.Unlock()
+
+	var errs []error
+	for _, name := range sortedKeys(stores) {
+		if e := release(stores[name]); e != nil {
+			errs = append(errs, fmt.Errorf("store <%s> - %w", name, e))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func release(h Holder) error {
+	switch h := h.(type) {
+	case interface{ Stop() }:
+		h.Stop()
+	case interface{ Close() error }:
+		return h.Close()
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// default registry
+// ----------------------------------------------------------------------
+
+// DefaultRegistry is the Registry of the package level functions
+// Register, Store, and Unregister.
+var DefaultRegistry = NewRegistry()
+
+// Registers store h as name in the DefaultRegistry.
+func Register(name string, h Holder) error {
+	return DefaultRegistry.Register(name, h)
+}
+
+// Returns the current Properties of the store registered as name in the
+// DefaultRegistry, e.g.
+//
+//	level := gestalt.Store("logging").GetStringOrDefault("level", "info")
+func Store(name string) Properties {
+	return DefaultRegistry.Store(name)
+}
+
+// Unregisters (and releases) the store registered as name in the
+// DefaultRegistry.
+func Unregister(name string) error {
+	return DefaultRegistry.Unregister(name)
+}
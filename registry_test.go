@@ -0,0 +1,63 @@
+package gestalt
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	app := NewSafeProperties(Properties{"name": "gestalt"})
+	w, e := Watch(context.Background(), SourceFunc(func(ctx context.Context) (Properties, error) {
+		return Properties{"level": "debug"}, nil
+	}), 0, nil)
+	if e != nil {
+		t.Fatalf("TestRegistry - Watch - %s", e)
+	}
+
+	if e := r.Register("app", app); e != nil {
+		t.Fatalf("TestRegistry - Register(app) - %s", e)
+	}
+	if e := r.Register("logging", w); e != nil {
+		t.Fatalf("TestRegistry - Register(logging) - %s", e)
+	}
+	if e := r.Register("app", app); e == nil || !strings.Contains(e.Error(), "already registered") {
+		t.Errorf("TestRegistry - Register(app) again - expected error, got: %v", e)
+	}
+
+	if v := r.Store("app").GetString("name"); v != "gestalt" {
+		t.Errorf("TestRegistry - Store(app) - expected: gestalt, got: %s", v)
+	}
+	if v := r.Store("logging").GetString("level"); v != "debug" {
+		t.Errorf("TestRegistry - Store(logging) - expected: debug, got: %s", v)
+	}
+	if p := r.Store("secrets"); p != nil {
+		t.Errorf("TestRegistry - Store(secrets) - expected: nil, got: %v", p)
+	}
+	if names := r.Names(); strings.Join(names, ",") != "app,logging" {
+		t.Errorf("TestRegistry - Names() - expected: app,logging, got: %v", names)
+	}
+
+	if e := r.Close(); e != nil {
+		t.Errorf("TestRegistry - Close - %s", e)
+	}
+	select {
+	case <-w.done:
+	default:
+		t.Errorf("TestRegistry - Close - expected stopped Watcher")
+	}
+	if _, ok := r.Lookup("app"); ok {
+		t.Errorf("TestRegistry - Lookup(app) after Close - expected none")
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	if e := Register("test.app", NewSafeProperties(Properties{"name": "gestalt"})); e != nil {
+		t.Fatalf("TestDefaultRegistry - Register - %s", e)
+	}
+	defer Unregister("test.app")
+	if v := Store("test.app").GetString("name"); v != "gestalt" {
+		t.Errorf("TestDefaultRegistry - Store - expected: gestalt, got: %s", v)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+
+package gestalt
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+// ----------------------------------------------------------------------
+// Windows registry provider
+//
+// a registry key subtree is mapped to properties as follows:
+//
+// • each value defines a key named by its (sub)key path relative to the
+// subtree root and the value name, joined with `.`, with `\` replaced by
+// `.` as well. The default (unnamed) value of a key is named by its path.
+//
+// • REG_SZ and REG_EXPAND_SZ values are strings (unexpanded), REG_DWORD
+// and REG_QWORD values are decimal strings, and REG_MULTI_SZ values are
+// arrays, with the `[]` suffix added to the key. Other kinds are ignored.
+// ----------------------------------------------------------------------
+
+var procRegEnumValueW = modadvapi32.NewProc("RegEnumValueW")
+
+const errorNoMoreItems syscall.Errno = 259
+
+// Instantiates a new Properties object from the registry key subtree at
+// path of the predefined key root, e.g. syscall.HKEY_LOCAL_MACHINE and
+// `SOFTWARE\Policies\Acme\App`.
+func LoadRegistry(root syscall.Handle, path string) (Properties, error) {
+	p := make(Properties)
+	if e := loadRegistryKey(p, root, path, ""); e != nil {
+		return nil, fmt.Errorf("Error reading registry key <%s> : %s", path, e)
+	}
+	return p, nil
+}
+
+// Returns a Source loading the registry key subtree. See LoadRegistry.
+func RegistrySource(root syscall.Handle, path string) Source {
+	return SourceFunc(func(ctx context.Context) (Properties, error) {
+		return LoadRegistry(root, path)
+	})
+}
+
+func loadRegistryKey(p Properties, parent syscall.Handle, path string, prefix string) error {
+	var key syscall.Handle
+	subkey, e := syscall.UTF16PtrFromString(path)
+	if e != nil {
+		return e
+	}
+	if e := syscall.RegOpenKeyEx(parent, subkey, 0, syscall.KEY_READ, &key); e != nil {
+		return e
+	}
+	defer syscall.RegCloseKey(key)
+
+	var nsubkeys, maxSubkeyLen, nvalues, maxValueNameLen uint32
+	if e := syscall.RegQueryInfoKey(key, nil, nil, nil, &nsubkeys, &maxSubkeyLen, nil, &nvalues, &maxValueNameLen, nil, nil, nil); e != nil {
+		return e
+	}
+
+	name := make([]uint16, maxValueNameLen+1)
+	for i := uint32(0); i < nvalues; i++ {
+		n := uint32(len(name))
+		r, _, _ := procRegEnumValueW.Call(uintptr(key), uintptr(i), uintptr(unsafe.Pointer(&name[0])), uintptr(unsafe.Pointer(&n)), 0, 0, 0, 0)
+		if errno := syscall.Errno(r); errno == errorNoMoreItems {
+			break
+		} else if errno != 0 {
+			return errno
+		}
+		vname := syscall.UTF16ToString(name[:n])
+		k := prefix + vname
+		switch {
+		case vname == "" && prefix == "":
+			continue // default value of root
+		case vname == "":
+			k = prefix[:len(prefix)-1]
+		}
+		if e := loadRegistryValue(p, key, &name[0], k); e != nil {
+			return &KeyError{k, e}
+		}
+	}
+
+	subname := make([]uint16, maxSubkeyLen+1)
+	for i := uint32(0); i < nsubkeys; i++ {
+		n := uint32(len(subname))
+		if e := syscall.RegEnumKeyEx(key, i, &subname[0], &n, nil, nil, nil, nil); e == errorNoMoreItems {
+			break
+		} else if e != nil {
+			return e
+		}
+		sub := syscall.UTF16ToString(subname[:n])
+		if e := loadRegistryKey(p, key, sub, prefix+sub+"."); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func loadRegistryValue(p Properties, key syscall.Handle, name *uint16, k string) error {
+	var kind, n uint32
+	if e := syscall.RegQueryValueEx(key, name, nil, &kind, nil, &n); e != nil {
+		return e
+	}
+	buf := make([]byte, n+2)
+	if e := syscall.RegQueryValueEx(key, name, nil, &kind, &buf[0], &n); e != nil {
+		return e
+	}
+	buf = buf[:n]
+
+	switch kind {
+	case syscall.REG_SZ, syscall.REG_EXPAND_SZ:
+		p[k] = utf16String(buf)
+	case syscall.REG_DWORD:
+		if len(buf) < 4 {
+			return fmt.Errorf("malformed REG_DWORD value")
+		}
+		p[k] = strconv.FormatUint(uint64(binary.LittleEndian.Uint32(buf)), 10)
+	case syscall.REG_QWORD:
+		if len(buf) < 8 {
+			return fmt.Errorf("malformed REG_QWORD value")
+		}
+		p[k] = strconv.FormatUint(binary.LittleEndian.Uint64(buf), 10)
+	case syscall.REG_MULTI_SZ:
+		arrv := []string{}
+		u := utf16LE(buf)
+		for len(u) > 0 && u[0] != 0 {
+			i := 0
+			for i < len(u) && u[i] != 0 {
+				i++
+			}
+			arrv = append(arrv, string(utf16.Decode(u[:i])))
+			if i == len(u) {
+				break
+			}
+			u = u[i+1:]
+		}
+		if !isArrayKey(k) {
+			k += array
+		}
+		p[k] = arrv
+	}
+	return nil
+}
+
+func utf16LE(b []byte) []uint16 {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return u
+}
+
+func utf16String(b []byte) string {
+	return syscall.UTF16ToString(utf16LE(b))
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package gestalt
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestLoadRegistry(t *testing.T) {
+	p, e := LoadRegistry(syscall.HKEY_LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`)
+	if e != nil {
+		t.Fatalf("TestLoadRegistry - LoadRegistry - %s", e)
+	}
+	if v := p.GetString("ProductName"); v == "" {
+		t.Errorf("TestLoadRegistry - GetString(ProductName) - expected: product name, got: <>")
+	}
+
+	if _, e := LoadRegistry(syscall.HKEY_LOCAL_MACHINE, `SOFTWARE\no\such\key`); e == nil {
+		t.Errorf("TestLoadRegistry - LoadRegistry - error expected for missing key")
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// value types of schema keys.
+// The type of array and map keys applies to each element (value).
+const (
+	TypeString    = "string"
+	TypeInt       = "int"
+	TypeBool      = "bool"
+	TypeDuration  = "duration"
+	TypeRegexp    = "regexp"
+	TypeGlob      = "glob"
+	TypeMediaType = "mediatype"
+	TypeCharset   = "charset"
+	TypeHostPort  = "hostport"
+	TypeLocation  = "location"
+	TypeSemver    = "semver"
+)
+
+// typed conversion tags of (non-string) schema value types
+var schemaTypes = map[string]byte{
+	TypeInt:       typed_int,
+	TypeBool:      typed_bool,
+	TypeDuration:  typed_duration,
+	TypeRegexp:    typed_regexp,
+	TypeGlob:      typed_glob,
+	TypeMediaType: typed_mediatype,
+	TypeCharset:   typed_charset,
+	TypeHostPort:  typed_hostport,
+	TypeLocation:  typed_location,
+	TypeSemver:    typed_semver,
+}
+
+// Schema describes the keys of a configuration.
+type Schema struct {
+	Keys []KeySpec
+	// if Strict, keys not described by the schema are invalid
+	Strict bool
+}
+
+// KeySpec describes a single key of a Schema.
+type KeySpec struct {
+	Key      string
+	Type     string // one of the Type constants - default is TypeString
+	Unit     string // unit of numeric values, e.g. "ms" (see GetQuantity)
+	Required bool
+	Secret   bool   // value is sensitive, e.g. a password
+	Default  string // value representation, per file syntax
+	Doc      string
+	// names of the layers that may override the key, or nil for any layer.
+	// See OverlayStack.Restrict.
+	Layers []string
+	// optional constraint on the (converted) value of the key.
+	// array and map values are passed as []interface{} and map[string]interface{},
+	// and values of keys with a Unit as float64, in the Unit.
+	Check func(v interface{}) error
+}
+
+// KeyError is the error of a specific key.
+type KeyError struct {
+	Key string
+	Err error
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("key <%s> - %s", e.Key, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// Returns the spec of key, or nil if not described by the schema.
+func (s *Schema) Spec(key string) *KeySpec {
+	for i := range s.Keys {
+		if s.Keys[i].Key == key {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// Returns true if key is described by the schema as Secret. See RedactedDiff.
+func (s *Schema) Sensitive(key string) bool {
+	spec := s.Spec(key)
+	return spec != nil && spec.Secret
+}
+
+// Validates p against the schema. Returns a *KeyError per invalid
+// key, or nil if p is valid.
+func (s *Schema) Validate(p Properties) (errs []error) {
+	for i := range s.Keys {
+		spec := &s.Keys[i]
+		v := p.get(spec.Key)
+		if v == nil {
+			if spec.Required {
+				errs = append(errs, &KeyError{spec.Key, fmt.Errorf("required key is missing")})
+			}
+			continue
+		}
+		if e := spec.validate(v); e != nil {
+			errs = append(errs, &KeyError{spec.Key, e})
+		}
+	}
+	if s.Strict {
+		for _, k := range sortedKeys(p) {
+			if p.get(k) != nil && s.Spec(plainKey(k)) == nil {
+				errs = append(errs, &KeyError{k, fmt.Errorf("key is not described by schema")})
+			}
+		}
+	}
+	return
+}
+
+// Returns a copy of p with the defaults of the schema applied to undefined
+// keys. Returns a *KeyError if a default is malformed.
+func (s *Schema) ApplyDefaults(p Properties) (Properties, error) {
+	p = p.Clone()
+	for i := range s.Keys {
+		spec := &s.Keys[i]
+		if spec.Default == "" || p.get(spec.Key) != nil {
+			continue
+		}
+		if e := p.override(spec.Key, spec.Default); e != nil {
+			return nil, &KeyError{spec.Key, e}
+		}
+	}
+	return p, nil
+}
+
+// validates the (resolved) value v of the spec'd key
+func (spec *KeySpec) validate(v interface{}) error {
+	tv, e := spec.convert(v)
+	if e != nil {
+		return e
+	}
+	if spec.Check != nil {
+		return spec.Check(tv)
+	}
+	return nil
+}
+
+// returns the (resolved) value v of the spec'd key converted per its type
+// and unit (see Check)
+func (spec *KeySpec) convert(v interface{}) (interface{}, error) {
+	conv := func(s string) (interface{}, error) { return s, nil }
+	if spec.Unit != "" {
+		conv = func(s string) (interface{}, error) { return new(getOptions).parseQuantity(s, spec.Unit) }
+	} else if spec.Type != "" && spec.Type != TypeString {
+		tag, ok := schemaTypes[spec.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown schema type <%s>", spec.Type)
+		}
+		conv = converters[tag]
+	}
+
+	var tv interface{}
+	switch v := v.(type) {
+	case []string:
+		arrv := make([]interface{}, len(v))
+		for i, av := range v {
+			var e error
+			if arrv[i], e = conv(av); e != nil {
+				return nil, fmt.Errorf("element %d - %s", i, e)
+			}
+		}
+		tv = arrv
+	case map[string]string:
+		mapv := make(map[string]interface{}, len(v))
+		for _, mk := range sortedKeys(v) {
+			var e error
+			if mapv[mk], e = conv(v[mk]); e != nil {
+				return nil, fmt.Errorf("map key <%s> - %s", mk, e)
+			}
+		}
+		tv = mapv
+	default:
+		var e error
+		if tv, e = conv(v.(string)); e != nil {
+			return nil, e
+		}
+	}
+	return tv, nil
+}
+
+// Instantiates a new Schema from the specified schema file.
+//
+// A schema file is a multi-document file (see LoadAll) with one document
+// per key, named by the key. Documents define the (optional) properties
+// type, unit, required, secret, layers, default, and doc of their key. For
+// example:
+//
+//	[document:db.port]
+//	type = int
+//	required = true
+//	layers[] = defaults, file
+//	default = 5432
+//	doc = port of the database server
+//
+// Values of keys with a unit (see GetQuantity) are numbers, in the unit
+// unless given with a unit of the same dimension.
+//
+// Keys are ordered by name.
+func LoadSchema(filename string) (*Schema, error) {
+	return loadSchema(OSFileSystem, filename)
+}
+
+func loadSchema(fsys FileSystem, filename string) (*Schema, error) {
+	docs, e := LoadAll(filename, WithFileSystem(fsys))
+	if e != nil {
+		return nil, e
+	}
+	return newSchema(docs)
+}
+
+// ----------------------------------------------------------------------
+// Schema directive
+//
+// a `@schema <path>` line, before any other (non-comment) line of a file,
+// names the schema of the file, e.g.
+//
+//	# database settings
+//	@schema schemas/db.gs
+//	db.host = localhost
+//
+// Load (and LoadStr) load the schema and validate the Properties against
+// it. Relative paths are relative to the directory of the file (or the
+// working directory, for LoadStr).
+// ----------------------------------------------------------------------
+
+const schema_directive = "@schema"
+
+// returns the path of the schema directive of s, if any, and s without the
+// directive (with line numbers retained).
+func schemaDirective(s string) (path string, rest string, ok bool) {
+	for off := 0; off < len(s); {
+		line, _, _ := strings.Cut(s[off:], "\n")
+		tline := strings.Trim(line, trimset)
+		if tline == empty || tline[0] == comment {
+			off += len(line) + 1
+			continue
+		}
+		if !strings.HasPrefix(tline, schema_directive+" ") {
+			return "", s, false
+		}
+		path = strings.Trim(tline[len(schema_directive):], ws)
+		return path, s[:off] + s[off+len(line):], true
+	}
+	return "", s, false
+}
+
+// loads s, per a schema directive (validated against the schema)
+func (o *loadOptions) loadWithSchema(path string, s string) (Properties, error) {
+	if !filepath.IsAbs(path) && o.dir != empty {
+		path = filepath.Join(o.dir, path)
+	}
+	schema, e := loadSchema(o.fsys, path)
+	if e != nil {
+		return nil, fmt.Errorf("schema directive - %w", e)
+	}
+	p, e := loadBuffer(s, o)
+	if e != nil {
+		return nil, e
+	}
+	if errs := schema.Validate(p); len(errs) > 0 {
+		return nil, fmt.Errorf("schema <%s> - %w", path, errors.Join(errs...))
+	}
+	return p, nil
+}
+
+// Support embedded schema specs. See LoadSchema.
+func LoadSchemaStr(spec string) (*Schema, error) {
+	docs, e := LoadAllStr(spec)
+	if e != nil {
+		return nil, e
+	}
+	return newSchema(docs)
+}
+
+func newSchema(docs map[string]Properties) (*Schema, error) {
+	s := &Schema{}
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		doc := docs[k]
+		spec := KeySpec{
+			Key:     k,
+			Type:    doc.GetStringOrDefault("type", TypeString),
+			Unit:    doc.GetString("unit"),
+			Default: doc.GetString("default"),
+			Doc:     doc.GetString("doc"),
+			Layers:  doc.GetArray("layers[]"),
+		}
+		if _, ok := schemaTypes[spec.Type]; !ok && spec.Type != TypeString {
+			return nil, &KeyError{k, fmt.Errorf("unknown schema type <%s>", spec.Type)}
+		}
+		if _, ok := units[spec.Unit]; !ok && spec.Unit != "" {
+			return nil, &KeyError{k, fmt.Errorf("unknown unit <%s>", spec.Unit)}
+		}
+		for _, attr := range []struct {
+			name string
+			flag *bool
+		}{{"required", &spec.Required}, {"secret", &spec.Secret}} {
+			if r := doc.GetString(attr.name); r != "" {
+				var e error
+				if *attr.flag, e = strconv.ParseBool(r); e != nil {
+					return nil, &KeyError{k, e}
+				}
+			}
+		}
+		s.Keys = append(s.Keys, spec)
+	}
+	return s, nil
+}
@@ -0,0 +1,135 @@
+package gestalt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	prop, e := LoadStr(`
+db.host = localhost
+db.port = 54x32
+timeouts[:] = read:5s, write:10
+extra = foo
+routes[] = ^/api, ^/v(1
+`)
+	if e != nil {
+		t.Fatalf("TestSchemaValidate - LoadStr - %s", e)
+	}
+	schema := &Schema{
+		Strict: true,
+		Keys: []KeySpec{
+			{Key: "db.host", Required: true, Check: func(v interface{}) error {
+				if v.(string) == "" {
+					return fmt.Errorf("empty host")
+				}
+				return nil
+			}},
+			{Key: "db.port", Type: TypeInt},
+			{Key: "db.user", Required: true},
+			{Key: "timeouts[:]", Type: TypeDuration},
+			{Key: "routes[]", Type: TypeRegexp},
+		},
+	}
+
+	errs := schema.Validate(prop)
+	expected := map[string]bool{"db.port": true, "db.user": true, "timeouts[:]": true, "extra": true, "routes[]": true}
+	if len(errs) != len(expected) {
+		t.Errorf("TestSchemaValidate - Validate - expected: %d errors, got: %s", len(expected), errs)
+	}
+	for _, e := range errs {
+		if ke, ok := e.(*KeyError); !ok || !expected[ke.Key] {
+			t.Errorf("TestSchemaValidate - Validate - unexpected error: %s", e)
+		}
+	}
+}
+
+func TestLoadSchemaStr(t *testing.T) {
+	schema, e := LoadSchemaStr(`
+[document:db.port]
+type = int
+required = true
+default = 5432
+doc = port of the database server
+
+[document:db.host]
+
+[document:db.password]
+secret = true
+`)
+	if e != nil {
+		t.Fatalf("TestLoadSchemaStr - LoadSchemaStr - %s", e)
+	}
+	if len(schema.Keys) != 3 || schema.Keys[0].Key != "db.host" {
+		t.Errorf("TestLoadSchemaStr - expected: [db.host db.password db.port], got: %v", schema.Keys)
+	}
+	spec := schema.Spec("db.port")
+	if spec == nil || spec.Type != TypeInt || !spec.Required || spec.Default != "5432" {
+		t.Errorf("TestLoadSchemaStr - Spec(db.port) - got: %v", spec)
+	}
+	if spec := schema.Spec("db.password"); spec == nil || !spec.Secret {
+		t.Errorf("TestLoadSchemaStr - Spec(db.password) - expected secret, got: %v", spec)
+	}
+
+	if _, e := LoadSchemaStr("[document:a]\ntype = float\n"); e == nil {
+		t.Errorf("TestLoadSchemaStr - LoadSchemaStr - error expected for unknown type")
+	}
+}
+
+func TestSchemaApplyDefaults(t *testing.T) {
+	schema := &Schema{Keys: []KeySpec{
+		{Key: "db.host", Default: "localhost"},
+		{Key: "db.port", Type: TypeInt, Default: "5432"},
+		{Key: "db.hosts[]", Default: "a, b"},
+		{Key: "db.user"},
+	}}
+	p := Properties{"db.host": "db.internal"}
+	dp, e := schema.ApplyDefaults(p)
+	if e != nil {
+		t.Fatalf("TestSchemaApplyDefaults - %s", e)
+	}
+	if v := dp.GetString("db.host"); v != "db.internal" {
+		t.Errorf("TestSchemaApplyDefaults - db.host - expected: db.internal, got: %s", v)
+	}
+	if v, e := dp.GetInt("db.port"); e != nil || v != 5432 {
+		t.Errorf("TestSchemaApplyDefaults - db.port - expected: 5432, got: %d, %v", v, e)
+	}
+	if v := dp.GetArray("db.hosts[]"); len(v) != 2 {
+		t.Errorf("TestSchemaApplyDefaults - db.hosts[] - expected: [a b], got: %v", v)
+	}
+	if dp.Has("db.user") || len(p) != 1 {
+		t.Errorf("TestSchemaApplyDefaults - expected no db.user, and p unmodified, got: %v, %v", dp, p)
+	}
+}
+
+func TestSchemaDirective(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "schemas"), 0755)
+	os.WriteFile(filepath.Join(dir, "schemas", "db.gs"), []byte(`
+[document:db.port]
+type = int
+required = true
+`), 0644)
+	write := func(name, content string) string {
+		filename := filepath.Join(dir, name)
+		os.WriteFile(filename, []byte(content), 0644)
+		return filename
+	}
+
+	p, e := Load(write("ok.gs", "# database settings\n@schema schemas/db.gs\ndb.port = 5432\n"))
+	if e != nil || p.GetString("db.port") != "5432" || len(p) != 1 {
+		t.Errorf("TestSchemaDirective - Load(ok.gs) - got: %v, %v", p, e)
+	}
+	if _, e := Load(write("bad.gs", "@schema schemas/db.gs\ndb.port = http\n")); e == nil || !strings.Contains(e.Error(), "db.port") {
+		t.Errorf("TestSchemaDirective - Load(bad.gs) - expected validation error, got: %v", e)
+	}
+	if _, e := Load(write("missing.gs", "@schema schemas/nope.gs\ndb.port = 5432\n")); e == nil {
+		t.Errorf("TestSchemaDirective - Load(missing.gs) - expected error of missing schema")
+	}
+	if _, e := Load(write("late.gs", "db.port = 5432\n@schema schemas/db.gs\n")); e == nil {
+		t.Errorf("TestSchemaDirective - Load(late.gs) - expected error of misplaced directive")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"strings"
+)
+
+// ----------------------------------------------------------------------
+// Scrubbing
+//
+// exports of Properties (Store, Convert, MarshalJSON, Environ, etc.)
+// write values as is: scrubbing is opt-in, and no export scrubs values
+// itself. Sanitized configs, e.g. to be shared or attached to tickets, are
+// exported from a scrubbed copy of the Properties, with the values of
+// sensitive keys (see Sensitive) replaced per a ScrubPolicy:
+//
+//	s := p.Scrub(schema.Sensitive, gestalt.ScrubPlaceholder(""))
+//	s.Store(w)
+//
+// Opaque lines (see PassThrough) can not be scrubbed, and are dropped if
+// they mention a sensitive key.
+// ----------------------------------------------------------------------
+
+// ScrubPolicy returns the replacement of the (resolved) value v of the
+// sensitive key, or nil if the key is to be omitted. See Scrub.
+type ScrubPolicy func(key string, v interface{}) interface{}
+
+// ScrubOmit omits sensitive keys.
+func ScrubOmit() ScrubPolicy {
+	return func(string, interface{}) interface{} {
+		return nil
+	}
+}
+
+// ScrubPlaceholder replaces the values of sensitive keys with placeholder
+// (or "[redacted]" if placeholder is ""). Array values are replaced by
+// a single placeholder element, and map values by the placeholder per
+// map key.
+func ScrubPlaceholder(placeholder string) ScrubPolicy {
+	if placeholder == empty {
+		placeholder = redacted
+	}
+	return func(key string, v interface{}) interface{} {
+		return scrubValue(v, func(string) string { return placeholder })
+	}
+}
+
+// ScrubReference replaces the values of sensitive keys with references
+// of scheme to the path prefix + key (without its kind suffix), e.g. of
+// scheme "vault" and prefix "secret/app/", `db.password = secret` is
+// exported as `db.password = ${vault:secret/app/db.password}`, for
+// resolution by an Interpolator of the scheme (see Interpolate). The
+// references of map values are per map key, e.g. ${vault:secret/app/m/k}.
+func ScrubReference(scheme, prefix string) ScrubPolicy {
+	return func(key string, v interface{}) interface{} {
+		path := prefix + strings.TrimSuffix(strings.TrimSuffix(key, cmap), array)
+		return scrubValue(v, func(mk string) string {
+			if mk != empty {
+				return "${" + scheme + ":" + path + "/" + mk + "}"
+			}
+			return "${" + scheme + ":" + path + "}"
+		})
+	}
+}
+
+// returns the value of the kind of v, of the replacements of fn, which is
+// called with the map key of map values, or "".
+func scrubValue(v interface{}, fn func(mk string) string) interface{} {
+	switch v := v.(type) {
+	case []string:
+		return []string{fn(empty)}
+	case map[string]string:
+		mapv := make(map[string]string, len(v))
+		for mk := range v {
+			mapv[mk] = fn(mk)
+		}
+		return mapv
+	}
+	return fn(empty)
+}
+
+// Returns a copy of p with the values of the sensitive keys (marked secret
+// by p, see MarkSecret, or per sensitive, e.g. Schema.Sensitive or
+// ACL.Sensitive) replaced per policy. nil sensitive adds no keys. Other
+// values (and @unset keys) are copied as is. Opaque lines mentioning a
+// sensitive key (of p, or per sensitive) are dropped.
+func (p Properties) Scrub(sensitive Sensitive, policy ScrubPolicy) Properties {
+	given := sensitive
+	sensitive = func(key string) bool {
+		return p.IsSecret(key) || (given != nil && given(key))
+	}
+	scrub := func(k string, v interface{}) interface{} {
+		if k = plainKey(k); v == nil || isUnset(v) || !sensitive(k) {
+			return v
+		}
+		return policy(k, v)
+	}
+	scrubbed := make(Properties, len(p))
+	var keys []string // sensitive keys of p
+	for k, v := range p {
+		if sensitive(plainKey(k)) {
+			keys = append(keys, plainKey(k))
+		}
+		if sv := scrub(k, v); sv != nil {
+			scrubbed[k] = sv
+		}
+	}
+	scrubbed.adopt(p, nil, true)
+	if opaque := p.opaque(); len(opaque) > 0 {
+		var kept []opaqueLine
+		for _, ol := range opaque {
+			if !sensitiveLine(ol.text, keys, sensitive) {
+				kept = append(kept, ol)
+			}
+		}
+		s := scrubbed.side()
+		s.mu.Lock()
+		s.opaque = kept
+		s.mu.Unlock()
+	}
+	if defaults := p.defaults(); defaults != nil {
+		sd := make(map[string]interface{}, len(defaults))
+		for k, v := range defaults {
+			if sv := scrub(k, v); sv != nil {
+				sd[k] = sv
+			}
+		}
+		s := scrubbed.side()
+		s.mu.Lock()
+		s.defaults = sd
+		s.mu.Unlock()
+	}
+	return scrubbed
+}
+
+// returns true if the (opaque) line mentions any of the sensitive keys,
+// or has a token (e.g. a key of a malformed line) that is sensitive.
+func sensitiveLine(line string, keys []string, sensitive Sensitive) bool {
+	for _, k := range keys {
+		if strings.Contains(line, k) {
+			return true
+		}
+	}
+	tokens := strings.FieldsFunc(line, func(r rune) bool {
+		return strings.ContainsRune(ws+"=:,", r)
+	})
+	for _, token := range tokens {
+		if sensitive(token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package gestalt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestScrub(t *testing.T) {
+	p, _ := LoadStr(`
+db.host = db.local
+db.password = s3cret
+api.keys[] = k1, k2
+tokens[:] = a:t1, b:t2
+old.password = @unset
+`)
+	sensitive := func(key string) bool {
+		return key != "db.host"
+	}
+
+	s := p.Scrub(sensitive, ScrubOmit())
+	if _, ok := s["old.password"]; !ok || !reflect.DeepEqual(s.Keys(), []string{"db.host"}) {
+		t.Errorf("TestScrub - ScrubOmit - expected: [db.host] and @unset old.password, got: %v", s)
+	}
+
+	s = p.Scrub(sensitive, ScrubPlaceholder(""))
+	var b bytes.Buffer
+	if e := s.Store(&b); e != nil {
+		t.Fatalf("TestScrub - ScrubPlaceholder - Store - %s", e)
+	}
+	expected := `api.keys[] = [redacted]
+db.host = db.local
+db.password = [redacted]
+old.password = @unset
+tokens[:] = a:[redacted], b:[redacted]
+`
+	if b.String() != expected {
+		t.Errorf("TestScrub - ScrubPlaceholder - expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+	if v := p.GetString("db.password"); v != "s3cret" {
+		t.Errorf("TestScrub - Scrub - expected receiver unchanged, got: %s", v)
+	}
+
+	s = p.Scrub(sensitive, ScrubReference("vault", "secret/app/"))
+	for k, expected := range map[string]interface{}{
+		"db.host":     "db.local",
+		"db.password": "${vault:secret/app/db.password}",
+		"api.keys[]":  []string{"${vault:secret/app/api.keys}"},
+		"tokens[:]":   map[string]string{"a": "${vault:secret/app/tokens/a}", "b": "${vault:secret/app/tokens/b}"},
+	} {
+		if v := s.get(k); !reflect.DeepEqual(v, expected) {
+			t.Errorf("TestScrub - ScrubReference - %s - expected: %v, got: %v", k, expected, v)
+		}
+	}
+	env := s.Environ()
+	if env[2] != "DB_PASSWORD=${vault:secret/app/db.password}" {
+		t.Errorf("TestScrub - ScrubReference - Environ - got: %v", env)
+	}
+
+	schema := &Schema{Keys: []KeySpec{{Key: "db.password", Secret: true}}}
+	js, _ := p.Scrub(schema.Sensitive, ScrubPlaceholder("***")).MarshalJSON()
+	if bytes.Contains(js, []byte("s3cret")) || !bytes.Contains(js, []byte("***")) {
+		t.Errorf("TestScrub - Schema.Sensitive - MarshalJSON - got: %s", js)
+	}
+
+	if s := p.Scrub(nil, ScrubOmit()); !reflect.DeepEqual(s, p) {
+		t.Errorf("TestScrub - Scrub(nil) - expected unchanged copy, got: %v", s)
+	}
+	kp, e := LoadStr("db.host = db.local\ndb.password = keyring:db/app\n", WithKeyring(fakeKeyring{"db/app": "hunter2"}))
+	if e != nil {
+		t.Fatalf("TestScrub - LoadStr - %s", e)
+	}
+	kp.MarkSecret("db.host")
+	if s := kp.Scrub(nil, ScrubOmit()); len(s) != 0 {
+		t.Errorf("TestScrub - Scrub(nil) - expected keys marked secret (and of the keyring) scrubbed, got: %v", s)
+	}
+}
+
+func TestScrubOpaque(t *testing.T) {
+	p, e := LoadStr("db.password = secret\n%set db.password secret2\napi.token: t0k3n\n%include extra.conf\n", PassThrough())
+	if e != nil {
+		t.Fatalf("TestScrubOpaque - LoadStr - %s", e)
+	}
+	sensitive := func(k string) bool { return k == "db.password" || k == "api.token" }
+	s := p.Scrub(sensitive, ScrubOmit())
+	if v := s.Opaque(); len(v) != 1 || v[0] != "%include extra.conf" {
+		t.Errorf("TestScrubOpaque - Scrub - expected opaque lines of sensitive keys dropped, got: %q", v)
+	}
+	if len(p.Opaque()) != 3 {
+		t.Errorf("TestScrubOpaque - Scrub - expected opaque lines of p retained, got: %q", p.Opaque())
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// redacted representation of secret values
+const redacted = "[redacted]"
+
+// Secret is binary secret material, e.g. an HMAC key or salt.
+//
+// Secrets are redacted when printed (via fmt), and should be compared
+// with Equal, in constant time.
+type Secret []byte
+
+// Returns true if s and b are equal. The time taken is independent of
+// the content of s and b.
+func (s Secret) Equal(b []byte) bool {
+	return subtle.ConstantTimeCompare(s, b) == 1
+}
+
+func (s Secret) String() string {
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return redacted
+}
+
+// Format redacts s for all verbs, e.g. %x and %v.
+func (s Secret) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, redacted)
+}
+
+// MarkSecret marks keys as secret, so that their values are redacted by
+// String (and Print), and by Diff, Scrub, and Dump. Keys are marked secret
+// when loaded, per the `secret` type hint (see TypeHints), the Secret keys
+// of the schema of LoadStrict, and keyring references (see WithKeyring).
+func (p Properties) MarkSecret(keys ...string) {
+	s := p.side()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.secrets == nil {
+		s.secrets = make(map[string]bool)
+	}
+	for _, k := range keys {
+		s.secrets[plainKey(k)] = true
+	}
+}
+
+// Returns true if key is marked secret. See MarkSecret.
+func (p Properties) IsSecret(key string) bool {
+	s := sideOf(p)
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.secrets[plainKey(key)]
+}
+
+// Binary value property of base64 encoded value - returns error if no such
+// key or value is not valid base64. Both the standard and URL alphabets are
+// accepted. As `=` is reserved, values are written without padding.
+func (p Properties) GetBytesBase64(key string) (Secret, error) {
+	s, e := p.stringValue(key)
+	if e != nil {
+		return nil, e
+	}
+	s = strings.TrimRight(s, "=")
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	b, e := enc.DecodeString(s)
+	if e != nil {
+		return nil, wrongType("key <%s> - invalid base64 value - %s", key, e)
+	}
+	return b, nil
+}
+
+// Binary value property of hex encoded value - returns error if no such
+// key or value is not valid hex.
+func (p Properties) GetBytesHex(key string) (Secret, error) {
+	s, e := p.stringValue(key)
+	if e != nil {
+		return nil, e
+	}
+	b, e := hex.DecodeString(s)
+	if e != nil {
+		return nil, wrongType("key <%s> - invalid hex value - %s", key, e)
+	}
+	return b, nil
+}
@@ -0,0 +1,71 @@
+package gestalt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetBytes(t *testing.T) {
+	p, _ := LoadStr(`
+hmac.key = c2VjcmV0IGtleQ
+url.key = -_8
+salt = 0a0b0c
+bad = zz
+`)
+	b, e := p.GetBytesBase64("hmac.key")
+	if e != nil || string(b) != "secret key" {
+		t.Errorf("TestGetBytes - GetBytesBase64(hmac.key) - expected: secret key, got: %q, %v", []byte(b), e)
+	}
+	if !b.Equal([]byte("secret key")) || b.Equal([]byte("secret kez")) {
+		t.Errorf("TestGetBytes - Equal - unexpected result")
+	}
+	if b, e := p.GetBytesBase64("url.key"); e != nil || len(b) != 2 || b[0] != 0xfb || b[1] != 0xff {
+		t.Errorf("TestGetBytes - GetBytesBase64(url.key) - got: %x, %v", []byte(b), e)
+	}
+
+	salt, e := p.GetBytesHex("salt")
+	if e != nil || !salt.Equal([]byte{0x0a, 0x0b, 0x0c}) {
+		t.Errorf("TestGetBytes - GetBytesHex(salt) - got: %x, %v", []byte(salt), e)
+	}
+	for _, verb := range []string{"%s", "%v", "%x", "%#v", "%q"} {
+		if s := fmt.Sprintf(verb, salt); s != redacted {
+			t.Errorf("TestGetBytes - Sprintf(%s) - expected: %s, got: %s", verb, redacted, s)
+		}
+	}
+
+	if _, e := p.GetBytesHex("bad"); e == nil {
+		t.Errorf("TestGetBytes - GetBytesHex(bad) - error expected")
+	}
+	if _, e := p.GetBytesBase64("nope"); e == nil {
+		t.Errorf("TestGetBytes - GetBytesBase64(nope) - error expected")
+	}
+}
+
+func TestMarkSecret(t *testing.T) {
+	p, e := LoadStr("hmac.key:secret = c2VjcmV0IGtleQ\ndb.password = hunter2\nhost = localhost\n", TypeHints(nil))
+	if e != nil {
+		t.Fatalf("TestMarkSecret - LoadStr - %s", e)
+	}
+	p.MarkSecret("db.password")
+	s := p.String() // before any getter
+	if strings.Contains(s, "c2VjcmV0IGtleQ") || strings.Contains(s, "hunter2") || !strings.Contains(s, "localhost") {
+		t.Errorf("TestMarkSecret - String - expected secret values redacted, got: %s", s)
+	}
+	if !p.Clone().IsSecret("db.password") || p.IsSecret("host") {
+		t.Errorf("TestMarkSecret - IsSecret - expected db.password (of clones) only")
+	}
+}
+
+func TestLoadStrictSecret(t *testing.T) {
+	schema := &Schema{Keys: []KeySpec{{Key: "db.password", Secret: true}}}
+	fsys := FS(fstest.MapFS{"app.conf": {Data: []byte("db.password = hunter2\n")}})
+	p, e := LoadStrict("app.conf", schema, WithFileSystem(fsys))
+	if e != nil {
+		t.Fatalf("TestLoadStrictSecret - LoadStrict - %s", e)
+	}
+	if s := p.String(); strings.Contains(s, "hunter2") {
+		t.Errorf("TestLoadStrictSecret - String - expected db.password redacted, got: %s", s)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KeyConfigVersion is the conventional key of the version of the schema
+// (generation) a configuration is written for, e.g. `config.version = 2.1.0`.
+// See ConfigVersion.
+const KeyConfigVersion = "config.version"
+
+// Semver is a semantic version, e.g. 1.4.0-rc.1. See semver.org.
+type Semver struct {
+	Major, Minor, Patch int
+	Pre                 string // pre-release, e.g. "rc.1"
+	Build               string // build metadata (ignored by comparisons)
+}
+
+// Parses the semantic version s, with an optional `v` prefix. The minor and
+// patch numbers are optional, e.g. "1.4" is 1.4.0. Returns error if s is
+// malformed.
+func ParseSemver(s string) (Semver, error) {
+	var v Semver
+	rest := strings.TrimPrefix(strings.Trim(s, ws), "v")
+	rest, v.Build, _ = strings.Cut(rest, "+")
+	rest, v.Pre, _ = strings.Cut(rest, "-")
+	nums := strings.Split(rest, ".")
+	if len(nums) > 3 {
+		return Semver{}, fmt.Errorf("malformed version <%s>", s)
+	}
+	var xs [3]int
+	for i, n := range nums {
+		x, e := strconv.Atoi(n)
+		if e != nil || x < 0 || n[0] == '+' || (len(n) > 1 && n[0] == '0') {
+			return Semver{}, fmt.Errorf("malformed version <%s>", s)
+		}
+		xs[i] = x
+	}
+	v.Major, v.Minor, v.Patch = xs[0], xs[1], xs[2]
+	return v, nil
+}
+
+// Returns -1, 0, or 1 if v is lower than, equal to, or higher than w, per
+// semver precedence (e.g. 1.0.0-rc.1 < 1.0.0).
+func (v Semver) Compare(w Semver) int {
+	for _, d := range []int{v.Major - w.Major, v.Minor - w.Minor, v.Patch - w.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case v.Pre == w.Pre:
+		return 0
+	case v.Pre == empty:
+		return 1
+	case w.Pre == empty:
+		return -1
+	}
+	vids, wids := strings.Split(v.Pre, "."), strings.Split(w.Pre, ".")
+	for i := 0; i < len(vids) && i < len(wids); i++ {
+		vn, ve := strconv.Atoi(vids[i])
+		wn, we := strconv.Atoi(wids[i])
+		switch {
+		case ve == nil && we == nil && vn != wn:
+			return sign(vn - wn)
+		case ve == nil && we != nil:
+			return -1
+		case ve != nil && we == nil:
+			return 1
+		case vids[i] != wids[i]:
+			return strings.Compare(vids[i], wids[i])
+		}
+	}
+	return sign(len(vids) - len(wids))
+}
+
+func sign(d int) int {
+	switch {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+func (v Semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != empty {
+		s += "-" + v.Pre
+	}
+	if v.Build != empty {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Semantic version value property - returns error if no such key or value is
+// not a semantic version. See ParseSemver.
+func (p Properties) GetSemver(key string) (Semver, error) {
+	v, e := p.typed(key, typed_semver)
+	if e != nil {
+		return Semver{}, e
+	}
+	return v.(Semver), nil
+}
+
+// Returns the version of the configuration, per KeyConfigVersion. Returns
+// error if the key is not defined or is not a semantic version.
+func (p Properties) ConfigVersion() (Semver, error) {
+	return p.GetSemver(KeyConfigVersion)
+}
+
+// MinVersion returns a KeySpec Check of TypeSemver keys, rejecting versions
+// lower than min, e.g.
+//
+//	{Key: gestalt.KeyConfigVersion, Type: gestalt.TypeSemver, Required: true, Check: gestalt.MinVersion("1.4.0")}
+//
+// Panics if min is malformed.
+func MinVersion(min string) func(v interface{}) error {
+	mv := mustParseSemver(min)
+	return func(v interface{}) error {
+		if sv, ok := v.(Semver); !ok || sv.Compare(mv) < 0 {
+			return fmt.Errorf("version %v is lower than %s", v, mv)
+		}
+		return nil
+	}
+}
+
+// MaxVersion returns a KeySpec Check of TypeSemver keys, rejecting versions
+// higher than max. Panics if max is malformed.
+func MaxVersion(max string) func(v interface{}) error {
+	mv := mustParseSemver(max)
+	return func(v interface{}) error {
+		if sv, ok := v.(Semver); !ok || sv.Compare(mv) > 0 {
+			return fmt.Errorf("version %v is higher than %s", v, mv)
+		}
+		return nil
+	}
+}
+
+func mustParseSemver(s string) Semver {
+	v, e := ParseSemver(s)
+	if e != nil {
+		panic(e)
+	}
+	return v
+}
+
+// ----------------------------------------------------------------------
+// Version constraints
+//
+// a constraint is a comma separated list of conditions, all of which must
+// be satisfied, e.g. ">=1.4, <2". Conditions are comparisons with
+// =, !=, <, <=, >, >=, or ^1.4 (i.e. >=1.4.0, <2.0.0), or ~1.4 (i.e.
+// >=1.4.0, <1.5.0).
+// ----------------------------------------------------------------------
+
+// ErrConfigVersion is the error of configurations of unsupported versions.
+// See RequireConfigVersion.
+var ErrConfigVersion = errors.New("unsupported configuration version")
+
+// VersionError is the error of a configuration version not satisfying a
+// constraint. The error message suggests an upgrade of the configuration
+// (see Migration), or of the application.
+type VersionError struct {
+	Version    *Semver // nil if the configuration has no version
+	Constraint string
+	older      bool // the version is older than required
+}
+
+func (e *VersionError) Error() string {
+	if e.Version == nil {
+		return fmt.Sprintf("%s - key <%s> is not defined (required: %s) - the configuration "+
+			"predates versioning, and may need to be migrated (see gestalt migrate) before "+
+			"setting %s", ErrConfigVersion, KeyConfigVersion, e.Constraint, KeyConfigVersion)
+	}
+	if e.older {
+		return fmt.Sprintf("%s - version %s of the configuration does not satisfy %s - "+
+			"the configuration is written for an older version of the application, and must be "+
+			"migrated (see gestalt migrate) before updating %s", ErrConfigVersion, e.Version, e.Constraint, KeyConfigVersion)
+	}
+	return fmt.Sprintf("%s - version %s of the configuration does not satisfy %s - "+
+		"the configuration is written for a newer version of the application, which must be upgraded",
+		ErrConfigVersion, e.Version, e.Constraint)
+}
+
+func (e *VersionError) Unwrap() error {
+	return ErrConfigVersion
+}
+
+// RequireConfigVersion checks the version of the configuration p (see
+// ConfigVersion) against constraint, e.g. ">=2.0, <3", so that applications
+// refuse configurations of incompatible schema generations with actionable
+// errors, rather than errors of missing or malformed keys. Returns a
+// *VersionError if the version is not defined or does not satisfy the
+// constraint, or error if the constraint or version is malformed.
+func RequireConfigVersion(p Properties, constraint string) error {
+	conds, e := parseConstraint(constraint)
+	if e != nil {
+		return e
+	}
+	if p.get(KeyConfigVersion) == nil {
+		return &VersionError{Constraint: constraint}
+	}
+	v, e := p.ConfigVersion()
+	if e != nil {
+		return e
+	}
+	for _, c := range conds {
+		if !c.satisfied(v) {
+			return &VersionError{&v, constraint, v.Compare(c.v) < 0}
+		}
+	}
+	return nil
+}
+
+// a condition of a version constraint
+type versionCond struct {
+	op string
+	v  Semver
+}
+
+func (c versionCond) satisfied(v Semver) bool {
+	d := v.Compare(c.v)
+	switch c.op {
+	case "=":
+		return d == 0
+	case "!=":
+		return d != 0
+	case "<":
+		return d < 0
+	case "<=":
+		return d <= 0
+	case ">":
+		return d > 0
+	}
+	return d >= 0
+}
+
+// parses the constraint s. Caret and tilde conditions are expanded to
+// their bounds.
+func parseConstraint(s string) ([]versionCond, error) {
+	var conds []versionCond
+	for _, term := range strings.Split(s, val_delim) {
+		term = strings.Trim(term, ws)
+		op := term[:len(term)-len(strings.TrimLeft(term, "=!<>^~"))]
+		v, e := ParseSemver(term[len(op):])
+		if e != nil {
+			return nil, fmt.Errorf("malformed version constraint <%s> - %s", s, e)
+		}
+		switch op {
+		case "^", "~":
+			upper := Semver{Major: v.Major + 1}
+			if op == "~" || (v.Major == 0 && op == "^") {
+				upper = Semver{Major: v.Major, Minor: v.Minor + 1}
+			}
+			conds = append(conds, versionCond{">=", v}, versionCond{"<", upper})
+		case "", "=", "!=", "<", "<=", ">", ">=":
+			if op == "" {
+				op = "="
+			}
+			conds = append(conds, versionCond{op, v})
+		default:
+			return nil, fmt.Errorf("malformed version constraint <%s> - operator <%s>", s, op)
+		}
+	}
+	return conds, nil
+}
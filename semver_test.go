@@ -0,0 +1,99 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSemver(t *testing.T) {
+	for s, expected := range map[string]Semver{
+		"1.4.0":            {1, 4, 0, "", ""},
+		"v2.0":             {2, 0, 0, "", ""},
+		"3":                {3, 0, 0, "", ""},
+		"1.0.0-rc.1+b.123": {1, 0, 0, "rc.1", "b.123"},
+	} {
+		if v, e := ParseSemver(s); e != nil || v != expected {
+			t.Errorf("TestParseSemver - ParseSemver(%s) - expected: %v, got: %v, %v", s, expected, v, e)
+		}
+	}
+	for _, s := range []string{"", "1.", "1.2.3.4", "01.2", "a.b", "1.-2"} {
+		if _, e := ParseSemver(s); e == nil {
+			t.Errorf("TestParseSemver - ParseSemver(%s) - error expected", s)
+		}
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	// in ascending order
+	versions := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "2.0.0"}
+	for i := range versions {
+		for j := range versions {
+			v, w := mustParseSemver(versions[i]), mustParseSemver(versions[j])
+			if c := v.Compare(w); c != sign(i-j) {
+				t.Errorf("TestSemverCompare - %s.Compare(%s) - expected: %d, got: %d", v, w, sign(i-j), c)
+			}
+		}
+	}
+	if v := mustParseSemver("1.0.0+a"); v.Compare(mustParseSemver("1.0.0+b")) != 0 {
+		t.Errorf("TestSemverCompare - expected build metadata to be ignored")
+	}
+}
+
+func TestGetSemver(t *testing.T) {
+	p := Properties{KeyConfigVersion: "1.3.2", "bad": "x"}
+	if v, e := p.ConfigVersion(); e != nil || v.String() != "1.3.2" {
+		t.Errorf("TestGetSemver - ConfigVersion - expected: 1.3.2, got: %v, %v", v, e)
+	}
+	for _, key := range []string{"bad", "nope"} {
+		if _, e := p.GetSemver(key); e == nil {
+			t.Errorf("TestGetSemver - GetSemver(%s) - error expected", key)
+		}
+	}
+
+	s := &Schema{Keys: []KeySpec{{Key: KeyConfigVersion, Type: TypeSemver, Check: MinVersion("1.4.0")}}}
+	if errs := s.Validate(p); len(errs) != 1 {
+		t.Errorf("TestGetSemver - Validate(MinVersion 1.4.0) - expected 1 error, got: %v", errs)
+	}
+	s.Keys[0].Check = MinVersion("1.3")
+	if errs := s.Validate(p); len(errs) != 0 {
+		t.Errorf("TestGetSemver - Validate(MinVersion 1.3) - unexpected errors: %v", errs)
+	}
+	s.Keys[0].Check = MaxVersion("1.3.1")
+	if errs := s.Validate(p); len(errs) != 1 {
+		t.Errorf("TestGetSemver - Validate(MaxVersion 1.3.1) - expected 1 error, got: %v", errs)
+	}
+}
+
+func TestRequireConfigVersion(t *testing.T) {
+	p := Properties{KeyConfigVersion: "2.3.1"}
+	for _, c := range []string{">=2.0, <3", "^2.1", "~2.3", "2.3.1", "!=2.3.0", ">2.3.0"} {
+		if e := RequireConfigVersion(p, c); e != nil {
+			t.Errorf("TestRequireConfigVersion - RequireConfigVersion(%s) - unexpected error: %s", c, e)
+		}
+	}
+	for c, older := range map[string]bool{">=3": true, "^3.0": true, "<2": false, "~2.2": false, "^0.2": false} {
+		e := RequireConfigVersion(p, c)
+		var ve *VersionError
+		if !errors.As(e, &ve) || !errors.Is(e, ErrConfigVersion) || ve.older != older {
+			t.Errorf("TestRequireConfigVersion - RequireConfigVersion(%s) - expected VersionError (older: %t), got: %v", c, older, e)
+			continue
+		}
+		if hint := strings.Contains(e.Error(), "migrate"); hint != older {
+			t.Errorf("TestRequireConfigVersion - RequireConfigVersion(%s) - expected migration hint: %t, got: %s", c, older, e)
+		}
+	}
+	e := RequireConfigVersion(Properties{}, ">=1")
+	if ve, ok := e.(*VersionError); !ok || ve.Version != nil || !strings.Contains(e.Error(), KeyConfigVersion) {
+		t.Errorf("TestRequireConfigVersion - RequireConfigVersion of unversioned - got: %v", e)
+	}
+	for _, c := range []string{"", ">=x", "=>1", ">=1,"} {
+		if e := RequireConfigVersion(p, c); e == nil || errors.Is(e, ErrConfigVersion) {
+			t.Errorf("TestRequireConfigVersion - RequireConfigVersion(%s) - expected malformed constraint, got: %v", c, e)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+	"weak"
+)
+
+// ----------------------------------------------------------------------
+// Side tables
+//
+// the values of Properties are plain (string, []string, map[string]string,
+// per key kind), and users may read and write the map directly. State of
+// features that plain values can not represent, i.e. the windows of
+// time-windowed keys, defaults, memoized conversions, opaque lines, and
+// secret keys, is
+// held in a side table of the Properties object, keyed by the identity of
+// its map. Side tables are dropped when their map is garbage collected.
+// ----------------------------------------------------------------------
+
+// side is the side table of a Properties object.
+type side struct {
+	owner weak.Pointer[byte] // the map of the Properties object
+
+	mu       sync.RWMutex
+	clock    Clock                  // of windows
+	windows  map[string][]window    // by plain key, see splitWindow
+	defaults map[string]interface{} // see SetDefaults
+	opaque   []opaqueLine           // see PassThrough
+	memoize  bool                   // see Lazy and PreResolve
+	memos    map[string]*memo       // memoized conversions, by key
+	reps     map[string]quotedRep   // of quoted string values, by key
+	secrets  map[string]bool        // by plain key, see MarkSecret
+}
+
+// the representation vrep of the (string) value of a key, if quoted, e.g.
+// of GetStringsSplit
+type quotedRep struct {
+	value, vrep string
+}
+
+// memoized conversions of the (string) value src of a key
+type memo struct {
+	src  string
+	conv map[byte]typedValue
+}
+
+// a memoized conversion
+type typedValue struct {
+	v interface{}
+	e error
+}
+
+// side tables (*side), by map address. Lookups (e.g. of hot getters) of
+// a sync.Map neither lock nor allocate, and are skipped while no side
+// table is live.
+var sides struct {
+	mu sync.Mutex // serializes the creation and dropping of side tables
+	m  sync.Map
+	n  atomic.Int64 // number of side tables
+}
+
+// returns the address of the map of p
+func mapOf(p Properties) *byte {
+	return (*byte)(reflect.ValueOf(p).UnsafePointer())
+}
+
+// returns the side table of p, or nil if none.
+func sideOf(p Properties) *side {
+	if sides.n.Load() == 0 || p == nil {
+		return nil
+	}
+	ptr := mapOf(p)
+	// addresses are reused once maps are collected: the owner of an entry
+	// of a collected map (pending its cleanup) is nil.
+	if v, ok := sides.m.Load(uintptr(unsafe.Pointer(ptr))); ok {
+		if s := v.(*side); s.owner.Value() == ptr {
+			return s
+		}
+	}
+	return nil
+}
+
+// returns the side table of p, created if none. p must not be nil.
+func (p Properties) side() *side {
+	if s := sideOf(p); s != nil {
+		return s
+	}
+	sides.mu.Lock()
+	defer sides.mu.Unlock()
+	if s := sideOf(p); s != nil {
+		return s
+	}
+	ptr := mapOf(p)
+	id := uintptr(unsafe.Pointer(ptr))
+	s := &side{owner: weak.Make(ptr), clock: SystemClock}
+	if _, replaced := sides.m.Swap(id, s); !replaced {
+		sides.n.Add(1)
+	}
+	runtime.AddCleanup(ptr, dropSide, id)
+	return s
+}
+
+// drops the side table of a collected map
+func dropSide(id uintptr) {
+	sides.mu.Lock()
+	defer sides.mu.Unlock()
+	if v, ok := sides.m.Load(id); ok && v.(*side).owner.Value() == nil {
+		sides.m.Delete(id)
+		sides.n.Add(-1)
+	}
+}
+
+// returns the value of key of p, given its (plain) value v: the value of
+// the current window of key, if any, or else v, or else the default of key.
+func (s *side) value(p Properties, key string, v interface{}) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if wk := s.window(p, key); wk != key {
+		return p[wk]
+	}
+	if v == nil {
+		return s.defaults[key]
+	}
+	return v
+}
+
+// returns the windowed key of the current window of key, if any, or key.
+// s.mu must be held.
+func (s *side) window(p Properties, key string) string {
+	if ws := s.windows[key]; ws != nil {
+		now := s.clock.Now()
+		for _, w := range ws {
+			if _, ok := p[w.key]; w.includes(now) && ok {
+				return w.key
+			}
+		}
+	}
+	return key
+}
+
+// returns the representation of the (string) value v of key, if quoted,
+// or else v.
+func (s *side) rep(p Properties, key string, v string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, ok := s.reps[s.window(p, key)]; ok && r.value == v {
+		return r.vrep
+	}
+	return v
+}
+
+// records the representation vrep of the (string) value v of key
+func (p Properties) addRep(key string, v, vrep string) {
+	s := p.side()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reps == nil {
+		s.reps = make(map[string]quotedRep)
+	}
+	s.reps[key] = quotedRep{v, vrep}
+}
+
+// adopts the side state of from, of the keys mapped by rename to their key
+// in p, e.g. of Sub, or of all keys (and the opaque lines) if rename is nil,
+// e.g. of Clone. Windows and secret keys are added, and defaults unless p
+// has a default of the key (and not overwrite). Opaque lines are appended.
+func (p Properties) adopt(from Properties, rename func(key string) (string, bool), overwrite bool) {
+	fs := sideOf(from)
+	if fs == nil || p == nil || sideOf(p) == fs {
+		return
+	}
+	all := rename == nil
+	if all {
+		rename = func(key string) (string, bool) { return key, true }
+	}
+
+	// the state of from is collected first, so that the locks of the two
+	// side tables are not held at once.
+	var windows = make(map[string][]window)
+	var defaults = make(map[string]interface{})
+	var reps = make(map[string]quotedRep)
+	var secrets []string
+	fs.mu.RLock()
+	clock, memoize := fs.clock, fs.memoize
+	for k, ws := range fs.windows {
+		if rk, ok := rename(k); ok {
+			for _, w := range ws {
+				if w.key, ok = rename(w.key); ok {
+					windows[rk] = append(windows[rk], w)
+				}
+			}
+		}
+	}
+	for k, v := range fs.defaults {
+		if rk, ok := rename(k); ok {
+			defaults[rk] = copyValue(v)
+		}
+	}
+	for k, r := range fs.reps {
+		if rk, ok := rename(k); ok {
+			reps[rk] = r
+		}
+	}
+	for k := range fs.secrets {
+		if rk, ok := rename(k); ok {
+			secrets = append(secrets, rk)
+		}
+	}
+	var opaque []opaqueLine
+	if all {
+		opaque = append(opaque, fs.opaque...)
+	}
+	fs.mu.RUnlock()
+
+	s := p.side()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(windows) > 0 {
+		if len(s.windows) == 0 || overwrite {
+			s.clock = clock
+		}
+		if s.windows == nil {
+			s.windows = make(map[string][]window)
+		}
+		for k, ws := range windows {
+			s.windows[k] = append(s.windows[k], ws...)
+		}
+	}
+	if len(defaults) > 0 {
+		// defaults are replaced, not modified. See Properties.defaults.
+		for k, v := range s.defaults {
+			if _, ok := defaults[k]; !ok || !overwrite {
+				defaults[k] = v
+			}
+		}
+		s.defaults = defaults
+	}
+	for k, r := range reps {
+		if _, dup := s.reps[k]; dup && !overwrite {
+			continue
+		}
+		if s.reps == nil {
+			s.reps = make(map[string]quotedRep)
+		}
+		s.reps[k] = r
+	}
+	for _, k := range secrets {
+		if s.secrets == nil {
+			s.secrets = make(map[string]bool)
+		}
+		s.secrets[k] = true
+	}
+	s.opaque = append(s.opaque, opaque...)
+	s.memoize = s.memoize || memoize
+}
@@ -0,0 +1,34 @@
+package gestalt
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSideTables(t *testing.T) {
+	live := make([]Properties, 20000)
+	for i := range live {
+		live[i] = Properties{"k": "v"}
+		live[i].MarkSecret("k")
+	}
+	for i, p := range live {
+		if s := sideOf(p); s == nil || !p.IsSecret("k") {
+			t.Fatalf("TestSideTables - sideOf(%d) - expected side table of the secret key", i)
+		}
+	}
+	if p := (Properties{"k": "v"}); sideOf(p) != nil || p.IsSecret("k") {
+		t.Errorf("TestSideTables - sideOf - expected no side table of new Properties")
+	}
+	runtime.KeepAlive(live)
+}
+
+func BenchmarkSideTables(b *testing.B) {
+	live := make([]Properties, 0, b.N)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := Properties{"k": "v"}
+		p.MarkSecret("k")
+		live = append(live, p)
+	}
+	runtime.KeepAlive(live)
+}
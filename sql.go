@@ -0,0 +1,85 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// type column values of SQLSource rows
+const (
+	SQLTypeString = "string"
+	SQLTypeArray  = "array"
+	SQLTypeMap    = "map"
+)
+
+// SQLSource is a Source loading properties from the rows of a database
+// query, e.g. a configuration table.
+//
+// The query must select (key, value) or (key, value, type) columns. Array
+// and map values are given per gestalt file syntax (e.g. "a, b" and
+// "k:v, k2:v2"). If selected, the type column is one of "string", "array",
+// or "map" (or NULL) and the corresponding key suffix is added to keys that
+// do not already have it.
+//
+// Use Watch to poll the database for changes.
+type SQLSource struct {
+	DB    *sql.DB
+	Query string
+	Args  []interface{}
+}
+
+// Returns a new SQLSource for the specified query and query args.
+func NewSQLSource(db *sql.DB, query string, args ...interface{}) *SQLSource {
+	return &SQLSource{DB: db, Query: query, Args: args}
+}
+
+// Load implements Source.
+func (s *SQLSource) Load(ctx context.Context) (p Properties, e error) {
+	rows, e := s.DB.QueryContext(ctx, s.Query, s.Args...)
+	if e != nil {
+		return nil, e
+	}
+	defer rows.Close()
+
+	columns, e := rows.Columns()
+	if e != nil {
+		return nil, e
+	}
+	if n := len(columns); n != 2 && n != 3 {
+		return nil, fmt.Errorf("query must select (key, value [, type]) columns - got %d columns", n)
+	}
+
+	p = make(Properties)
+	for rows.Next() {
+		var k, vrep string
+		var typ sql.NullString
+		dest := []interface{}{&k, &vrep, &typ}[:len(columns)]
+		if e = rows.Scan(dest...); e != nil {
+			return nil, e
+		}
+		switch typ.String {
+		case "", SQLTypeString:
+		case SQLTypeArray:
+			if !isArrayKey(k) {
+				k += array
+			}
+		case SQLTypeMap:
+			if !isMapKey(k) {
+				k += cmap
+			}
+		default:
+			return nil, &KeyError{k, fmt.Errorf("unknown type <%s>", typ.String)}
+		}
+		v, e := parseValue(k, vrep)
+		if e != nil {
+			return nil, &KeyError{k, e}
+		}
+		p[k] = v
+	}
+	return p, rows.Err()
+}
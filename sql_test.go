@@ -0,0 +1,134 @@
+package gestalt
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// a minimal in-memory sql driver serving a single (mutable) result set
+// ----------------------------------------------------------------------
+
+type fakeTable struct {
+	mu      sync.Mutex
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (t *fakeTable) set(columns []string, rows ...[]driver.Value) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.columns, t.rows = columns, rows
+}
+
+var fakeDB = &fakeTable{}
+
+func init() {
+	sql.Register("gestalt-fake", fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, io.EOF }
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                                    { return nil }
+func (fakeStmt) NumInput() int                                   { return -1 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) { return nil, io.EOF }
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	fakeDB.mu.Lock()
+	defer fakeDB.mu.Unlock()
+	return &fakeRows{columns: fakeDB.columns, rows: fakeDB.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// tests
+// ----------------------------------------------------------------------
+
+func TestSQLSource(t *testing.T) {
+	db, _ := sql.Open("gestalt-fake", "")
+	defer db.Close()
+	fakeDB.set([]string{"key", "value", "type"},
+		[]driver.Value{"db.host", "localhost", nil},
+		[]driver.Value{"hosts", "a, b", "array"},
+		[]driver.Value{"labels[:]", "env:prod", "map"},
+	)
+
+	p, e := NewSQLSource(db, "SELECT k, v, t FROM config").Load(context.Background())
+	if e != nil {
+		t.Fatalf("TestSQLSource - Load - %s", e)
+	}
+	if v := p.GetString("db.host"); v != "localhost" {
+		t.Errorf("TestSQLSource - GetString(db.host) - expected: localhost, got: %s", v)
+	}
+	if v := p.GetArray("hosts[]"); len(v) != 2 || v[1] != "b" {
+		t.Errorf("TestSQLSource - GetArray(hosts[]) - expected: [a b], got: %s", v)
+	}
+	if v := p.GetMap("labels[:]"); v["env"] != "prod" {
+		t.Errorf("TestSQLSource - GetMap(labels[:]) - expected: map[env:prod], got: %s", v)
+	}
+
+	fakeDB.set([]string{"key"}, []driver.Value{"a"})
+	if _, e := NewSQLSource(db, "SELECT k FROM config").Load(context.Background()); e == nil {
+		t.Errorf("TestSQLSource - Load - error expected for single column")
+	}
+}
+
+func TestWatchSQLSource(t *testing.T) {
+	db, _ := sql.Open("gestalt-fake", "")
+	defer db.Close()
+	fakeDB.set([]string{"key", "value"}, []driver.Value{"debug", "false"})
+
+	changes := make(chan Properties, 1)
+	w, e := Watch(context.Background(), NewSQLSource(db, "SELECT k, v FROM config"), time.Millisecond,
+		func(p Properties, e error) {
+			if e == nil {
+				changes <- p
+			}
+		})
+	if e != nil {
+		t.Fatalf("TestWatchSQLSource - Watch - %s", e)
+	}
+	defer w.Stop()
+
+	fakeDB.set([]string{"key", "value"}, []driver.Value{"debug", "true"})
+	select {
+	case p := <-changes:
+		if v := p.GetString("debug"); v != "true" {
+			t.Errorf("TestWatchSQLSource - change - expected: debug=true, got: %s", v)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("TestWatchSQLSource - change notification expected")
+	}
+	if v := w.Properties().GetString("debug"); v != "true" {
+		t.Errorf("TestWatchSQLSource - Properties - expected: debug=true, got: %s", v)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prompter provides values of keys, e.g. by asking the user.
+// See WithPrompter and Schema.WriteStarter.
+type Prompter interface {
+	// returns the value representation (per file syntax) of the spec'd key,
+	// or "" if none is provided (in which case the Default of spec applies).
+	Prompt(spec *KeySpec) (string, error)
+}
+
+// WithPrompter sets the Prompter of LoadStrict. nil pr is ignored.
+func WithPrompter(pr Prompter) LoadOption {
+	return func(o *loadOptions) {
+		o.prompter = pr
+	}
+}
+
+// Instantiates a new Properties object from the content of the specified
+// file, and validates it against schema. Values of missing required keys
+// are obtained from the Prompter, if set (see WithPrompter), or the key's
+// default if the Prompter provides none. The Secret keys of schema are
+// marked secret (see MarkSecret). Returns error if schema is nil, or the
+// file can not be loaded, or is not valid.
+func LoadStrict(filename string, schema *Schema, opts ...LoadOption) (Properties, error) {
+	if schema == nil {
+		return nil, errors.New("LoadStrict - schema is nil")
+	}
+	p, e := Load(filename, opts...)
+	if e != nil {
+		return nil, e
+	}
+
+	if pr := newLoadOptions(opts).prompter; pr != nil {
+		for i := range schema.Keys {
+			spec := &schema.Keys[i]
+			if !spec.Required || p.get(spec.Key) != nil {
+				continue
+			}
+			vrep, e := pr.Prompt(spec)
+			if e != nil {
+				return nil, &KeyError{spec.Key, e}
+			}
+			if vrep = strings.Trim(vrep, trimset); vrep == empty {
+				vrep = spec.Default
+			}
+			if vrep == empty {
+				continue
+			}
+			v, e := parseValue(spec.Key, vrep)
+			if e != nil {
+				return nil, &KeyError{spec.Key, e}
+			}
+			p[spec.Key] = v
+		}
+	}
+
+	for _, spec := range schema.Keys {
+		if spec.Secret {
+			p.MarkSecret(spec.Key)
+		}
+	}
+	if errs := schema.Validate(p); len(errs) > 0 {
+		newLoadOptions(opts).logger.Debug("gestalt: validation failed", "file", filename, "errors", len(errs))
+		return nil, fmt.Errorf("gestalt file <%s> is not valid - %w", filename, errors.Join(errs...))
+	}
+	return p, nil
+}
+
+// Returns a Prompter reading from the process's terminal, or nil if stdin
+// is not a terminal (TTY). Input of secret keys is not echoed, where
+// supported (via stty).
+func TTYPrompter() Prompter {
+	in, out, ok := terminal()
+	if !ok {
+		return nil
+	}
+	return &ttyPrompter{in: in, out: out}
+}
+
+type ttyPrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (t *ttyPrompter) Prompt(spec *KeySpec) (string, error) {
+	if spec.Doc != "" {
+		fmt.Fprintf(t.out, "# %s\n", spec.Doc)
+	}
+	if spec.Default != "" && !spec.Secret {
+		fmt.Fprintf(t.out, "%s [%s] = ", spec.Key, spec.Default)
+	} else {
+		fmt.Fprintf(t.out, "%s = ", spec.Key)
+	}
+
+	if spec.Secret && setEcho(false) == nil {
+		defer func() {
+			setEcho(true)
+			fmt.Fprintln(t.out)
+		}()
+	}
+	line, e := t.in.ReadString('\n')
+	if e != nil && e != io.EOF {
+		return "", e
+	}
+	return line, nil
+}
@@ -0,0 +1,49 @@
+package gestalt
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakePrompter map[string]string
+
+func (pr fakePrompter) Prompt(spec *KeySpec) (string, error) {
+	return pr[spec.Key], nil
+}
+
+func TestLoadStrict(t *testing.T) {
+	dir := writeFragments(t, map[string]string{"app.conf": "db.host = localhost\n"})
+	filename := dir + "/app.conf"
+	schema := &Schema{Keys: []KeySpec{
+		{Key: "db.host", Required: true},
+		{Key: "db.port", Type: TypeInt, Required: true},
+		{Key: "db.password", Required: true, Secret: true},
+	}}
+
+	if _, e := LoadStrict(filename, nil); e == nil {
+		t.Errorf("TestLoadStrict - LoadStrict(nil schema) - error expected")
+	}
+
+	_, e := LoadStrict(filename, schema)
+	var ke *KeyError
+	if e == nil || !errors.As(e, &ke) {
+		t.Errorf("TestLoadStrict - LoadStrict - KeyError expected, got: %v", e)
+	}
+
+	pr := fakePrompter{"db.port": "5432\n", "db.password": "s3cret"}
+	p, e := LoadStrict(filename, schema, WithPrompter(pr))
+	if e != nil {
+		t.Fatalf("TestLoadStrict - LoadStrict(prompter) - %s", e)
+	}
+	if v, _ := p.GetInt("db.port"); v != 5432 {
+		t.Errorf("TestLoadStrict - GetInt(db.port) - expected: 5432, got: %d", v)
+	}
+	if v := p.GetString("db.password"); v != "s3cret" {
+		t.Errorf("TestLoadStrict - GetString(db.password) - expected: s3cret, got: %s", v)
+	}
+
+	pr["db.port"] = "not a port"
+	if _, e := LoadStrict(filename, schema, WithPrompter(pr)); e == nil {
+		t.Errorf("TestLoadStrict - LoadStrict(prompter) - error expected for invalid answer")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// TenantConfig manages the configurations of tenants, each a base
+// configuration overlaid by the tenant's overrides (see OverlayStack).
+// Merged views are cached until invalidated.
+//
+// TenantConfig is safe for concurrent use.
+type TenantConfig struct {
+	base    Source
+	overlay func(id string) Source
+
+	mu    sync.Mutex
+	basep Properties
+	views map[string]Properties
+	gen   uint64 // incremented on invalidation of views
+}
+
+// Instantiates a new TenantConfig of the base source and the per-tenant
+// overlay sources (see e.g. TenantFiles). Returns error if base can not
+// be loaded.
+func NewTenantConfig(ctx context.Context, base Source, overlay func(id string) Source) (*TenantConfig, error) {
+	p, e := base.Load(ctx)
+	if e != nil {
+		return nil, e
+	}
+	return &TenantConfig{base: base, overlay: overlay, basep: p, views: make(map[string]Properties)}, nil
+}
+
+// Returns the configuration of the tenant: the base configuration
+// overlaid by the tenant's overrides. @unset keys of the overlay mask the
+// base. Returns error if the tenant's overlay can not be loaded.
+//
+// The returned object must not be modified.
+func (tc *TenantConfig) ForTenant(ctx context.Context, id string) (Properties, error) {
+	tc.mu.Lock()
+	view, gen, base := tc.views[id], tc.gen, tc.basep
+	tc.mu.Unlock()
+	if view != nil {
+		return view, nil
+	}
+
+	overlay, e := tc.overlay(id).Load(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("tenant <%s> - %w", id, e)
+	}
+	s := NewOverlayStack(base)
+	s.Push(overlay)
+	view = s.Flatten()
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.gen == gen {
+		tc.views[id] = view
+	}
+	return view, nil
+}
+
+// Invalidates the cached configuration of the tenant, e.g. on a change of
+// its overlay.
+func (tc *TenantConfig) Invalidate(id string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	delete(tc.views, id)
+	tc.gen++
+}
+
+// Reloads the base configuration, and invalidates all cached tenant
+// configurations. The base is retained if the reload fails.
+func (tc *TenantConfig) Reload(ctx context.Context) error {
+	p, e := tc.base.Load(ctx)
+	if e != nil {
+		return e
+	}
+	tc.OnChange(p, nil)
+	return nil
+}
+
+// OnChange replaces the base configuration with p, and invalidates all
+// cached tenant configurations. It is a ChangeFunc, e.g. of a Watcher
+// of the base source. Reload errors are ignored.
+func (tc *TenantConfig) OnChange(p Properties, e error) {
+	if e != nil {
+		return
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.basep = p
+	tc.views = make(map[string]Properties)
+	tc.gen++
+}
+
+// Returns the overlay sources of tenant files per pattern, with the tenant
+// id substituted for %s, e.g. "/etc/app/tenants/%s.conf". Tenants without
+// a file, or with an empty file, have no overrides. Ids containing path
+// separators or ".." are rejected.
+func TenantFiles(pattern string, opts ...LoadOption) func(id string) Source {
+	return func(id string) Source {
+		return SourceFunc(func(ctx context.Context) (Properties, error) {
+			if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+				return nil, fmt.Errorf("invalid tenant id <%s>", id)
+			}
+			filename := fmt.Sprintf(pattern, id)
+			o := newLoadOptions(opts)
+			s, e := readFile(o, filename)
+			if errors.Is(e, fs.ErrNotExist) {
+				return Properties{}, nil
+			}
+			if e != nil {
+				return nil, e
+			}
+			if strings.Trim(s, trimset) == empty {
+				return Properties{}, nil
+			}
+			o.dir = filepath.Dir(filename)
+			return loadBuffer(s, o)
+		})
+	}
+}
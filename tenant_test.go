@@ -0,0 +1,55 @@
+package gestalt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTenantConfig(t *testing.T) {
+	dir := writeFragments(t, map[string]string{
+		"base.conf":  "db.host = localhost\nlog.level = info\nfeature = on\n",
+		"acme.conf":  "db.host = acme.db\nfeature = @unset\n",
+		"empty.conf": "\n",
+	})
+	ctx := context.Background()
+	tc, e := NewTenantConfig(ctx, FileSource(filepath.Join(dir, "base.conf")), TenantFiles(filepath.Join(dir, "%s.conf")))
+	if e != nil {
+		t.Fatalf("TestTenantConfig - NewTenantConfig - %s", e)
+	}
+
+	acme, e := tc.ForTenant(ctx, "acme")
+	if e != nil || acme.GetString("db.host") != "acme.db" || acme.GetString("log.level") != "info" || acme.GetString("feature") != "" {
+		t.Errorf("TestTenantConfig - ForTenant(acme) - got: %v, %v", acme, e)
+	}
+	other, e := tc.ForTenant(ctx, "other")
+	if e != nil || other.GetString("db.host") != "localhost" {
+		t.Errorf("TestTenantConfig - ForTenant(other) - expected base, got: %v, %v", other, e)
+	}
+	if empty, e := tc.ForTenant(ctx, "empty"); e != nil || empty.GetString("db.host") != "localhost" {
+		t.Errorf("TestTenantConfig - ForTenant(empty) - expected base, got: %v, %v", empty, e)
+	}
+	if _, e := tc.ForTenant(ctx, "../base"); e == nil {
+		t.Errorf("TestTenantConfig - ForTenant(../base) - error expected")
+	}
+
+	// cached until invalidated
+	os.WriteFile(filepath.Join(dir, "acme.conf"), []byte("db.host = acme2.db\n"), 0644)
+	if v, _ := tc.ForTenant(ctx, "acme"); v.GetString("db.host") != "acme.db" {
+		t.Errorf("TestTenantConfig - ForTenant(acme) - expected cached view, got: %v", v)
+	}
+	tc.Invalidate("acme")
+	if v, _ := tc.ForTenant(ctx, "acme"); v.GetString("db.host") != "acme2.db" {
+		t.Errorf("TestTenantConfig - ForTenant(acme) - expected reloaded view, got: %v", v)
+	}
+
+	// base change invalidates all views
+	os.WriteFile(filepath.Join(dir, "base.conf"), []byte("db.host = localhost\nlog.level = debug\n"), 0644)
+	if e := tc.Reload(ctx); e != nil {
+		t.Fatalf("TestTenantConfig - Reload - %s", e)
+	}
+	if v, _ := tc.ForTenant(ctx, "acme"); v.GetString("log.level") != "debug" {
+		t.Errorf("TestTenantConfig - ForTenant(acme) - expected new base, got: %v", v)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tlsconf builds *tls.Config from a conventional group of
+// gestalt properties:
+//
+//	tls.cert        = /etc/app/server.crt   # PEM certificate (chain)
+//	tls.key         = /etc/app/server.key   # PEM private key of cert
+//	tls.ca[]        = /etc/app/ca.crt       # PEM CA certificates
+//	tls.min_version = 1.2                   # 1.0, 1.1, 1.2, or 1.3
+//	tls.client_auth = require_and_verify    # see ClientAuth
+//
+// All keys are optional. Paths are expanded per gestalt.Properties.GetPath.
+package tlsconf
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/alphazero/gestalt"
+)
+
+// conventional keys, relative to the group prefix
+const (
+	KeyCert       = "cert"
+	KeyKey        = "key"
+	KeyCA         = "ca[]"
+	KeyMinVersion = "min_version"
+	KeyClientAuth = "client_auth"
+)
+
+// Versions maps min_version values to TLS versions.
+var Versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ClientAuth maps client_auth values to client authentication policies.
+var ClientAuth = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// Returns the *tls.Config of the key group of prefix (e.g. "tls") of p.
+//
+// The CA certificates are the RootCAs (servers verified by clients) and
+// ClientCAs (clients verified by servers) of the config. Returns error if
+// a file can not be loaded, or a value is not valid.
+func Config(p gestalt.Properties, prefix string) (*tls.Config, error) {
+	key := func(k string) string { return prefix + "." + k }
+	conf := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	certFile, keyFile := p.GetString(key(KeyCert)), p.GetString(key(KeyKey))
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("tlsconf - %s and %s must be specified together", key(KeyCert), key(KeyKey))
+	}
+	if certFile != "" {
+		var e error
+		if certFile, e = p.GetPath(key(KeyCert), gestalt.PathExists); e != nil {
+			return nil, e
+		}
+		if keyFile, e = p.GetPath(key(KeyKey), gestalt.PathExists); e != nil {
+			return nil, e
+		}
+		cert, e := tls.LoadX509KeyPair(certFile, keyFile)
+		if e != nil {
+			return nil, fmt.Errorf("tlsconf - key <%s> - %s", key(KeyCert), e)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	if cas := p.GetArray(key(KeyCA)); len(cas) > 0 {
+		pool := x509.NewCertPool()
+		for i, ca := range cas {
+			ca, e := gestalt.ExpandPath(ca)
+			if e != nil {
+				return nil, fmt.Errorf("tlsconf - key <%s> - element %d - %s", key(KeyCA), i, e)
+			}
+			pem, e := os.ReadFile(ca)
+			if e != nil {
+				return nil, fmt.Errorf("tlsconf - key <%s> - element %d - %s", key(KeyCA), i, e)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("tlsconf - key <%s> - element %d - no PEM certificates in %s", key(KeyCA), i, ca)
+			}
+		}
+		conf.RootCAs, conf.ClientCAs = pool, pool
+	}
+
+	if p.GetString(key(KeyMinVersion)) != "" {
+		v, e := gestalt.GetEnum(p, key(KeyMinVersion), Versions)
+		if e != nil {
+			return nil, e
+		}
+		conf.MinVersion = v
+	}
+	if p.GetString(key(KeyClientAuth)) != "" {
+		v, e := gestalt.GetEnum(p, key(KeyClientAuth), ClientAuth)
+		if e != nil {
+			return nil, e
+		}
+		conf.ClientAuth = v
+	}
+	return conf, nil
+}
@@ -0,0 +1,85 @@
+package tlsconf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alphazero/gestalt"
+)
+
+// writes a self-signed certificate and its key to dir
+func writeCert(t *testing.T, dir string) (certFile, keyFile string) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gestalt.test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, e := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	keyDER, e := x509.MarshalECPrivateKey(key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	certFile, keyFile = filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	return
+}
+
+func TestConfig(t *testing.T) {
+	certFile, keyFile := writeCert(t, t.TempDir())
+	p, _ := gestalt.LoadStr(`
+tls.cert = ` + certFile + `
+tls.key = ` + keyFile + `
+tls.ca[] = ` + certFile + `
+tls.min_version = 1.3
+tls.client_auth = require_and_verify
+`)
+	conf, e := Config(p, "tls")
+	if e != nil {
+		t.Fatalf("TestConfig - Config - %s", e)
+	}
+	if len(conf.Certificates) != 1 || conf.RootCAs == nil || conf.ClientCAs == nil {
+		t.Errorf("TestConfig - Config - expected certificate and CAs, got: %v", conf)
+	}
+	if conf.MinVersion != tls.VersionTLS13 || conf.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("TestConfig - Config - expected: TLS 1.3 require_and_verify, got: %x %s", conf.MinVersion, conf.ClientAuth)
+	}
+
+	conf, e = Config(gestalt.Properties{}, "tls")
+	if e != nil || conf.MinVersion != tls.VersionTLS12 {
+		t.Errorf("TestConfig - Config(empty) - expected default config, got: %v, %v", conf, e)
+	}
+
+	for _, spec := range []string{
+		"tls.cert = " + certFile,
+		"tls.min_version = 1.4",
+		"tls.client_auth = maybe",
+		"tls.ca[] = " + keyFile,
+		"tls.cert = /nope.crt\ntls.key = /nope.key",
+	} {
+		p, _ := gestalt.LoadStr(spec + "\n")
+		if _, e := Config(p, "tls"); e == nil {
+			t.Errorf("TestConfig - Config(%q) - error expected", spec)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ----------------------------------------------------------------------
+// Tracing
+//
+// spans (and their attributes):
+//
+//	gestalt.load    file loads (file, bytes, keys)
+//	gestalt.fetch   loads of a TracedSource (source, keys)
+//	gestalt.reload  Watcher reload cycles (changed, version)
+//
+// failed operations record their error on the span.
+// ----------------------------------------------------------------------
+
+// Tracer is the subset of a tracer (e.g. of OpenTelemetry) used to trace
+// loads and reloads. Tracers of choice are easily adapted to it, e.g.
+// with an OpenTelemetry trace.Tracer t:
+//
+//	func (a otelTracer) Start(ctx context.Context, name string) (context.Context, gestalt.Span) {
+//		ctx, span := a.t.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+// where otelSpan calls span.SetAttributes, span.RecordError and span.SetStatus,
+// and span.End.
+type Tracer interface {
+	// starts a span named name, a child of the span of ctx (if any).
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation of a Tracer.
+type Span interface {
+	// value is a string, int, or bool.
+	SetAttribute(key string, value interface{})
+	RecordError(e error)
+	End()
+}
+
+// tracer of the package. Tracing is disabled by default.
+var tracer atomic.Pointer[Tracer]
+
+// SetTracer sets the Tracer of loads and reloads. nil t disables tracing,
+// which is the default.
+func SetTracer(t Tracer) {
+	if t == nil {
+		tracer.Store(nil)
+		return
+	}
+	tracer.Store(&t)
+}
+
+// WithTracer sets the Tracer of a load, overriding the tracer of the
+// package (see SetTracer).
+func WithTracer(t Tracer) LoadOption {
+	return func(o *loadOptions) {
+		o.tracer = t
+	}
+}
+
+// Returns a Source tracing the loads of src (e.g. of a provider) as
+// gestalt.fetch spans, with attribute source set to name. Spans are
+// children of the span of the ctx of the load, e.g. of a Watcher reload.
+func TracedSource(name string, src Source) Source {
+	return SourceFunc(func(ctx context.Context) (Properties, error) {
+		ctx, span := startSpan(ctx, nil, "gestalt.fetch")
+		defer span.End()
+		span.SetAttribute("source", name)
+		p, e := src.Load(ctx)
+		if e != nil {
+			span.RecordError(e)
+			return nil, e
+		}
+		span.SetAttribute("keys", len(p))
+		return p, nil
+	})
+}
+
+// starts a span with t, or with the tracer of the package if t is nil.
+func startSpan(ctx context.Context, t Tracer, name string) (context.Context, Span) {
+	if t == nil {
+		pt := tracer.Load()
+		if pt == nil {
+			return ctx, noopSpan{}
+		}
+		t = *pt
+	}
+	return t.Start(ctx, name)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) RecordError(e error)                        {}
+func (noopSpan) End()                                       {}
@@ -0,0 +1,98 @@
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeTracer records ended spans as "name attr=value ... [error]"
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+type fakeSpan struct {
+	t     *fakeTracer
+	name  string
+	attrs []string
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, &fakeSpan{t: t, name: name}
+}
+
+func (s *fakeSpan) SetAttribute(key string, value interface{}) {
+	s.attrs = append(s.attrs, fmt.Sprintf("%s=%v", key, value))
+}
+
+func (s *fakeSpan) RecordError(e error) { s.attrs = append(s.attrs, "error") }
+
+func (s *fakeSpan) End() {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	s.t.spans = append(s.t.spans, strings.Join(append([]string{s.name}, s.attrs...), " "))
+}
+
+func (t *fakeTracer) ended() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := append([]string(nil), t.spans...)
+	sort.Strings(spans)
+	return spans
+}
+
+func TestWithTracer(t *testing.T) {
+	tr := &fakeTracer{}
+	if _, e := Load("test/test.conf", WithTracer(tr)); e != nil {
+		t.Fatalf("TestWithTracer - Load - %s", e)
+	}
+	Load("test/no-such-file.conf", WithTracer(tr))
+
+	spans := tr.ended()
+	if len(spans) != 2 {
+		t.Fatalf("TestWithTracer - expected 2 spans, got: %q", spans)
+	}
+	if s := spans[0]; !strings.HasPrefix(s, "gestalt.load file=test/no-such-file.conf error") {
+		t.Errorf("TestWithTracer - failed load - got: %s", s)
+	}
+	if s := spans[1]; !strings.HasPrefix(s, "gestalt.load file=test/test.conf bytes=") || !strings.Contains(s, " keys=") {
+		t.Errorf("TestWithTracer - load - got: %s", s)
+	}
+}
+
+func TestSetTracer(t *testing.T) {
+	tr := &fakeTracer{}
+	SetTracer(tr)
+	defer SetTracer(nil)
+
+	fail := false
+	src := TracedSource("fake", SourceFunc(func(ctx context.Context) (Properties, error) {
+		if fail {
+			return nil, errors.New("source unavailable")
+		}
+		return Properties{"foo": "bar"}, nil
+	}))
+	events := make(chan error, 1)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) { events <- e })
+	if e != nil {
+		t.Fatalf("TestSetTracer - Watch - %s", e)
+	}
+	fail = true
+	w.Reload()
+	<-events
+	w.Stop()
+
+	expected := []string{
+		"gestalt.fetch source=fake error",
+		"gestalt.fetch source=fake keys=1",
+		"gestalt.reload error",
+	}
+	if spans := tr.ended(); strings.Join(spans, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("TestSetTracer - expected: %q, got: %q", expected, spans)
+	}
+}
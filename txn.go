@@ -0,0 +1,152 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SafeProperties is a container of Properties that are updated atomically,
+// in transactions (see Apply), and read via immutable snapshots. Readers
+// never observe partially applied updates, e.g. a new host with an old port.
+//
+// Each update is stamped with a new Version, and optionally recorded in
+// a History (see SetHistory).
+//
+// SafeProperties is safe for concurrent use.
+type SafeProperties struct {
+	mu      sync.Mutex // serializes updates
+	history *History
+	snap    atomic.Pointer[snapshot]
+}
+
+// a versioned snapshot of Properties
+type snapshot struct {
+	p Properties
+	v Version
+}
+
+// Instantiates a new SafeProperties container of a copy of p, at version 1.
+// nil p is treated as empty Properties.
+func NewSafeProperties(p Properties) *SafeProperties {
+	sp := &SafeProperties{}
+	sp.snap.Store(&snapshot{p.Clone(), Version{}.next(time.Now())})
+	return sp
+}
+
+// Returns the current snapshot. The returned object must not be modified.
+func (sp *SafeProperties) Properties() Properties {
+	return sp.snap.Load().p
+}
+
+// Returns the version of the current snapshot.
+func (sp *SafeProperties) Version() Version {
+	return sp.snap.Load().v
+}
+
+// Records subsequent updates in h. nil h disables recording.
+func (sp *SafeProperties) SetHistory(h *History) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.history = h
+}
+
+// Replaces the Properties with a copy of p, e.g. on reload.
+func (sp *SafeProperties) Replace(p Properties) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.commit("load", p.Clone())
+}
+
+// Merges from into the Properties, replacing existing keys. Existing array
+// values are merged with those of from per opts (see OrderedArrays), if any,
+// rather than replaced.
+func (sp *SafeProperties) Merge(from Properties, opts ...MergeOption) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	cur := sp.Properties()
+	next := cur.Clone()
+	next.Copy(from, true)
+	if o := newMergeOptions(opts); o.ordered {
+		for k := range from {
+			arrv, ok := cur.get(k).([]string)
+			farrv, fok := from.get(k).([]string)
+			if ok && fok {
+				next[k] = mergeArrays(arrv, farrv, o.unique)
+			}
+		}
+	}
+	return sp.commit("merge", next)
+}
+
+// commits next as a new version. Returns the error of recording the
+// version, if any, in which case next is committed nonetheless.
+func (sp *SafeProperties) commit(op string, next Properties) error {
+	cur := sp.snap.Load()
+	v := cur.v.next(time.Now())
+	sp.snap.Store(&snapshot{next, v})
+	return sp.history.record(v, op, cur.p, next)
+}
+
+// Applies the updates of fn atomically. If fn returns error, none of
+// its updates are applied, and the error is returned. Otherwise the
+// updates are committed, and visible to subsequent snapshots at once.
+// Transactions are serialized. Returns the error of recording the commit
+// in the History, if any.
+func (sp *SafeProperties) Apply(fn func(tx *Txn) error) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	tx := &Txn{base: sp.Properties(), updates: make(Properties)}
+	if e := fn(tx); e != nil {
+		return e
+	}
+	if len(tx.updates) == 0 {
+		return nil
+	}
+	next := tx.base.Clone()
+	for k, v := range tx.updates {
+		if v == nil {
+			delete(next, k)
+		} else {
+			next[k] = v
+		}
+	}
+	return sp.commit("apply", next)
+}
+
+// Txn is a transaction of SafeProperties. See SafeProperties.Apply.
+// A Txn is valid only for the duration of its Apply call.
+type Txn struct {
+	base    Properties
+	updates Properties // nil values are deletions
+}
+
+// Sets key to the value representation vrep, per file syntax (e.g.
+// "a, b" for array keys). Returns error if vrep is malformed.
+func (tx *Txn) Set(key string, vrep string) error {
+	v, e := parseValue(key, vrep)
+	if e != nil {
+		return e
+	}
+	tx.updates[key] = v
+	return nil
+}
+
+// Deletes key.
+func (tx *Txn) Delete(key string) {
+	tx.updates[key] = nil
+}
+
+// Returns the value of key, including the updates of the transaction,
+// or nil if no such key or key is @unset.
+func (tx *Txn) Get(key string) interface{} {
+	if v, ok := tx.updates[key]; ok {
+		return Properties{key: v}.get(key)
+	}
+	return tx.base.get(key)
+}
@@ -0,0 +1,71 @@
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSafePropertiesApply(t *testing.T) {
+	base, _ := LoadStr("db.host = a.local\ndb.port = 5432\nold = x\n")
+	sp := NewSafeProperties(base)
+
+	e := sp.Apply(func(tx *Txn) error {
+		if e := tx.Set("db.host", "b.local"); e != nil {
+			return e
+		}
+		if v := tx.Get("db.host"); v != "b.local" {
+			t.Errorf("TestSafePropertiesApply - Get(db.host) - expected: b.local, got: %v", v)
+		}
+		if v := sp.Properties().GetString("db.host"); v != "a.local" {
+			t.Errorf("TestSafePropertiesApply - uncommitted update visible: %s", v)
+		}
+		tx.Delete("old")
+		return tx.Set("db.port", "6543")
+	})
+	if e != nil {
+		t.Fatalf("TestSafePropertiesApply - Apply - %s", e)
+	}
+	p := sp.Properties()
+	if p.GetString("db.host") != "b.local" || p.GetString("db.port") != "6543" || p.GetString("old") != "" {
+		t.Errorf("TestSafePropertiesApply - Apply - unexpected Properties: %v", p)
+	}
+	if base.GetString("db.host") != "a.local" {
+		t.Errorf("TestSafePropertiesApply - Apply - source Properties modified")
+	}
+
+	// aborted transaction
+	e = sp.Apply(func(tx *Txn) error {
+		tx.Set("db.host", "c.local")
+		return errors.New("abort")
+	})
+	if e == nil || sp.Properties().GetString("db.host") != "b.local" {
+		t.Errorf("TestSafePropertiesApply - Apply - expected aborted transaction, got: %v", e)
+	}
+	if e := sp.Apply(func(tx *Txn) error { return tx.Set("m[:]", "bad") }); e == nil {
+		t.Errorf("TestSafePropertiesApply - Set(m[:], bad) - error expected")
+	}
+}
+
+func TestSafePropertiesConsistency(t *testing.T) {
+	sp := NewSafeProperties(Properties{"host": "h0", "port": "p0"})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 1000; i++ {
+			sp.Apply(func(tx *Txn) error {
+				tx.Set("host", fmt.Sprintf("h%d", i))
+				return tx.Set("port", fmt.Sprintf("p%d", i))
+			})
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		p := sp.Properties()
+		if p.GetString("host")[1:] != p.GetString("port")[1:] {
+			t.Fatalf("TestSafePropertiesConsistency - inconsistent snapshot: %v", p)
+		}
+	}
+	wg.Wait()
+}
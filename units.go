@@ -0,0 +1,107 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ----------------------------------------------------------------------
+// Units
+//
+// numeric values may carry a unit, e.g. `timeout = 500ms`, or
+// `cache.size = 1.5 GB`. Values are converted between units of the same
+// dimension:
+//
+//	time   ns, us, ms, s, min, h
+//	size   B, KB, MB, GB, TB (powers of 1000), KiB, MiB, GiB, TiB (of 1024)
+//	ratio  %
+//	rate   req/s, req/min, req/h
+//
+// values without a unit are in the requested (or schema) unit.
+// ----------------------------------------------------------------------
+
+type unit struct {
+	dim    string
+	factor float64 // to the base unit of dim
+}
+
+var units = map[string]unit{
+	"ns":      {"time", 1e-9},
+	"us":      {"time", 1e-6},
+	"ms":      {"time", 1e-3},
+	"s":       {"time", 1},
+	"min":     {"time", 60},
+	"h":       {"time", 3600},
+	"B":       {"size", 1},
+	"KB":      {"size", 1e3},
+	"MB":      {"size", 1e6},
+	"GB":      {"size", 1e9},
+	"TB":      {"size", 1e12},
+	"KiB":     {"size", 1 << 10},
+	"MiB":     {"size", 1 << 20},
+	"GiB":     {"size", 1 << 30},
+	"TiB":     {"size", 1 << 40},
+	"%":       {"ratio", 1},
+	"req/s":   {"rate", 1},
+	"req/min": {"rate", 1.0 / 60},
+	"req/h":   {"rate", 1.0 / 3600},
+}
+
+// Numeric value property in unit, e.g.
+//
+//	timeout, e := p.GetQuantity("timeout", "ms")   // 1500 of "1.5s"
+//
+// Returns error if no such key, unit is unknown, or the value is not a
+// number (per the Locale option, if any) or its unit is not of the
+// dimension of unit.
+func (p Properties) GetQuantity(key string, unit string, opts ...GetOption) (float64, error) {
+	s, e := p.stringValue(key)
+	if e != nil {
+		return 0, e
+	}
+	q, e := newGetOptions(opts).parseQuantity(s, unit)
+	if e != nil {
+		return 0, &KeyError{key, e}
+	}
+	return q, nil
+}
+
+// returns the value of s (number and optional unit) in unit to, per the
+// locale of the options, if any
+func (o *getOptions) parseQuantity(s string, to string) (float64, error) {
+	tu, ok := units[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit <%s>", to)
+	}
+	numeric := "0123456789.+-eE"
+	if o.locale != nil {
+		numeric = "0123456789+-eE" + o.locale.Group + string(o.locale.Decimal)
+	}
+	s = strings.Trim(s, ws)
+	i := strings.IndexFunc(s, func(c rune) bool {
+		return !strings.ContainsRune(numeric, c)
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	num, us := strings.Trim(s[:i], ws), strings.Trim(s[i:], ws)
+	n, e := o.parseFloat(num)
+	if e != nil {
+		return 0, fmt.Errorf("value <%s> is not a number", s)
+	}
+	if us == empty {
+		return n, nil
+	}
+	u, ok := units[us]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit <%s> of value <%s>", us, s)
+	}
+	if u.dim != tu.dim {
+		return 0, fmt.Errorf("unit mismatch - value <%s> is %s, expected %s (%s)", s, u.dim, tu.dim, to)
+	}
+	return n * u.factor / tu.factor, nil
+}
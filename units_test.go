@@ -0,0 +1,68 @@
+package gestalt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetQuantity(t *testing.T) {
+	p := Properties{
+		"timeout":    "1.5s",
+		"interval":   "250",
+		"cache.size": "2 KiB",
+		"rate":       "120 req/min",
+		"load":       "75%",
+		"bad":        "fast",
+	}
+	for _, c := range []struct {
+		key, unit string
+		expected  float64
+	}{
+		{"timeout", "ms", 1500},
+		{"timeout", "s", 1.5},
+		{"interval", "ms", 250},
+		{"cache.size", "B", 2048},
+		{"rate", "req/s", 2},
+		{"load", "%", 75},
+	} {
+		if v, e := p.GetQuantity(c.key, c.unit); e != nil || v != c.expected {
+			t.Errorf("TestGetQuantity - GetQuantity(%s, %s) - expected: %g, got: %g, %v", c.key, c.unit, c.expected, v, e)
+		}
+	}
+
+	for _, c := range []struct{ key, unit, expected string }{
+		{"timeout", "MB", "unit mismatch"},
+		{"timeout", "parsecs", "unknown unit <parsecs>"},
+		{"bad", "s", "not a number"},
+		{"none", "s", "no such key"},
+	} {
+		if _, e := p.GetQuantity(c.key, c.unit); e == nil || !strings.Contains(e.Error(), c.expected) {
+			t.Errorf("TestGetQuantity - GetQuantity(%s, %s) - expected error: %s, got: %v", c.key, c.unit, c.expected, e)
+		}
+	}
+}
+
+func TestSchemaUnit(t *testing.T) {
+	schema, e := LoadSchemaStr("[document:timeout]\nunit = ms\n")
+	if e != nil {
+		t.Fatalf("TestSchemaUnit - LoadSchemaStr - %s", e)
+	}
+	schema.Keys[0].Check = func(v interface{}) error {
+		if v.(float64) > 5000 {
+			return errors.New("timeout exceeds 5s")
+		}
+		return nil
+	}
+	if errs := schema.Validate(Properties{"timeout": "2s"}); len(errs) != 0 {
+		t.Errorf("TestSchemaUnit - Validate(2s) - %v", errs)
+	}
+	for v, expected := range map[string]string{"10s": "exceeds", "5MB": "unit mismatch", "soon": "not a number"} {
+		if errs := schema.Validate(Properties{"timeout": v}); len(errs) != 1 || !strings.Contains(errs[0].Error(), expected) {
+			t.Errorf("TestSchemaUnit - Validate(%s) - expected: %s, got: %v", v, expected, errs)
+		}
+	}
+	if _, e := LoadSchemaStr("[document:timeout]\nunit = fortnights\n"); e == nil {
+		t.Errorf("TestSchemaUnit - unknown unit - error expected")
+	}
+}
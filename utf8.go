@@ -0,0 +1,66 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is the error of content with invalid UTF-8 bytes, per
+// UTF8Error.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// UTF8Policy is the handling of invalid UTF-8 bytes of loaded content, e.g.
+// of files of legacy systems in Latin-1. See InvalidUTF8.
+type UTF8Policy int
+
+const (
+	UTF8Replace UTF8Policy = iota // bytes are replaced with U+FFFD (the default)
+	UTF8Error                     // the load fails with ErrInvalidUTF8, at the offset of the first invalid byte
+	UTF8Raw                       // bytes are passed through as is
+)
+
+var utf8_policy_names = [...]string{
+	UTF8Replace: "replace",
+	UTF8Error:   "error",
+	UTF8Raw:     "raw",
+}
+
+func (up UTF8Policy) String() string {
+	if up < 0 || int(up) >= len(utf8_policy_names) {
+		return fmt.Sprintf("UTF8Policy(%d)", int(up))
+	}
+	return utf8_policy_names[up]
+}
+
+// InvalidUTF8 sets the handling of invalid UTF-8 bytes of loaded content.
+// By default invalid bytes are replaced with U+FFFD (utf8.RuneError), so
+// that keys and values of legacy encodings are silently altered; UTF8Error
+// rejects such content, and UTF8Raw preserves the bytes, e.g. for values
+// decoded by the application.
+func InvalidUTF8(policy UTF8Policy) LoadOption {
+	return func(o *loadOptions) {
+		o.utf8Policy = policy
+	}
+}
+
+// returns error if s has invalid UTF-8 bytes and the policy is UTF8Error
+func (o *loadOptions) checkUTF8(s string) error {
+	if o.utf8Policy != UTF8Error || utf8.ValidString(s) {
+		return nil
+	}
+	for off, c := range s {
+		if c == utf8.RuneError {
+			if _, n := utf8.DecodeRuneInString(s[off:]); n == 1 {
+				line := strings.Count(s[:off], "\n") + 1
+				return fmt.Errorf("%w - byte 0x%02x at offset %d (line %d)", ErrInvalidUTF8, s[off], off, line)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package gestalt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestInvalidUTF8(t *testing.T) {
+	// Latin-1 encoded "café"
+	spec := "a = ok\ncaf\xe9 = caf\xe9 # Latin-1\n"
+
+	p, e := LoadStr(spec)
+	if e != nil || p.GetString("caf�") != "caf�" {
+		t.Errorf("TestInvalidUTF8 - default - expected replaced key and value, got: %v, %v", p, e)
+	}
+	p, e = LoadStr(spec, InvalidUTF8(UTF8Replace))
+	if e != nil || p.GetString("caf�") != "caf�" {
+		t.Errorf("TestInvalidUTF8 - UTF8Replace - expected replaced key and value, got: %v, %v", p, e)
+	}
+
+	p, e = LoadStr(spec, InvalidUTF8(UTF8Raw))
+	if e != nil || p.GetString("caf\xe9") != "caf\xe9" || p.GetString("a") != "ok" {
+		t.Errorf("TestInvalidUTF8 - UTF8Raw - expected raw key and value, got: %v, %v", p, e)
+	}
+
+	_, e = LoadStr(spec, InvalidUTF8(UTF8Error))
+	if !errors.Is(e, ErrInvalidUTF8) || !strings.Contains(e.Error(), "byte 0xe9 at offset 10 (line 2)") {
+		t.Errorf("TestInvalidUTF8 - UTF8Error - expected ErrInvalidUTF8 at offset 10, got: %v", e)
+	}
+	if p, e := LoadStr("clé = välue", InvalidUTF8(UTF8Error)); e != nil || p.GetString("clé") != "välue" {
+		t.Errorf("TestInvalidUTF8 - UTF8Error - valid content - got: %v, %v", p, e)
+	}
+
+	if s := UTF8Raw.String(); s != "raw" {
+		t.Errorf("TestInvalidUTF8 - UTF8Raw.String() - expected: raw, got: %s", s)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// validation checks
+const (
+	CheckParse         = "parse"
+	CheckSchema        = "schema"
+	CheckInterpolation = "interpolation"
+)
+
+// Report is the (JSON friendly) result of ValidateFiles.
+type Report struct {
+	OK    bool         `json:"ok"`
+	Files []FileReport `json:"files"`
+}
+
+// FileReport is the validation result of a single file.
+type FileReport struct {
+	Path     string    `json:"path"`
+	OK       bool      `json:"ok"`
+	Problems []Problem `json:"problems,omitempty"`
+}
+
+// Problem is a single validation failure.
+type Problem struct {
+	Check   string `json:"check"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// Validates the specified files without otherwise using them, e.g. as a
+// dry-run gate in CI. Each file is checked for parseability, for unresolved
+// ${key} references (see Interpolate), and, if schema is not nil,
+// conformance to schema. References of a scheme, e.g. ${env:HOME}, are
+// resolved by Interpolators at run time, and are not checked. Files have
+// no includes, i.e. there is no include integrity to check.
+func ValidateFiles(paths []string, schema *Schema, opts ...LoadOption) Report {
+	report := Report{OK: true, Files: []FileReport{}}
+	for _, path := range paths {
+		fr := FileReport{Path: path}
+		p, e := Load(path, opts...)
+		if e != nil {
+			fr.Problems = append(fr.Problems, Problem{Check: CheckParse, Message: e.Error()})
+		} else {
+			fr.Problems = append(fr.Problems, checkRefs(p)...)
+		}
+		if e == nil && schema != nil {
+			for _, e := range schema.Validate(p) {
+				problem := Problem{Check: CheckSchema, Message: e.Error()}
+				var ke *KeyError
+				if errors.As(e, &ke) {
+					problem.Key, problem.Message = ke.Key, ke.Err.Error()
+				}
+				fr.Problems = append(fr.Problems, problem)
+			}
+		}
+		fr.OK = len(fr.Problems) == 0
+		report.OK = report.OK && fr.OK
+		report.Files = append(report.Files, fr)
+	}
+	return report
+}
+
+// returns the problems of the unresolved, cyclic, or otherwise invalid
+// ${key} references of the values of p, by key
+func checkRefs(p Properties) []Problem {
+	schemed := InterpolatorFunc(func(ref string) (string, bool, error) {
+		return "", strings.Contains(ref, kv_delim), nil
+	})
+	s := &OverlayStack{layers: []Properties{p}, chain: []Interpolator{schemed}}
+	var problems []Problem
+	for _, k := range sortedKeys(p) {
+		if isUnset(p[k]) {
+			continue
+		}
+		if _, e := s.interpolate(k, nil); e != nil {
+			problems = append(problems, Problem{Check: CheckInterpolation, Key: k, Message: e.Error()})
+		}
+	}
+	return problems
+}
+
+// Returns the JSON encoding of the report.
+func (r Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
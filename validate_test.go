@@ -0,0 +1,47 @@
+package gestalt
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateFiles(t *testing.T) {
+	dir := writeFragments(t, map[string]string{
+		"bad.conf":  "foo bar\n",
+		"ok.conf":   "port = 8080\n",
+		"refs.conf": "port = 8080\nhome = ${env:HOME}\nurl = http://${host}:${port}\n",
+	})
+	schema := &Schema{Keys: []KeySpec{{Key: "port", Type: TypeInt, Required: true}}}
+
+	paths := []string{"test/test.conf", filepath.Join(dir, "bad.conf"), filepath.Join(dir, "ok.conf")}
+	report := ValidateFiles(paths[1:], schema)
+	if report.OK || len(report.Files) != 2 {
+		t.Errorf("TestValidateFiles - ValidateFiles - expected: 2 files, not ok, got: %v", report)
+	}
+	if fr := report.Files[0]; fr.OK || fr.Problems[0].Check != CheckParse {
+		t.Errorf("TestValidateFiles - ValidateFiles(bad.conf) - expected: parse problem, got: %v", fr)
+	}
+	if fr := report.Files[1]; !fr.OK {
+		t.Errorf("TestValidateFiles - ValidateFiles(ok.conf) - expected: ok, got: %v", fr)
+	}
+
+	report = ValidateFiles([]string{filepath.Join(dir, "refs.conf")}, schema)
+	if fr := report.Files[0]; fr.OK || len(fr.Problems) != 1 || fr.Problems[0].Check != CheckInterpolation || fr.Problems[0].Key != "url" {
+		t.Errorf("TestValidateFiles - ValidateFiles(refs.conf) - expected: interpolation problem for url, got: %v", fr)
+	}
+
+	report = ValidateFiles(paths[:1], schema)
+	if report.OK || report.Files[0].Problems[0].Key != "port" {
+		t.Errorf("TestValidateFiles - ValidateFiles(test.conf) - expected: schema problem for port, got: %v", report)
+	}
+
+	b, e := report.JSON()
+	if e != nil {
+		t.Errorf("TestValidateFiles - JSON - %s", e)
+	}
+	var decoded Report
+	if e := json.Unmarshal(b, &decoded); e != nil || decoded.OK {
+		t.Errorf("TestValidateFiles - JSON - round trip failed: %s", e)
+	}
+}
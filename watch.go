@@ -0,0 +1,406 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Source is a (re)loadable source of Properties, e.g. a file, or a
+// database table. See Watch.
+type Source interface {
+	Load(ctx context.Context) (Properties, error)
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc func(ctx context.Context) (Properties, error)
+
+func (f SourceFunc) Load(ctx context.Context) (Properties, error) {
+	return f(ctx)
+}
+
+// Returns a Source loading the specified file.
+func FileSource(filename string, opts ...LoadOption) Source {
+	return SourceFunc(func(ctx context.Context) (Properties, error) {
+		return Load(filename, opts...)
+	})
+}
+
+// ChangeFunc is notified of changes of watched Properties, or of the
+// error of a failed reload.
+type ChangeFunc func(p Properties, e error)
+
+// ErrRejected is the error of reloaded Properties rejected by a Validator
+// of a Watcher.
+var ErrRejected = errors.New("reload rejected")
+
+// ErrPartial is the error of reloaded Properties accepted in part. See
+// AcceptPartial.
+var ErrPartial = errors.New("reload partially accepted")
+
+// Validator approves newly loaded Properties (p) before a Watcher applies
+// them, e.g. checking them against a schema, or against the current
+// Properties (nil on the initial load). A non-nil error rejects p.
+type Validator func(p Properties, current Properties) error
+
+// Returns a Validator of the schema. See Schema.Validate.
+func SchemaValidator(s *Schema) Validator {
+	return func(p Properties, current Properties) error {
+		return errors.Join(s.Validate(p)...)
+	}
+}
+
+// WatchOption is an option of Watch.
+type WatchOption func(*Watcher)
+
+// WithValidators appends validators to the Validator chain of a Watcher.
+// All validators must approve reloaded Properties, in order, before they
+// are applied.
+func WithValidators(validators ...Validator) WatchOption {
+	return func(w *Watcher) {
+		w.validators = append(w.validators, validators...)
+	}
+}
+
+// WithPolicy enforces policy on the loads and reloads of a Watcher, after
+// its validators. Reloaded Properties violating policy are rejected.
+func WithPolicy(policy Policy) WatchOption {
+	return func(w *Watcher) {
+		w.policy = policy
+	}
+}
+
+// WithHistory records the versions of a Watcher in h. Errors of recording
+// are ignored.
+func WithHistory(h *History) WatchOption {
+	return func(w *Watcher) {
+		w.history = h
+	}
+}
+
+// WatchClock sets the Clock of a Watcher, of its polling and versions,
+// e.g. a ManualClock for tests.
+func WatchClock(c Clock) WatchOption {
+	return func(w *Watcher) {
+		w.clock = c
+	}
+}
+
+// AcceptPartial sets the partial acceptance policy of a Watcher: reloaded
+// Properties rejected only for specific keys (i.e. all errors of the
+// rejection are KeyErrors, e.g. of a SchemaValidator) are applied with
+// the current values of the invalid keys retained (or the keys removed,
+// if not currently defined), provided the result is approved by all
+// validators. fn is then called with both the applied Properties and
+// the error of the invalid keys (ErrPartial).
+func AcceptPartial() WatchOption {
+	return func(w *Watcher) {
+		w.partial = true
+	}
+}
+
+// Debounce delays reloads requested with Watcher#Reload (e.g. by change
+// notifications of files written several times in quick succession) until
+// no further reload is requested for quiet, coalescing the requests into
+// a single reload.
+func Debounce(quiet time.Duration) WatchOption {
+	return func(w *Watcher) {
+		w.quiet = quiet
+	}
+}
+
+// RateLimit limits the reloads of a Watcher to at most one every d.
+// Reloads requested (or polled) sooner are delayed, and coalesced.
+func RateLimit(d time.Duration) WatchOption {
+	return func(w *Watcher) {
+		w.every = d
+	}
+}
+
+// Watcher periodically reloads a Source and notifies its ChangeFunc
+// if the loaded Properties have changed. Each applied change is stamped
+// with a new Version.
+type Watcher struct {
+	src        Source
+	fn         ChangeFunc
+	validators []Validator
+	policy     Policy
+	history    *History
+	clock      Clock
+	partial    bool          // see AcceptPartial
+	quiet      time.Duration // see Debounce
+	every      time.Duration // see RateLimit
+	ttl        time.Duration // see StaleAfter
+	last       time.Time     // of the latest reload
+
+	mu      sync.RWMutex
+	current Properties
+	version Version
+	loaded  time.Time // of the latest successful load (see Health)
+	err     error     // of the latest load, if failed
+	invalid error     // of the validation of the latest load, if failed
+
+	reload chan struct{}
+	done   chan struct{}
+	stop   sync.Once
+	cancel context.CancelFunc
+}
+
+// Loads src and watches it for changes, polling every interval, until ctx
+// is done or the Watcher is stopped. A non-positive interval disables
+// polling, and src is then only reloaded on demand (see Watcher#Reload).
+//
+// fn (if not nil) is called with the newly loaded Properties when they differ
+// from the current Properties, or with the error when a reload fails, in
+// which case the current Properties are retained. Calls to fn are serialized.
+//
+// Reloaded Properties rejected by a Validator (see WithValidators), or
+// violating the Policy of the Watcher (see WithPolicy), are not applied, and fn is
+// called with the error of the rejection (ErrRejected), unless accepted in
+// part (see AcceptPartial).
+//
+// Returns error if the initial load fails, or is rejected.
+func Watch(ctx context.Context, src Source, interval time.Duration, fn ChangeFunc, opts ...WatchOption) (*Watcher, error) {
+	w := &Watcher{
+		src:    src,
+		fn:     fn,
+		reload: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		clock:  SystemClock,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	p, e := src.Load(ctx)
+	if e != nil {
+		return nil, e
+	}
+	if e := w.validate(ctx, p); e != nil {
+		return nil, e
+	}
+	w.current, w.version = p, Version{}.next(w.clock.Now())
+	w.loaded = w.version.Time
+	w.history.record(w.version, "load", nil, p)
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	// the ticker is started before returning, so that ticks of a
+	// (manual) clock advanced after Watch returns are not missed.
+	var tick <-chan time.Time
+	stop := func() {}
+	if interval > 0 {
+		tick, stop = w.clock.NewTicker(interval)
+	}
+	go w.loop(ctx, tick, stop)
+	return w, nil
+}
+
+// Returns the current Properties. The returned object must not be modified.
+func (w *Watcher) Properties() Properties {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Returns the version of the current Properties.
+func (w *Watcher) Version() Version {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.version
+}
+
+// Requests an immediate reload of the source, e.g. on a change notification
+// of the source. Does not block.
+func (w *Watcher) Reload() {
+	select {
+	case w.reload <- struct{}{}:
+	default:
+	}
+}
+
+// Stops watching. Blocks until the watch loop has exited.
+func (w *Watcher) Stop() {
+	w.stop.Do(w.cancel)
+	<-w.done
+}
+
+func (w *Watcher) loop(ctx context.Context, tick <-chan time.Time, stop func()) {
+	defer close(w.done)
+	defer stop()
+
+	for {
+		debounce := false
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case <-w.reload:
+			debounce = w.quiet > 0
+		}
+		if debounce && !w.wait(ctx, w.quiet, true) {
+			return
+		}
+		if w.every > 0 && !w.last.IsZero() {
+			if d := w.every - w.clock.Now().Sub(w.last); d > 0 && !w.wait(ctx, d, false) {
+				return
+			}
+		}
+		w.last = w.clock.Now()
+		w.update(ctx)
+	}
+}
+
+// waits for d, coalescing reload requests meanwhile, and restarting the
+// wait on each request if restart. Returns false if ctx is done.
+func (w *Watcher) wait(ctx context.Context, d time.Duration, restart bool) bool {
+	deadline := w.clock.Now().Add(d)
+	for {
+		tick, stop := w.clock.NewTicker(d)
+		select {
+		case <-ctx.Done():
+			stop()
+			return false
+		case <-tick:
+			stop()
+			return true
+		case <-w.reload:
+			stop()
+			if !restart {
+				// coalesced. wait for the remainder
+				if d = deadline.Sub(w.clock.Now()); d <= 0 {
+					return true
+				}
+			}
+		}
+	}
+}
+
+func (w *Watcher) update(ctx context.Context) {
+	log().Debug("gestalt: watch fired")
+	sctx, span := startSpan(ctx, nil, "gestalt.reload")
+	defer span.End()
+
+	p, e := w.src.Load(sctx)
+	if ctx.Err() != nil {
+		return
+	}
+	if e != nil {
+		log().Debug("gestalt: reload failed", "error", e)
+		span.RecordError(e)
+		w.loadDone(w.clock.Now(), false, e, nil)
+		w.notify(nil, e)
+		return
+	}
+	if equal(w.Properties(), p) {
+		span.SetAttribute("changed", false)
+		w.loadDone(w.clock.Now(), true, nil, nil)
+		return
+	}
+	var partial, invalid error
+	if invalid = w.validate(sctx, p); invalid != nil {
+		log().Debug("gestalt: validation failed", "error", invalid)
+		span.RecordError(invalid)
+		if p, partial = w.salvage(sctx, p, invalid); p == nil {
+			w.loadDone(w.clock.Now(), false, invalid, invalid)
+			w.notify(nil, invalid)
+			return
+		}
+	}
+	w.mu.Lock()
+	prev := w.current
+	w.current, w.version = p, w.version.next(w.clock.Now())
+	v := w.version
+	w.mu.Unlock()
+	span.SetAttribute("changed", true)
+	span.SetAttribute("version", int(v.N))
+	w.history.record(v, "reload", prev, p)
+	w.loadDone(v.Time, true, partial, invalid)
+	w.notify(p, partial)
+}
+
+// returns p with the keys invalidated by the rejection e reverted to their
+// current values, and the error of the keys, if partial acceptance applies.
+// Returns nil otherwise.
+func (w *Watcher) salvage(ctx context.Context, p Properties, e error) (Properties, error) {
+	if !w.partial {
+		return nil, nil
+	}
+	var errs []error
+	if !keyErrors(e, &errs) || len(errs) == 0 {
+		return nil, nil
+	}
+	current := w.Properties()
+	q := p.Clone()
+	for _, ke := range errs {
+		k := ke.(*KeyError).Key
+		if v, ok := current[k]; ok {
+			q[k] = v
+		} else {
+			delete(q, k)
+		}
+	}
+	if equal(current, q) || w.validate(ctx, q) != nil {
+		return nil, nil
+	}
+	return q, fmt.Errorf("%w - %w", ErrPartial, errors.Join(errs...))
+}
+
+// collects the KeyErrors of (the tree of) e. Returns false if e includes
+// errors other than KeyErrors (and ErrRejected).
+func keyErrors(e error, errs *[]error) bool {
+	switch e := e.(type) {
+	case *KeyError:
+		*errs = append(*errs, e)
+		return true
+	case interface{ Unwrap() []error }:
+		for _, e := range e.Unwrap() {
+			if e != ErrRejected && !keyErrors(e, errs) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// returns the error of the first validator rejecting p, if any, or of
+// the violations of the Policy of w, if any.
+func (w *Watcher) validate(ctx context.Context, p Properties) error {
+	current := w.Properties()
+	for i, v := range w.validators {
+		if e := v(p, current); e != nil {
+			return fmt.Errorf("%w by validator %d - %w", ErrRejected, i, e)
+		}
+	}
+	if e := CheckPolicy(ctx, w.policy, p); e != nil {
+		return fmt.Errorf("%w by policy - %w", ErrRejected, e)
+	}
+	return nil
+}
+
+func (w *Watcher) notify(p Properties, e error) {
+	if w.fn != nil {
+		w.fn(p, e)
+	}
+}
+
+// returns true if p and q define the same values
+func equal(p, q Properties) bool {
+	if len(p) != len(q) {
+		return false
+	}
+	for k, v := range p {
+		qv, ok := q[k]
+		if !ok || !reflect.DeepEqual(v, qv) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,229 @@
+package gestalt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	var n int32
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		switch atomic.AddInt32(&n, 1) {
+		case 1:
+			return Properties{"foo": "bar"}, nil
+		case 2:
+			return nil, errors.New("source unavailable")
+		}
+		return Properties{"foo": "baz"}, nil
+	})
+
+	events := make(chan error, 4)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) {
+		events <- e
+	})
+	if e != nil {
+		t.Fatalf("TestWatch - Watch - %s", e)
+	}
+	defer w.Stop()
+
+	w.Reload()
+	if e := <-events; e == nil {
+		t.Errorf("TestWatch - Reload - error expected")
+	}
+	if v := w.Properties().GetString("foo"); v != "bar" {
+		t.Errorf("TestWatch - Properties after failed reload - expected: bar, got: %s", v)
+	}
+
+	w.Reload()
+	select {
+	case e := <-events:
+		if e != nil {
+			t.Errorf("TestWatch - Reload - %s", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestWatch - Reload - change notification expected")
+	}
+	if v := w.Properties().GetString("foo"); v != "baz" {
+		t.Errorf("TestWatch - Properties - expected: baz, got: %s", v)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	p, e := FileSource("test/test.conf").Load(context.Background())
+	if e != nil || p.GetString("prop one") != "prop one value" {
+		t.Errorf("TestFileSource - Load - %v", e)
+	}
+}
+
+func TestWatchValidators(t *testing.T) {
+	var n int32
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		switch atomic.AddInt32(&n, 1) {
+		case 1:
+			return Properties{"port": "8080"}, nil
+		case 2:
+			return Properties{"port": "http"}, nil
+		case 3:
+			return Properties{"port": "9090", "canary": "fail"}, nil
+		}
+		return Properties{"port": "9090"}, nil
+	})
+	schema := &Schema{Keys: []KeySpec{{Key: "port", Type: TypeInt, Required: true}}}
+	canary := func(p Properties, current Properties) error {
+		if current != nil && current.GetString("port") != "8080" {
+			return errors.New("unexpected current Properties")
+		}
+		if p.GetString("canary") == "fail" {
+			return errors.New("canary failed")
+		}
+		return nil
+	}
+
+	events := make(chan error, 4)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) {
+		events <- e
+	}, WithValidators(SchemaValidator(schema), canary))
+	if e != nil {
+		t.Fatalf("TestWatchValidators - Watch - %s", e)
+	}
+	defer w.Stop()
+
+	for _, expected := range []string{"validator 0", "canary failed"} {
+		w.Reload()
+		if e := <-events; !errors.Is(e, ErrRejected) || !strings.Contains(e.Error(), expected) {
+			t.Errorf("TestWatchValidators - Reload - expected rejection: %s, got: %v", expected, e)
+		}
+		if v := w.Properties().GetString("port"); v != "8080" {
+			t.Errorf("TestWatchValidators - Properties after rejection - expected: 8080, got: %s", v)
+		}
+	}
+	w.Reload()
+	if e := <-events; e != nil {
+		t.Errorf("TestWatchValidators - Reload - %s", e)
+	}
+	if v := w.Properties().GetString("port"); v != "9090" {
+		t.Errorf("TestWatchValidators - Properties - expected: 9090, got: %s", v)
+	}
+
+	invalid := SourceFunc(func(ctx context.Context) (Properties, error) { return Properties{}, nil })
+	if _, e := Watch(context.Background(), invalid, 0, nil, WithValidators(SchemaValidator(schema))); !errors.Is(e, ErrRejected) {
+		t.Errorf("TestWatchValidators - Watch - expected initial rejection, got: %v", e)
+	}
+}
+
+func TestWatchDebounce(t *testing.T) {
+	var n int32
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		return Properties{"n": fmt.Sprint(atomic.AddInt32(&n, 1))}, nil
+	})
+	events := make(chan Properties, 4)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) {
+		events <- p
+	}, Debounce(50*time.Millisecond))
+	if e != nil {
+		t.Fatalf("TestWatchDebounce - Watch - %s", e)
+	}
+	defer w.Stop()
+
+	for i := 0; i < 5; i++ {
+		w.Reload()
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case p := <-events:
+		if v := p.GetString("n"); v != "2" {
+			t.Errorf("TestWatchDebounce - expected a single reload: n = 2, got: n = %s", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestWatchDebounce - reload expected")
+	}
+	select {
+	case p := <-events:
+		t.Errorf("TestWatchDebounce - unexpected reload: %v", p)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatchRateLimit(t *testing.T) {
+	var n int32
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		return Properties{"n": fmt.Sprint(atomic.AddInt32(&n, 1))}, nil
+	})
+	events := make(chan time.Time, 4)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) {
+		events <- time.Now()
+	}, RateLimit(100*time.Millisecond))
+	if e != nil {
+		t.Fatalf("TestWatchRateLimit - Watch - %s", e)
+	}
+	defer w.Stop()
+
+	w.Reload()
+	first := <-events
+	w.Reload()
+	w.Reload()
+	select {
+	case second := <-events:
+		if d := second.Sub(first); d < 90*time.Millisecond {
+			t.Errorf("TestWatchRateLimit - expected reloads at least 100ms apart, got: %s", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestWatchRateLimit - reload expected")
+	}
+	select {
+	case <-events:
+		t.Errorf("TestWatchRateLimit - expected coalesced reloads")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatchAcceptPartial(t *testing.T) {
+	var n int32
+	src := SourceFunc(func(ctx context.Context) (Properties, error) {
+		switch atomic.AddInt32(&n, 1) {
+		case 1:
+			return Properties{"port": "8080", "timeout": "1s"}, nil
+		case 2:
+			return Properties{"port": "http", "timeout": "2s", "debug": "yes"}, nil
+		}
+		return Properties{"port": "9090"}, nil
+	})
+	schema := &Schema{Keys: []KeySpec{
+		{Key: "port", Type: TypeInt, Required: true},
+		{Key: "timeout", Type: TypeDuration},
+		{Key: "debug", Type: TypeBool},
+	}}
+
+	type event struct {
+		p Properties
+		e error
+	}
+	events := make(chan event, 4)
+	w, e := Watch(context.Background(), src, 0, func(p Properties, e error) {
+		events <- event{p, e}
+	}, WithValidators(SchemaValidator(schema)), AcceptPartial())
+	if e != nil {
+		t.Fatalf("TestWatchAcceptPartial - Watch - %s", e)
+	}
+	defer w.Stop()
+
+	w.Reload()
+	ev := <-events
+	if !errors.Is(ev.e, ErrPartial) || !strings.Contains(ev.e.Error(), "key <port>") || !strings.Contains(ev.e.Error(), "key <debug>") {
+		t.Errorf("TestWatchAcceptPartial - Reload - expected partial error of port and debug, got: %v", ev.e)
+	}
+	expected := Properties{"port": "8080", "timeout": "2s"}
+	if !equal(ev.p, expected) || !equal(w.Properties(), expected) {
+		t.Errorf("TestWatchAcceptPartial - Properties - expected: %v, got: %v", expected, w.Properties())
+	}
+
+	w.Reload()
+	if ev := <-events; ev.e != nil || ev.p.GetString("port") != "9090" {
+		t.Errorf("TestWatchAcceptPartial - Reload - expected: port 9090, got: %v, %v", ev.p, ev.e)
+	}
+}
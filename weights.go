@@ -0,0 +1,49 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"math"
+)
+
+// Weighted map value property, e.g. for traffic splitting:
+//
+//	backends[:] = blue:0.9, green:0.1
+//
+// Returns error if no such key, not a map key, or a weight is not a
+// non-negative number. With the Normalize option, weights are scaled to
+// sum to 1.0, and their sum must be positive.
+func (p Properties) GetWeightedMap(key string, opts ...GetOption) (map[string]float64, error) {
+	m, e := p.mapValue(key)
+	if e != nil {
+		return nil, e
+	}
+
+	o := newGetOptions(opts)
+	weights := make(map[string]float64, len(m))
+	sum := 0.0
+	for _, mk := range sortedKeys(m) {
+		w, e := o.parseFloat(m[mk])
+		if e != nil {
+			return nil, wrongType("key <%s> - map key <%s> - %s", key, mk, e)
+		}
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, wrongType("key <%s> - map key <%s> - invalid weight %s", key, mk, m[mk])
+		}
+		weights[mk] = w
+		sum += w
+	}
+
+	if o.normalize {
+		if sum == 0 {
+			return nil, fmt.Errorf("key <%s> - weights sum to zero", key)
+		}
+		for mk := range weights {
+			weights[mk] /= sum
+		}
+	}
+	return weights, nil
+}
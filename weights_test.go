@@ -0,0 +1,35 @@
+package gestalt
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetWeightedMap(t *testing.T) {
+	p, _ := LoadStr(`
+backends[:] = blue:3, green:1
+negative[:] = a:1, b:-1
+nan[:] = a:x
+zero[:] = a:0
+`)
+	w, e := p.GetWeightedMap("backends[:]")
+	if e != nil || w["blue"] != 3 || w["green"] != 1 {
+		t.Errorf("TestGetWeightedMap - GetWeightedMap(backends[:]) - got: %v, %v", w, e)
+	}
+	w, e = p.GetWeightedMap("backends[:]", Normalize())
+	if e != nil || math.Abs(w["blue"]-0.75) > 1e-9 || math.Abs(w["green"]-0.25) > 1e-9 {
+		t.Errorf("TestGetWeightedMap - GetWeightedMap(backends[:], Normalize) - got: %v, %v", w, e)
+	}
+
+	for _, key := range []string{"negative[:]", "nan[:]", "nope[:]", "backends"} {
+		if _, e := p.GetWeightedMap(key); e == nil {
+			t.Errorf("TestGetWeightedMap - GetWeightedMap(%s) - error expected", key)
+		}
+	}
+	if _, e := p.GetWeightedMap("zero[:]"); e != nil {
+		t.Errorf("TestGetWeightedMap - GetWeightedMap(zero[:]) - unexpected error: %s", e)
+	}
+	if _, e := p.GetWeightedMap("zero[:]", Normalize()); e == nil {
+		t.Errorf("TestGetWeightedMap - GetWeightedMap(zero[:], Normalize) - error expected")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Time-windowed values
+//
+// Values may be restricted to a validity window, by a key suffix of the
+// form @<from>..<to>, e.g.
+//
+//	banner.msg = Welcome
+//	banner.msg@2024-12-01..2024-12-31 = Happy holidays
+//	maintenance@2024-06-01T02:00:00Z..2024-06-01T04:00:00Z = true
+//
+// Bounds are dates (UTC, and inclusive of the whole day) or RFC 3339
+// times, and either bound may be omitted (e.g. @2025-01-01..). Keys of
+// other suffixes, e.g. user@example..com, are plain keys. Windowed keys are
+// held as written (e.g. by Keys, and Store), and their values are resolved
+// at read time of the plain key, per the Clock of the load (see WithClock):
+// the value of the first window including the current time, or else the
+// value of the plain key, if any.
+// ----------------------------------------------------------------------
+
+const (
+	window_sep   = "@"
+	window_range = ".."
+	window_date  = "2006-01-02"
+)
+
+// window of a windowed key
+type window struct {
+	from, to time.Time // zero if unbounded
+	key      string    // the windowed key, e.g. banner.msg@2024-12-01..2024-12-31
+}
+
+// returns true if t is in w
+func (w window) includes(t time.Time) bool {
+	return (w.from.IsZero() || !t.Before(w.from)) && (w.to.IsZero() || t.Before(w.to))
+}
+
+// returns the plain key of key, without its window suffix, if any
+func plainKey(key string) string {
+	if plain, _, ok, _ := splitWindow(key); ok {
+		return plain
+	}
+	return key
+}
+
+// splits key into its plain key and window, if windowed.
+// ok is false if key has no window suffix, i.e. of two (possibly open)
+// bounds, so that keys such as `user@example..com` are plain keys.
+// Returns error if the window is empty.
+func splitWindow(key string) (plain string, w window, ok bool, e error) {
+	i := strings.LastIndex(key, window_sep)
+	if i < 0 || !strings.Contains(key[i:], window_range) {
+		return key, w, false, nil
+	}
+	spec := key[i+len(window_sep):]
+	bounds := strings.SplitN(spec, window_range, 2)
+	from, e1 := parseWindowBound(bounds[0], false)
+	to, e2 := parseWindowBound(bounds[1], true)
+	if e1 != nil || e2 != nil {
+		return key, w, false, nil
+	}
+	plain, w = strings.Trim(key[:i], ws), window{from: from, to: to, key: key}
+	if !w.from.IsZero() && !w.to.IsZero() && !w.from.Before(w.to) {
+		e = fmt.Errorf("window <%s> of key <%s> is empty", spec, plain)
+	}
+	return plain, w, true, e
+}
+
+// parses a window bound. dates of upper bounds include the whole day.
+func parseWindowBound(s string, upper bool) (time.Time, error) {
+	if s = strings.Trim(s, ws); s == empty {
+		return time.Time{}, nil
+	}
+	if t, e := time.Parse(window_date, s); e == nil {
+		if upper {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// adds window w of the plain key, per clock
+func (p Properties) addWindow(key string, w window, clock Clock) {
+	s := p.side()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.windows == nil {
+		s.windows = make(map[string][]window)
+	}
+	for _, ww := range s.windows[key] {
+		if ww.key == w.key {
+			return
+		}
+	}
+	s.clock = clock
+	s.windows[key] = append(s.windows[key], w)
+}
@@ -0,0 +1,116 @@
+package gestalt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWindowedValues(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC))
+	spec := `
+banner.msg = Welcome
+banner.msg@2024-12-01..2024-12-31 = Happy holidays
+banner.msg@2025-01-01.. = Happy new year
+maintenance@2024-11-30T10:00:00Z..2024-11-30T14:00:00Z = true
+limits[]@..2024-12-01 = 10, 20
+`
+	for _, opts := range [][]LoadOption{{WithClock(clock)}, {WithClock(clock), Lazy()}} {
+		p, e := LoadStr(spec, opts...)
+		if e != nil {
+			t.Fatalf("TestWindowedValues - LoadStr - %s", e)
+		}
+		clock.Set(time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC))
+		if v := p.GetString("banner.msg"); v != "Welcome" {
+			t.Errorf("TestWindowedValues - GetString(banner.msg) - expected: Welcome, got: %s", v)
+		}
+		if v, e := p.GetBool("maintenance"); e != nil || !v {
+			t.Errorf("TestWindowedValues - GetBool(maintenance) - expected: true, got: %t, %v", v, e)
+		}
+		if v := p.GetArray("limits[]"); len(v) != 2 {
+			t.Errorf("TestWindowedValues - GetArray(limits[]) - expected: [10 20], got: %v", v)
+		}
+
+		clock.Set(time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC))
+		if v := p.GetString("banner.msg"); v != "Happy holidays" {
+			t.Errorf("TestWindowedValues - GetString(banner.msg) - expected: Happy holidays, got: %s", v)
+		}
+		if _, e := p.GetBool("maintenance"); e == nil {
+			t.Errorf("TestWindowedValues - GetBool(maintenance) - expected no such key outside window")
+		}
+		if v := p.GetArray("limits[]"); v != nil {
+			t.Errorf("TestWindowedValues - GetArray(limits[]) - expected nil, got: %v", v)
+		}
+
+		clock.Set(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+		if v := p.GetString("banner.msg"); v != "Happy new year" {
+			t.Errorf("TestWindowedValues - GetString(banner.msg) - expected: Happy new year, got: %s", v)
+		}
+		if b, e := p.MarshalBinary(); e != nil || len(b) == 0 {
+			t.Errorf("TestWindowedValues - MarshalBinary - %v", e)
+		}
+	}
+
+	if _, e := LoadStr("a@2024-12-31..2024-12-01 = x\n"); e == nil {
+		t.Errorf("TestWindowedValues - LoadStr - error expected for empty window")
+	}
+	for _, k := range []string{"user@example.com", "user@example..com", "a@yesterday..today"} {
+		if p, e := LoadStr(k + " = x\n"); e != nil || p.GetString(k) != "x" {
+			t.Errorf("TestWindowedValues - keys with @ and no window are plain keys - %s: %v", k, e)
+		}
+	}
+}
+
+func TestWindowedBinaryRoundTrip(t *testing.T) {
+	p, _ := LoadStr("banner.msg = Welcome\nbanner.msg@2000-01-01..2999-12-31 = Holidays\n")
+	b, e := p.MarshalBinary()
+	if e != nil {
+		t.Fatalf("TestWindowedBinaryRoundTrip - MarshalBinary - %s", e)
+	}
+	var q Properties
+	if e := q.UnmarshalBinary(b); e != nil {
+		t.Fatalf("TestWindowedBinaryRoundTrip - UnmarshalBinary - %s", e)
+	}
+	if v := q.GetString("banner.msg"); v != "Holidays" {
+		t.Errorf("TestWindowedBinaryRoundTrip - GetString(banner.msg) - expected: Holidays, got: %s", v)
+	}
+}
+
+func TestWindowedKeysAsWritten(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC))
+	spec := "banner.msg = Welcome\nbanner.msg@2024-12-01..2024-12-31 = Happy holidays\n"
+	p, e := LoadStr(spec, WithClock(clock))
+	if e != nil {
+		t.Fatalf("TestWindowedKeysAsWritten - LoadStr - %s", e)
+	}
+	for k, v := range p {
+		if _, ok := v.(string); !ok {
+			t.Errorf("TestWindowedKeysAsWritten - value of %s - expected: string, got: %T", k, v)
+		}
+	}
+	if v := p["banner.msg"]; v != "Welcome" {
+		t.Errorf("TestWindowedKeysAsWritten - p[banner.msg] - expected: Welcome, got: %v", v)
+	}
+
+	var buf bytes.Buffer
+	if e := p.Store(&buf); e != nil || buf.String() != spec {
+		t.Errorf("TestWindowedKeysAsWritten - Store - expected:\n%s\ngot:\n%s (%v)", spec, buf.String(), e)
+	}
+	for name, q := range map[string]Properties{"Clone": p.Clone(), "Project": p.Project("banner.msg"), "Sub": p.sub("banner")} {
+		key := "banner.msg"
+		if name == "Sub" {
+			key = "msg"
+		}
+		if v := q.GetString(key); v != "Happy holidays" {
+			t.Errorf("TestWindowedKeysAsWritten - %s - GetString(%s) - expected: Happy holidays, got: %s", name, key, v)
+		}
+	}
+}
+
+func TestWindowedKeysStrict(t *testing.T) {
+	schema := &Schema{Strict: true, Keys: []KeySpec{{Key: "banner.msg"}}}
+	p, _ := LoadStr("banner.msg@2024-12-01..2024-12-31 = Happy holidays\n")
+	if errs := schema.Validate(p); errs != nil {
+		t.Errorf("TestWindowedKeysStrict - Validate - expected windowed keys described by the spec of their plain key, got: %v", errs)
+	}
+}
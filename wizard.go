@@ -0,0 +1,84 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Writes a starter configuration file for the schema to w, in gestalt
+// syntax. Each key is preceded by comments describing its doc, type, and
+// default. Values are obtained from pr, if not nil (e.g. TTYPrompter), or
+// else the key's default. Keys without a value are written commented out.
+//
+// Returns a *KeyError if a value obtained from pr is not valid per schema.
+func (s *Schema) WriteStarter(w io.Writer, pr Prompter) error {
+	bw := bufio.NewWriter(w)
+	for i := range s.Keys {
+		spec := &s.Keys[i]
+
+		vrep := ""
+		if pr != nil {
+			answer, e := pr.Prompt(spec)
+			if e != nil {
+				return &KeyError{spec.Key, e}
+			}
+			vrep = strings.Trim(answer, trimset)
+		}
+		if vrep == empty {
+			vrep = spec.Default
+		}
+		if vrep != empty {
+			v, e := parseValue(spec.Key, vrep)
+			if e != nil {
+				return &KeyError{spec.Key, e}
+			}
+			if e := spec.validate(v); e != nil {
+				return &KeyError{spec.Key, e}
+			}
+		}
+
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		for _, line := range strings.Split(spec.Doc, "\n") {
+			if line = strings.Trim(line, trimset); line != empty {
+				fmt.Fprintf(bw, "# %s\n", line)
+			}
+		}
+		fmt.Fprintf(bw, "# type: %s", spec.typeName())
+		if spec.Unit != "" {
+			fmt.Fprintf(bw, ", unit: %s", spec.Unit)
+		}
+		if spec.Required {
+			fmt.Fprint(bw, ", required")
+		}
+		if spec.Secret {
+			fmt.Fprint(bw, ", secret")
+		}
+		if spec.Default != empty {
+			fmt.Fprintf(bw, ", default: %s", spec.Default)
+		}
+		fmt.Fprintln(bw)
+
+		if vrep == empty {
+			fmt.Fprintf(bw, "# %s =\n", spec.Key)
+		} else {
+			fmt.Fprintf(bw, "%s = %s\n", spec.Key, vrep)
+		}
+	}
+	return bw.Flush()
+}
+
+// returns the schema type of the spec'd key
+func (spec *KeySpec) typeName() string {
+	if spec.Type == empty {
+		return TypeString
+	}
+	return spec.Type
+}
@@ -0,0 +1,47 @@
+package gestalt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteStarter(t *testing.T) {
+	schema := &Schema{Keys: []KeySpec{
+		{Key: "db.host", Required: true, Doc: "host of the database server"},
+		{Key: "db.port", Type: TypeInt, Default: "5432"},
+		{Key: "db.password", Secret: true},
+	}}
+
+	var buf bytes.Buffer
+	if e := schema.WriteStarter(&buf, fakePrompter{"db.host": "localhost"}); e != nil {
+		t.Fatalf("TestWriteStarter - WriteStarter - %s", e)
+	}
+	expected := `# host of the database server
+# type: string, required
+db.host = localhost
+
+# type: int, default: 5432
+db.port = 5432
+
+# type: string, secret
+# db.password =
+`
+	if buf.String() != expected {
+		t.Errorf("TestWriteStarter - WriteStarter - expected: %q, got: %q", expected, buf.String())
+	}
+
+	p, e := LoadStr(buf.String())
+	if e != nil {
+		t.Fatalf("TestWriteStarter - LoadStr - %s", e)
+	}
+	if errs := schema.Validate(p); len(errs) > 0 {
+		t.Errorf("TestWriteStarter - Validate - expected valid, got: %s", errs)
+	}
+
+	buf.Reset()
+	e = schema.WriteStarter(&buf, fakePrompter{"db.port": "five"})
+	if e == nil || !strings.Contains(e.Error(), "db.port") {
+		t.Errorf("TestWriteStarter - WriteStarter - expected error for db.port, got: %v", e)
+	}
+}
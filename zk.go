@@ -0,0 +1,141 @@
+// Copyright 2012-2015 Joubin Houshyar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gestalt
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ZKConn is the subset of a ZooKeeper connection used by the zookeeper
+// provider. Clients of choice (e.g. go-zookeeper) are easily adapted to it.
+//
+// Both methods set one-shot watches, per ZooKeeper semantics, and the
+// returned channels are signaled (or closed) when the watch fires.
+type ZKConn interface {
+	// returns the child names of the znode at path (e.g. ChildrenW)
+	ChildrenW(path string) ([]string, <-chan struct{}, error)
+	// returns the data of the znode at path (e.g. GetW)
+	GetW(path string) ([]byte, <-chan struct{}, error)
+}
+
+// Returns a Source loading the znode subtree at root. Each znode with
+// (non-empty) data defines a key named by its path relative to root, with
+// `/` replaced by `.`, e.g. root/db/host defines "db.host". Data is parsed
+// per key type and gestalt file syntax.
+func ZKSource(conn ZKConn, root string) Source {
+	return newZKSource(conn, root)
+}
+
+// Watches the znode subtree at root (see ZKSource), reloading it when any
+// of its znodes change. Failed reloads are retried on the next change, or
+// poll (if positive), whichever comes first. See Watch for the semantics
+// of fn.
+func WatchZK(ctx context.Context, conn ZKConn, root string, poll time.Duration, fn ChangeFunc) (*Watcher, error) {
+	src := newZKSource(conn, root)
+	w, e := Watch(ctx, src, poll, fn)
+	if e != nil {
+		return nil, e
+	}
+	go func() {
+		done := reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(w.done)}
+		for {
+			select {
+			case <-src.loaded:
+			case <-w.done:
+				return
+			}
+			cases := []reflect.SelectCase{done}
+			for _, ch := range src.takeWatches() {
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)})
+			}
+			if len(cases) == 1 {
+				continue
+			}
+			if i, _, _ := reflect.Select(cases); i == 0 {
+				return
+			}
+			w.Reload()
+		}
+	}()
+	return w, nil
+}
+
+type zkSource struct {
+	conn ZKConn
+	root string
+
+	// signaled after every load
+	loaded chan struct{}
+
+	mu      sync.Mutex
+	watches []<-chan struct{}
+}
+
+func newZKSource(conn ZKConn, root string) *zkSource {
+	return &zkSource{conn: conn, root: path.Clean(root), loaded: make(chan struct{}, 1)}
+}
+
+func (s *zkSource) Load(ctx context.Context) (p Properties, e error) {
+	var watches []<-chan struct{}
+	defer func() {
+		s.mu.Lock()
+		s.watches = watches
+		s.mu.Unlock()
+		select {
+		case s.loaded <- struct{}{}:
+		default:
+		}
+	}()
+
+	p = make(Properties)
+	var walk func(zpath string) error
+	walk = func(zpath string) error {
+		if e := ctx.Err(); e != nil {
+			return e
+		}
+		data, dw, e := s.conn.GetW(zpath)
+		if e != nil {
+			return e
+		}
+		children, cw, e := s.conn.ChildrenW(zpath)
+		if e != nil {
+			return e
+		}
+		watches = append(watches, dw, cw)
+
+		if vrep := strings.Trim(string(data), trimset); vrep != empty && zpath != s.root {
+			k := strings.Replace(strings.TrimPrefix(zpath, s.root+"/"), "/", ".", -1)
+			v, e := parseValue(k, vrep)
+			if e != nil {
+				return &KeyError{k, e}
+			}
+			p[k] = v
+		}
+		for _, child := range children {
+			if e := walk(path.Join(zpath, child)); e != nil {
+				return e
+			}
+		}
+		return nil
+	}
+	if e = walk(s.root); e != nil {
+		return nil, e
+	}
+	return p, nil
+}
+
+// returns the watches of the last load
+func (s *zkSource) takeWatches() []<-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watches := s.watches
+	s.watches = nil
+	return watches
+}
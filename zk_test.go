@@ -0,0 +1,94 @@
+package gestalt
+
+import (
+	"context"
+	"path"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// an in-memory ZKConn
+type fakeZK struct {
+	mu      sync.Mutex
+	nodes   map[string]string
+	watches []chan struct{}
+}
+
+func (z *fakeZK) watch() <-chan struct{} {
+	ch := make(chan struct{})
+	z.watches = append(z.watches, ch)
+	return ch
+}
+
+func (z *fakeZK) ChildrenW(zpath string) ([]string, <-chan struct{}, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	var children []string
+	for p := range z.nodes {
+		if path.Dir(p) == zpath {
+			children = append(children, path.Base(p))
+		}
+	}
+	sort.Strings(children)
+	return children, z.watch(), nil
+}
+
+func (z *fakeZK) GetW(zpath string) ([]byte, <-chan struct{}, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	return []byte(z.nodes[zpath]), z.watch(), nil
+}
+
+func (z *fakeZK) set(zpath, data string) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.nodes[zpath] = data
+	for _, ch := range z.watches {
+		close(ch)
+	}
+	z.watches = nil
+}
+
+func TestZKSource(t *testing.T) {
+	zk := &fakeZK{nodes: map[string]string{
+		"/app":                  "",
+		"/app/config":           "",
+		"/app/config/db":        "",
+		"/app/config/db/host":   "localhost",
+		"/app/config/hosts[]":   "a, b",
+		"/app/config/log":       "",
+		"/app/config/log/level": "INFO",
+	}}
+	p, e := ZKSource(zk, "/app/config").Load(context.Background())
+	if e != nil {
+		t.Fatalf("TestZKSource - Load - %s", e)
+	}
+	if v := p.GetString("db.host"); v != "localhost" {
+		t.Errorf("TestZKSource - GetString(db.host) - expected: localhost, got: %s", v)
+	}
+	if v := p.GetArray("hosts[]"); len(v) != 2 {
+		t.Errorf("TestZKSource - GetArray(hosts[]) - expected: [a b], got: %s", v)
+	}
+	if len(p) != 3 {
+		t.Errorf("TestZKSource - Load - expected: 3 keys, got: %s", p)
+	}
+
+	changes := make(chan Properties, 1)
+	w, e := WatchZK(context.Background(), zk, "/app/config", 0, func(p Properties, e error) { changes <- p })
+	if e != nil {
+		t.Fatalf("TestZKSource - WatchZK - %s", e)
+	}
+	defer w.Stop()
+
+	zk.set("/app/config/log/level", "DEBUG")
+	select {
+	case p := <-changes:
+		if v := p.GetString("log.level"); v != "DEBUG" {
+			t.Errorf("TestZKSource - change - expected: log.level=DEBUG, got: %s", v)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("TestZKSource - change notification expected")
+	}
+}